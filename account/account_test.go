@@ -12,14 +12,14 @@ import (
 var Mnemonic = os.Getenv("WalletSdkTestM1")
 
 func TestMyAccouunt(t *testing.T) {
-	account, err := NewAccountWithMnemonic(Mnemonic)
+	account, err := NewAccountWithMnemonic(Mnemonic, 0)
 	require.Nil(t, err)
 
 	t.Logf("addr = %v", account.Address)
 }
 
 func Test_Signature_Marshal_Unmarshal(t *testing.T) {
-	account, err := NewAccountWithMnemonic(Mnemonic)
+	account, err := NewAccountWithMnemonic(Mnemonic, 0)
 	require.Nil(t, err)
 
 	msg := "Coming chat is very good jopfpzf"
@@ -17,21 +17,33 @@ import (
 var (
 	M1Mnemonic = os.Getenv("WalletSdkTestM1")
 	Address, _ = sui_types.NewAddressFromHex("0x7e875ea78ee09f08d72e2676cf84e0f1c8ac61d94fa339cc8e37cace85bebc6e")
+	// NetworkTests runs the tests against the sui networks and faucets, the others use a mock node.
+	NetworkTests = os.Getenv("WalletSdkTestNetwork") != ""
 )
 
+// RequireNetwork skips the test unless WalletSdkTestNetwork is set, and in short mode.
+func RequireNetwork(t *testing.T) {
+	if testing.Short() || !NetworkTests {
+		t.Skip("set WalletSdkTestNetwork to run the tests against the sui networks")
+	}
+}
+
 func MainnetClient(t *testing.T) *Client {
+	RequireNetwork(t)
 	c, err := Dial(types.MainnetRpcUrl)
 	require.NoError(t, err)
 	return c
 }
 
 func TestnetClient(t *testing.T) *Client {
+	RequireNetwork(t)
 	c, err := Dial(types.TestnetRpcUrl)
 	require.NoError(t, err)
 	return c
 }
 
 func DevnetClient(t *testing.T) *Client {
+	RequireNetwork(t)
 	c, err := Dial(types.DevNetRpcUrl)
 	require.NoError(t, err)
 
@@ -59,7 +71,7 @@ func ChainClient(t *testing.T) *Client {
 }
 
 func M1Account(t *testing.T) *account.Account {
-	a, err := account.NewAccountWithMnemonic(M1Mnemonic)
+	a, err := account.NewAccountWithMnemonic(M1Mnemonic, 0)
 	require.NoError(t, err)
 	return a
 }
@@ -16,7 +16,7 @@ func TestAccountSignAndSend(t *testing.T) {
 func ManualTest_AccountSignAndSend(t *testing.T) {
 	unsafeMnemonic := M1Mnemonic
 
-	account, err := account.NewAccountWithMnemonic(unsafeMnemonic)
+	account, err := account.NewAccountWithMnemonic(unsafeMnemonic, 0)
 	require.Nil(t, err)
 	t.Log(account.Address)
 
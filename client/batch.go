@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+var ErrBatchNotSent = errors.New("batch has not been sent")
+
+// Batch collects typed calls sent together by SendBatch.
+//
+//	batch := client.NewBatch()
+//	gasPrice := client.BatchGetReferenceGasPrice(batch)
+//	object := client.BatchGetObject(batch, objectId, options)
+//	supply := client.AddBatchCall[types.Supply](batch, client.SuiXMethod("getTotalSupply"), coinType)
+//	err := cli.SendBatch(ctx, batch)
+//	price, err := gasPrice.Get()
+type Batch struct {
+	elems []BatchElem
+	// answered is the number of first elements which have their result.
+	answered int
+}
+
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+func (b *Batch) Len() int {
+	return len(b.elems)
+}
+
+// BatchResult is the result of a call added to a Batch.
+type BatchResult[T any] struct {
+	batch *Batch
+	index int
+	value T
+}
+
+// AddBatchCall adds a call of method to b, its result is decoded into a T.
+func AddBatchCall[T any](b *Batch, method Method, args ...interface{}) *BatchResult[T] {
+	result := &BatchResult[T]{batch: b, index: len(b.elems)}
+	b.elems = append(
+		b.elems, BatchElem{
+			Method: method.String(),
+			Args:   args,
+			Result: &result.value,
+		},
+	)
+	return result
+}
+
+// Get returns the result of the call, or its error. Before the batch is sent, or when it failed
+// before the call was answered, the error is ErrBatchNotSent.
+func (r *BatchResult[T]) Get() (T, error) {
+	if r.index >= r.batch.answered {
+		var zero T
+		return zero, ErrBatchNotSent
+	}
+	if err := r.batch.elems[r.index].Error; err != nil {
+		var zero T
+		return zero, err
+	}
+	return r.value, nil
+}
+
+// SendBatch sends the calls of b with BatchCallContext, the error is only set for transport
+// failures, the error of each call is returned by its Get. When a batch fails after others were
+// answered, the answered calls keep their results and the error is a *PartialBatchError.
+func (c *Client) SendBatch(ctx context.Context, b *Batch) error {
+	err := c.BatchCallContext(ctx, b.elems)
+	var partialErr *PartialBatchError
+	switch {
+	case err == nil:
+		b.answered = len(b.elems)
+	case errors.As(err, &partialErr):
+		b.answered = partialErr.Answered
+	}
+	return err
+}
+
+func BatchGetObject(
+	b *Batch,
+	objID suiObjectID,
+	options *types.SuiObjectDataOptions,
+) *BatchResult[types.SuiObjectResponse] {
+	return AddBatchCall[types.SuiObjectResponse](b, getObject, objID, options)
+}
+
+func BatchGetTransactionBlock(
+	b *Batch,
+	digest suiDigest,
+	options types.SuiTransactionBlockResponseOptions,
+) *BatchResult[types.SuiTransactionBlockResponse] {
+	return AddBatchCall[types.SuiTransactionBlockResponse](b, getTransactionBlock, digest, options)
+}
+
+func BatchGetBalance(b *Batch, owner suiAddress, coinType string) *BatchResult[types.Balance] {
+	if coinType == "" {
+		return AddBatchCall[types.Balance](b, getBalance, owner)
+	}
+	return AddBatchCall[types.Balance](b, getBalance, owner, coinType)
+}
+
+func BatchGetReferenceGasPrice(b *Batch) *BatchResult[types.SafeSuiBigInt[uint64]] {
+	return AddBatchCall[types.SafeSuiBigInt[uint64]](b, getReferenceGasPrice)
+}
@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// batchNode answers batches in reverse order, drops the calls to "drop" and rejects batches larger
+// than its limit as a whole, with rejection or else with the HTTP status when it is set. The batches
+// after the first failAfter fail with a 503 when it is set.
+type batchNode struct {
+	limit     int
+	rejection *RPCError
+	status    int
+	failAfter int
+
+	lock  sync.Mutex
+	sizes []int
+}
+
+func (n *batchNode) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var msgs []jsonrpcMessage
+	if err := json.NewDecoder(r.Body).Decode(&msgs); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	n.lock.Lock()
+	n.sizes = append(n.sizes, len(msgs))
+	failed := n.failAfter > 0 && len(n.sizes) > n.failAfter
+	n.lock.Unlock()
+	if failed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if n.limit > 0 && len(msgs) > n.limit {
+		if n.status != 0 {
+			w.WriteHeader(n.status)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(jsonrpcMessage{Version: vsn, ID: null, Error: n.rejection})
+		return
+	}
+	var resps []jsonrpcMessage
+	for i := len(msgs) - 1; i >= 0; i-- {
+		msg := msgs[i]
+		resp := jsonrpcMessage{Version: vsn, ID: msg.ID}
+		switch msg.Method {
+		case SuiXMethod("drop").String():
+			continue
+		case getReferenceGasPrice.String():
+			resp.Result = json.RawMessage(`"1000"`)
+		case SuiXMethod("fail").String():
+			resp.Error = &RPCError{Code: RPCErrorInvalidParams, Message: "invalid params"}
+		default:
+			var params []string
+			_ = json.Unmarshal(msg.Params, &params)
+			resp.Result, _ = json.Marshal(params[0])
+		}
+		resps = append(resps, resp)
+	}
+	_ = json.NewEncoder(w).Encode(resps)
+}
+
+func (n *batchNode) batchSizes() []int {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.sizes
+}
+
+func newBatchNode(t *testing.T, limit int, options ...ClientOption) (*batchNode, *Client) {
+	node := &batchNode{
+		limit:     limit,
+		rejection: &RPCError{Code: RPCErrorBatchTooLarge, Message: "The batch request was too large"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(node.serveHTTP))
+	t.Cleanup(server.Close)
+	cli, err := Dial(server.URL, options...)
+	require.NoError(t, err)
+	return node, cli
+}
+
+func TestBatchCallContext_MatchesByID(t *testing.T) {
+	_, cli := newBatchNode(t, 0)
+	var first, second string
+	batch := []BatchElem{
+		{Method: SuiXMethod("echo").String(), Args: []interface{}{"first"}, Result: &first},
+		{Method: SuiXMethod("drop").String(), Args: []interface{}{"dropped"}, Result: new(string)},
+		{Method: SuiXMethod("echo").String(), Args: []interface{}{"second"}, Result: &second},
+		{Method: SuiXMethod("fail").String(), Result: new(string)},
+	}
+	require.NoError(t, cli.BatchCallContext(context.Background(), batch))
+	require.NoError(t, batch[0].Error)
+	require.Equal(t, "first", first)
+	require.ErrorIs(t, batch[1].Error, ErrMissingBatchResponse)
+	require.NoError(t, batch[2].Error)
+	require.Equal(t, "second", second)
+	var rpcErr *RPCError
+	require.ErrorAs(t, batch[3].Error, &rpcErr)
+	require.Equal(t, RPCErrorInvalidParams, rpcErr.Code)
+}
+
+func TestBatchCallContext_Split(t *testing.T) {
+	node, cli := newBatchNode(t, 3, WithMaxBatchSize(4))
+	results := make([]string, 10)
+	batch := make([]BatchElem, len(results))
+	for i := range batch {
+		batch[i] = BatchElem{Method: SuiXMethod("echo").String(), Args: []interface{}{fmt.Sprint(i)}, Result: &results[i]}
+	}
+	require.NoError(t, cli.BatchCallContext(context.Background(), batch))
+	for i := range batch {
+		require.NoError(t, batch[i].Error)
+		require.Equal(t, fmt.Sprint(i), results[i])
+	}
+	// 4 is rejected and halved, the last 2 fit
+	require.Equal(t, []int{4, 2, 2, 4, 2, 2, 2}, node.batchSizes())
+
+	node, cli = newBatchNode(t, 0, WithMaxBatchSize(0))
+	require.NoError(t, cli.BatchCallContext(context.Background(), batch))
+	require.Equal(t, []int{10}, node.batchSizes())
+
+	t.Run("http 413", func(t *testing.T) {
+		node, cli := newBatchNode(t, 3, WithMaxBatchSize(4))
+		node.status = http.StatusRequestEntityTooLarge
+		require.NoError(t, cli.BatchCallContext(context.Background(), batch[:4]))
+		require.Equal(t, []int{4, 2, 2}, node.batchSizes())
+		require.Equal(t, "3", results[3])
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		// a batch rejected for another reason than its size is not split
+		node, cli := newBatchNode(t, 3, WithMaxBatchSize(4))
+		node.rejection = &RPCError{Code: -32600, Message: "Invalid request"}
+		err := cli.BatchCallContext(context.Background(), batch[:4])
+		var rpcErr *RPCError
+		require.ErrorAs(t, err, &rpcErr)
+		require.Equal(t, -32600, rpcErr.Code)
+		require.Equal(t, []int{4}, node.batchSizes())
+
+		node, cli = newBatchNode(t, 3, WithMaxBatchSize(4))
+		node.status = http.StatusServiceUnavailable
+		err = cli.BatchCallContext(context.Background(), batch[:4])
+		var httpErr HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, []int{4}, node.batchSizes())
+	})
+}
+
+func TestBatch(t *testing.T) {
+	_, cli := newBatchNode(t, 0)
+	batch := NewBatch()
+	gasPrice := BatchGetReferenceGasPrice(batch)
+	echo := AddBatchCall[string](batch, SuiXMethod("echo"), "hello")
+	failed := AddBatchCall[string](batch, SuiXMethod("fail"))
+	require.Equal(t, 3, batch.Len())
+
+	_, err := gasPrice.Get()
+	require.ErrorIs(t, err, ErrBatchNotSent)
+
+	require.NoError(t, cli.SendBatch(context.Background(), batch))
+	price, err := gasPrice.Get()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000), price.Uint64())
+	value, err := echo.Get()
+	require.NoError(t, err)
+	require.Equal(t, "hello", value)
+	_, err = failed.Get()
+	require.Error(t, err)
+}
+
+func TestBatch_PartialFailure(t *testing.T) {
+	node, cli := newBatchNode(t, 0, WithMaxBatchSize(2))
+	node.failAfter = 1
+	batch := NewBatch()
+	var results []*BatchResult[string]
+	for i := 0; i < 5; i++ {
+		results = append(results, AddBatchCall[string](batch, SuiXMethod("echo"), fmt.Sprint(i)))
+	}
+
+	err := cli.SendBatch(context.Background(), batch)
+	var partialErr *PartialBatchError
+	require.ErrorAs(t, err, &partialErr)
+	require.Equal(t, 2, partialErr.Answered)
+	var httpErr HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+	for i, result := range results {
+		value, err := result.Get()
+		if i < 2 {
+			require.NoError(t, err)
+			require.Equal(t, fmt.Sprint(i), value)
+		} else {
+			require.ErrorIs(t, err, ErrBatchNotSent)
+		}
+	}
+	require.Equal(t, []int{2, 2}, node.batchSizes())
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+type checkpointFollowerConfig struct {
+	pageSize     uint
+	pollInterval time.Duration
+}
+
+type CheckpointFollowerOption func(*checkpointFollowerConfig)
+
+// WithCheckpointPageSize sets the limit of every GetCheckpoints call, 50 by default. A size of 0
+// keeps the default.
+func WithCheckpointPageSize(size uint) CheckpointFollowerOption {
+	return func(c *checkpointFollowerConfig) {
+		if size > 0 {
+			c.pageSize = size
+		}
+	}
+}
+
+// WithCheckpointPollInterval sets how long the follower waits for new checkpoints at the chain tip.
+func WithCheckpointPollInterval(interval time.Duration) CheckpointFollowerOption {
+	return func(c *checkpointFollowerConfig) {
+		c.pollInterval = interval
+	}
+}
+
+// CheckpointFollower returns the checkpoints in ascending order from a start sequence number
+// and waits for new ones once it reached the chain tip.
+type CheckpointFollower struct {
+	client *Client
+	config checkpointFollowerConfig
+
+	next   types.CheckpointSequenceNumber
+	buffer []types.Checkpoint
+}
+
+// FollowCheckpoints starts following the chain at the checkpoint start.
+func (c *Client) FollowCheckpoints(
+	start types.CheckpointSequenceNumber,
+	options ...CheckpointFollowerOption,
+) *CheckpointFollower {
+	config := checkpointFollowerConfig{
+		pageSize:     50,
+		pollInterval: time.Second,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+	return &CheckpointFollower{client: c, config: config, next: start}
+}
+
+// Next blocks until the next checkpoint is available or ctx is done. A failed
+// GetCheckpoints call is returned and retried on the following call.
+func (f *CheckpointFollower) Next(ctx context.Context) (*types.Checkpoint, error) {
+	for len(f.buffer) == 0 {
+		if err := f.fetch(ctx); err != nil {
+			return nil, err
+		}
+		if len(f.buffer) > 0 {
+			break
+		}
+		if err := sleepContext(ctx, f.config.pollInterval); err != nil {
+			return nil, err
+		}
+	}
+	checkpoint := f.buffer[0]
+	f.buffer = f.buffer[1:]
+	f.next = checkpoint.SequenceNumber.Uint64() + 1
+	return &checkpoint, nil
+}
+
+// NextSequenceNumber returns the sequence number of the checkpoint Next returns, it can be
+// persisted to resume following later.
+func (f *CheckpointFollower) NextSequenceNumber() types.CheckpointSequenceNumber {
+	return f.next
+}
+
+func (f *CheckpointFollower) fetch(ctx context.Context) error {
+	// the cursor is exclusive, a nil cursor starts from the genesis checkpoint
+	var cursor *types.SafeSuiBigInt[types.CheckpointSequenceNumber]
+	if f.next > 0 {
+		prev := types.NewSafeSuiBigInt(f.next - 1)
+		cursor = &prev
+	}
+	page, err := f.client.GetCheckpoints(ctx, cursor, &f.config.pageSize, false)
+	if err != nil {
+		return err
+	}
+	for _, checkpoint := range page.Data {
+		if checkpoint.SequenceNumber.Uint64() >= f.next {
+			f.buffer = append(f.buffer, checkpoint)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+type checkpointChain struct {
+	lock  sync.Mutex
+	count uint64
+}
+
+func (c *checkpointChain) advance(n uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.count += n
+}
+
+func (c *checkpointChain) getCheckpoints(params []json.RawMessage) (interface{}, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var cursor *types.SafeSuiBigInt[types.CheckpointSequenceNumber]
+	var limit uint
+	_ = json.Unmarshal(params[0], &cursor)
+	_ = json.Unmarshal(params[1], &limit)
+	start := uint64(0)
+	if cursor != nil {
+		start = cursor.Uint64() + 1
+	}
+	page := types.CheckpointPage{Data: []types.Checkpoint{}}
+	for seq := start; seq < c.count && seq < start+uint64(limit); seq++ {
+		page.Data = append(page.Data, types.Checkpoint{SequenceNumber: types.NewSafeSuiBigInt(seq)})
+	}
+	if len(page.Data) > 0 {
+		page.NextCursor = &page.Data[len(page.Data)-1].SequenceNumber
+		page.HasNextPage = page.NextCursor.Uint64()+1 < c.count
+	}
+	return page, nil
+}
+
+func TestCheckpointFollower_FollowsTip(t *testing.T) {
+	chain := &checkpointChain{count: 5}
+	standIn := newRpcStandIn(t, map[string]rpcHandler{getCheckpoints.String(): chain.getCheckpoints})
+	cli := standIn.client(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	follower := cli.FollowCheckpoints(3, WithCheckpointPageSize(2), WithCheckpointPollInterval(10*time.Millisecond))
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		chain.advance(3)
+	}()
+	var seqs []uint64
+	for len(seqs) < 5 {
+		checkpoint, err := follower.Next(ctx)
+		require.NoError(t, err)
+		seqs = append(seqs, checkpoint.SequenceNumber.Uint64())
+	}
+	require.Equal(t, []uint64{3, 4, 5, 6, 7}, seqs)
+	require.Equal(t, uint64(8), follower.NextSequenceNumber())
+
+	short, cancelShort := context.WithTimeout(ctx, 30*time.Millisecond)
+	defer cancelShort()
+	_, err := follower.Next(short)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	page, err := cli.GetCheckpointsIterator(false, WithIteratorPageSize(3)).Collect(ctx)
+	require.NoError(t, err)
+	require.Len(t, page, 8)
+}
+
+func TestCheckpointFollower_ZeroPageSize(t *testing.T) {
+	chain := &checkpointChain{count: 3}
+	standIn := newRpcStandIn(t, map[string]rpcHandler{getCheckpoints.String(): chain.getCheckpoints})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// a zero page size keeps the default instead of asking for empty pages
+	follower := standIn.client(t).FollowCheckpoints(0, WithCheckpointPageSize(0))
+	for seq := uint64(0); seq < 3; seq++ {
+		checkpoint, err := follower.Next(ctx)
+		require.NoError(t, err)
+		require.Equal(t, seq, checkpoint.SequenceNumber.Uint64())
+	}
+	require.Equal(t, 1, standIn.callCount(getCheckpoints.String()))
+}
@@ -11,14 +11,33 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/thorli9527/sui-wallet-sdk/types"
 )
 
 var (
-	ErrNoResult = errors.New("no result in JSON-RPC response")
+	ErrNoResult             = errors.New("no result in JSON-RPC response")
+	ErrMissingBatchResponse = errors.New("no response for the call in the JSON-RPC batch response")
 )
 
+// PartialBatchError is returned by BatchCallContext when a batch fails after the batches before it
+// were answered. The first Answered elements have their results.
+type PartialBatchError struct {
+	Answered int
+	Err      error
+}
+
+func (err *PartialBatchError) Error() string {
+	return fmt.Sprintf("batch failed after %d answered calls: %v", err.Answered, err.Err)
+}
+
+func (err *PartialBatchError) Unwrap() error {
+	return err.Err
+}
+
 // BatchElem is an element in a batch request.
 type BatchElem struct {
 	Method string
@@ -35,26 +54,37 @@ type BatchElem struct {
 type Client struct {
 	idCounter uint32
 
-	rpcUrl string
-	client *http.Client
+	rpcUrl       string
+	client       *http.Client
+	handler      Handler
+	maxBatchSize int
+	metrics      Metrics
+	tracer       Tracer
+
+	metadataLock sync.Mutex
+	coinMetadata map[string]*types.SuiCoinMetadata
 }
 
-func Dial(rpcUrl string) (client *Client, err error) {
-	hc := &http.Client{
+func Dial(rpcUrl string, options ...ClientOption) (client *Client, err error) {
+	return DialWithClient(rpcUrl, newHTTPClient(), options...)
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{
 		Transport: &http.Transport{
 			MaxIdleConns:    3,
 			IdleConnTimeout: 30 * time.Second,
 		},
 		Timeout: 30 * time.Second,
 	}
-	return DialWithClient(rpcUrl, hc)
 }
 
-func DialWithClient(rpcUrl string, c *http.Client) (client *Client, err error) {
+func DialWithClient(rpcUrl string, c *http.Client, options ...ClientOption) (client *Client, err error) {
 	client = &Client{
 		rpcUrl: strings.TrimRight(rpcUrl, "/"),
 		client: c,
 	}
+	newClientConfig(options).apply(client, client.send)
 	return
 }
 
@@ -73,22 +103,24 @@ func (c *Client) Call(result interface{}, method Method, args ...interface{}) er
 //
 // The result must be a pointer so that package json can unmarshal into it. You
 // can also pass nil, in which case the result is ignored.
-func (c *Client) CallContext(ctx context.Context, result interface{}, method Method, args ...interface{}) error {
+func (c *Client) CallContext(ctx context.Context, result interface{}, method Method, args ...interface{}) (err error) {
 	if result != nil && reflect.TypeOf(result).Kind() != reflect.Ptr {
 		return fmt.Errorf("call result parameter must be pointer or nil interface: %v", result)
 	}
+	ctx, end := c.startCall(ctx, method.String(), args)
+	defer func() { end(err) }()
+
 	msg, err := c.newMessage(method.String(), args...)
 	if err != nil {
 		return err
 	}
-	respBody, err := c.doRequest(ctx, msg)
+	respBody, err := c.doRequest(ctx, msg, []string{msg.Method})
 	if err != nil {
 		return err
 	}
-	defer respBody.Close()
 
 	var respmsg jsonrpcMessage
-	if err := json.NewDecoder(respBody).Decode(&respmsg); err != nil {
+	if err := json.Unmarshal(respBody, &respmsg); err != nil {
 		return err
 	}
 	if respmsg.Error != nil {
@@ -106,33 +138,120 @@ func (c *Client) BatchCall(b []BatchElem) error {
 	return c.BatchCallContext(context.Background(), b)
 }
 
-// BatchCallContext sends all given requests as a single batch and waits for the server
-// to return a response for all of them. The wait duration is bounded by the
-// context's deadline.
-func (c *Client) BatchCallContext(ctx context.Context, b []BatchElem) error {
+// BatchCallContext sends all given requests as batches of at most the max batch size and waits for
+// the server to return a response for all of them. The wait duration is bounded by the context's
+// deadline.
+//
+// Responses are matched to the elements by their ID, an element without a response gets
+// ErrMissingBatchResponse. A batch which the node rejects for its size, with RPCErrorBatchTooLarge or
+// an HTTP 413, is split in halves and sent again. Other errors rejecting a batch are returned, as a
+// *PartialBatchError when the batches before it were answered.
+func (c *Client) BatchCallContext(ctx context.Context, b []BatchElem) (err error) {
+	ctx, end := c.startBatch(ctx, b)
+	defer func() { end(err) }()
+
+	size := c.maxBatchSize
+	if size <= 0 {
+		size = len(b)
+	}
+	for start := 0; start < len(b); start += size {
+		stop := start + size
+		if stop > len(b) {
+			stop = len(b)
+		}
+		answered, err := c.batchCall(ctx, b[start:stop])
+		if err == nil {
+			continue
+		}
+		if answered += start; answered > 0 {
+			return &PartialBatchError{Answered: answered, Err: err}
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *Client) splitBatch(ctx context.Context, b []BatchElem) (int, error) {
+	half := len(b) / 2
+	if answered, err := c.batchCall(ctx, b[:half]); err != nil {
+		return answered, err
+	}
+	answered, err := c.batchCall(ctx, b[half:])
+	return half + answered, err
+}
+
+// isBatchTooLarge reports whether the node rejected a batch for its number of requests or the size
+// of its body.
+func isBatchTooLarge(err error) bool {
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusRequestEntityTooLarge
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		message := strings.ToLower(rpcErr.Message)
+		return rpcErr.Code == RPCErrorBatchTooLarge ||
+			strings.Contains(message, "batch") && strings.Contains(message, "too large")
+	}
+	return false
+}
+
+// batchCall sends b as one batch and returns how many of the first elements were answered, all of
+// them unless it fails.
+func (c *Client) batchCall(ctx context.Context, b []BatchElem) (int, error) {
 	var (
-		msgs = make([]*jsonrpcMessage, len(b))
-		byID = make(map[string]int, len(b))
+		msgs    = make([]*jsonrpcMessage, len(b))
+		byID    = make(map[string]int, len(b))
+		methods = make([]string, len(b))
 	)
 	for i, elem := range b {
 		msg, err := c.newMessage(elem.Method, elem.Args...)
 		if err != nil {
-			return err
+			return 0, err
 		}
 		msgs[i] = msg
 		byID[string(msg.ID)] = i
+		methods[i] = elem.Method
+	}
+	respBody, err := c.doRequest(ctx, msgs, methods)
+	if len(b) > 1 && isBatchTooLarge(err) {
+		return c.splitBatch(ctx, b)
 	}
-	respBody, err := c.doRequest(ctx, msgs)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	respBody = bytes.TrimSpace(respBody)
+	if len(respBody) > 0 && respBody[0] == '{' {
+		// the node answers a batch it does not accept with a single error
+		var respmsg jsonrpcMessage
+		if err := json.Unmarshal(respBody, &respmsg); err != nil {
+			return 0, err
+		}
+		if respmsg.Error == nil {
+			return 0, fmt.Errorf("batch answered with a single response")
+		}
+		if len(b) == 1 {
+			b[0].Error = respmsg.Error
+			return 1, nil
+		}
+		if !isBatchTooLarge(respmsg.Error) {
+			return 0, respmsg.Error
+		}
+		return c.splitBatch(ctx, b)
 	}
-	defer respBody.Close()
 
 	var respmsgs []jsonrpcMessage
-	if err := json.NewDecoder(respBody).Decode(&respmsgs); err != nil {
-		return err
+	if err := json.Unmarshal(respBody, &respmsgs); err != nil {
+		return 0, err
 	}
-	for idx, resp := range respmsgs {
+	answered := make([]bool, len(b))
+	for _, resp := range respmsgs {
+		idx, ok := byID[string(resp.ID)]
+		if !ok || answered[idx] {
+			continue
+		}
+		answered[idx] = true
 		elem := &b[idx]
 		if resp.Error != nil {
 			elem.Error = resp.Error
@@ -142,9 +261,18 @@ func (c *Client) BatchCallContext(ctx context.Context, b []BatchElem) error {
 			elem.Error = ErrNoResult
 			continue
 		}
+		if elem.Result == nil {
+			elem.Error = nil
+			continue
+		}
 		elem.Error = json.Unmarshal(resp.Result, elem.Result)
 	}
-	return nil
+	for idx := range b {
+		if !answered[idx] {
+			b[idx].Error = ErrMissingBatchResponse
+		}
+	}
+	return len(b), nil
 }
 
 func (c *Client) nextID() json.RawMessage {
@@ -153,7 +281,11 @@ func (c *Client) nextID() json.RawMessage {
 }
 
 func (c *Client) newMessage(method string, paramsIn ...interface{}) (*jsonrpcMessage, error) {
-	msg := &jsonrpcMessage{Version: vsn, ID: c.nextID(), Method: method}
+	return newJsonrpcMessage(c.nextID(), method, paramsIn...)
+}
+
+func newJsonrpcMessage(id json.RawMessage, method string, paramsIn ...interface{}) (*jsonrpcMessage, error) {
+	msg := &jsonrpcMessage{Version: vsn, ID: id, Method: method}
 	if paramsIn != nil { // prevent sending "params":null
 		var err error
 		if msg.Params, err = json.Marshal(paramsIn); err != nil {
@@ -163,37 +295,49 @@ func (c *Client) newMessage(method string, paramsIn ...interface{}) (*jsonrpcMes
 	return msg, nil
 }
 
-func (c *Client) doRequest(ctx context.Context, msg interface{}) (io.ReadCloser, error) {
+func (c *Client) doRequest(ctx context.Context, msg interface{}, methods []string) ([]byte, error) {
 	body, err := json.Marshal(msg)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.rpcUrl, io.NopCloser(bytes.NewReader(body)))
+	req := &Request{
+		Methods: methods,
+		Header:  http.Header{"Content-Type": []string{"application/json"}},
+		Body:    body,
+	}
+	return c.handler(ctx, req)
+}
+
+// send is the last Handler of the middleware chain, it posts the request to the node.
+func (c *Client) send(ctx context.Context, r *Request) ([]byte, error) {
+	spanFromContext(ctx).SetAttribute(AttributeRpcEndpoint, c.rpcUrl)
+	return postRequest(ctx, c.client, c.rpcUrl, r)
+}
+
+func postRequest(ctx context.Context, hc *http.Client, rpcUrl string, r *Request) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpcUrl, bytes.NewReader(r.Body))
 	if err != nil {
 		return nil, err
 	}
-	req.ContentLength = int64(len(body))
-	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
-
-	req.Header.Set("Content-Type", "application/json")
+	req.Header = r.Header.Clone()
 
 	// do request
-	resp, err := c.client.Do(req)
+	resp, err := hc.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var buf bytes.Buffer
-		var body []byte
-		if _, err := buf.ReadFrom(resp.Body); err == nil {
-			body = buf.Bytes()
-		}
-
 		return nil, HTTPError{
 			Status:     resp.Status,
 			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
 			Body:       body,
 		}
 	}
-	return resp.Body, nil
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
 }
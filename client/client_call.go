@@ -35,14 +35,10 @@ func (c *Client) GetAllBalances(ctx context.Context, owner suiAddress) ([]types.
 	return resp, c.CallContext(ctx, &resp, getAllBalances, owner)
 }
 
-// GetSuiCoinsOwnedByAddress This function will retrieve a maximum of 200 coins.
+// GetSuiCoinsOwnedByAddress retrieves all the sui coins of address, page by page.
 func (c *Client) GetSuiCoinsOwnedByAddress(ctx context.Context, address suiAddress) (types.Coins, error) {
 	coinType := types.SuiCoinType
-	page, err := c.GetCoins(ctx, address, &coinType, nil, 200)
-	if err != nil {
-		return nil, err
-	}
-	return page.Data, nil
+	return c.GetCoinsIterator(address, &coinType, WithIteratorPageSize(200)).Collect(ctx)
 }
 
 // GetCoins to use default sui coin(0x2::sui::SUI) when coinType is nil
@@ -123,6 +119,22 @@ func (c *Client) GetLatestCheckpointSequenceNumber(ctx context.Context) (string,
 	return resp, c.CallContext(ctx, &resp, getLatestCheckpointSequenceNumber)
 }
 
+func (c *Client) GetCheckpoint(ctx context.Context, id types.CheckpointId) (*types.Checkpoint, error) {
+	var resp types.Checkpoint
+	return &resp, c.CallContext(ctx, &resp, getCheckpoint, id)
+}
+
+// GetCheckpoints start with the first checkpoint (or the latest one in descending order) when cursor is nil
+func (c *Client) GetCheckpoints(
+	ctx context.Context,
+	cursor *types.SafeSuiBigInt[types.CheckpointSequenceNumber],
+	limit *uint,
+	descendingOrder bool,
+) (*types.CheckpointPage, error) {
+	var resp types.CheckpointPage
+	return &resp, c.CallContext(ctx, &resp, getCheckpoints, cursor, limit, descendingOrder)
+}
+
 // BatchGetObjectsOwnedByAddress @param filterType You can specify filtering out the specified resources, this will fetch all resources if it is not empty ""
 func (c *Client) BatchGetObjectsOwnedByAddress(
 	ctx context.Context,
@@ -176,6 +188,15 @@ func (c *Client) GetTransactionBlock(
 	return &resp, c.CallContext(ctx, &resp, getTransactionBlock, digest, options)
 }
 
+func (c *Client) MultiGetTransactionBlocks(
+	ctx context.Context,
+	digests []suiDigest,
+	options types.SuiTransactionBlockResponseOptions,
+) ([]types.SuiTransactionBlockResponse, error) {
+	var resp []types.SuiTransactionBlockResponse
+	return resp, c.CallContext(ctx, &resp, multiGetTransactionBlocks, digests, options)
+}
+
 func (c *Client) GetReferenceGasPrice(ctx context.Context) (*types.SafeSuiBigInt[uint64], error) {
 	var resp types.SafeSuiBigInt[uint64]
 	return &resp, c.CallContext(ctx, &resp, getReferenceGasPrice)
@@ -196,6 +217,15 @@ func (c *Client) TryGetPastObject(
 	return &resp, c.CallContext(ctx, &resp, tryGetPastObject, objectId, version, options)
 }
 
+func (c *Client) TryMultiGetPastObjects(
+	ctx context.Context,
+	pastObjects []types.SuiGetPastObjectRequest,
+	options *types.SuiObjectDataOptions,
+) ([]types.SuiPastObjectResponse, error) {
+	var resp []types.SuiPastObjectResponse
+	return resp, c.CallContext(ctx, &resp, tryMultiGetPastObjects, pastObjects, options)
+}
+
 func (c *Client) DevInspectTransactionBlock(
 	ctx context.Context,
 	senderAddress suiAddress,
@@ -215,14 +245,6 @@ func (c *Client) DryRunTransaction(
 	return &resp, c.CallContext(ctx, &resp, dryRunTransactionBlock, txBytes)
 }
 
-func (c *Client) ExecuteTransactionBlock(
-	ctx context.Context, txBytes suiBase64Data, signatures []any,
-	options *types.SuiTransactionBlockResponseOptions, requestType types.ExecuteTransactionRequestType,
-) (*types.SuiTransactionBlockResponse, error) {
-	resp := types.SuiTransactionBlockResponse{}
-	return &resp, c.CallContext(ctx, &resp, executeTransactionBlock, txBytes, signatures, options, requestType)
-}
-
 // TransferObject Create an unsigned transaction to transfer an object from one address to another. The object's type must allow public transfers
 func (c *Client) TransferObject(
 	ctx context.Context,
@@ -420,3 +442,87 @@ func (c *Client) GetDynamicFieldObject(
 	var resp types.SuiObjectResponse
 	return &resp, c.CallContext(ctx, &resp, getDynamicFieldObject, parentObjectId, name)
 }
+
+// MARK - Move Introspection
+
+func (c *Client) GetNormalizedMoveModulesByPackage(
+	ctx context.Context,
+	packageId suiObjectID,
+) (map[string]types.SuiMoveNormalizedModule, error) {
+	var resp map[string]types.SuiMoveNormalizedModule
+	return resp, c.CallContext(ctx, &resp, getNormalizedMoveModulesByPackage, packageId)
+}
+
+func (c *Client) GetNormalizedMoveModule(
+	ctx context.Context,
+	packageId suiObjectID,
+	moduleName string,
+) (*types.SuiMoveNormalizedModule, error) {
+	var resp types.SuiMoveNormalizedModule
+	return &resp, c.CallContext(ctx, &resp, getNormalizedMoveModule, packageId, moduleName)
+}
+
+func (c *Client) GetNormalizedMoveStruct(
+	ctx context.Context,
+	packageId suiObjectID,
+	moduleName, structName string,
+) (*types.SuiMoveNormalizedStruct, error) {
+	var resp types.SuiMoveNormalizedStruct
+	return &resp, c.CallContext(ctx, &resp, getNormalizedMoveStruct, packageId, moduleName, structName)
+}
+
+func (c *Client) GetNormalizedMoveFunction(
+	ctx context.Context,
+	packageId suiObjectID,
+	moduleName, functionName string,
+) (*types.SuiMoveNormalizedFunction, error) {
+	var resp types.SuiMoveNormalizedFunction
+	return &resp, c.CallContext(ctx, &resp, getNormalizedMoveFunction, packageId, moduleName, functionName)
+}
+
+// GetMoveFunctionArgTypes returns whether every parameter of the function is a pure value or an object
+func (c *Client) GetMoveFunctionArgTypes(
+	ctx context.Context,
+	packageId suiObjectID,
+	moduleName, functionName string,
+) ([]types.MoveFunctionArgType, error) {
+	var resp []types.MoveFunctionArgType
+	return resp, c.CallContext(ctx, &resp, getMoveFunctionArgTypes, packageId, moduleName, functionName)
+}
+
+// MARK - Epoch & Metrics
+
+func (c *Client) GetCurrentEpoch(ctx context.Context) (*types.EpochInfo, error) {
+	var resp types.EpochInfo
+	return &resp, c.CallContext(ctx, &resp, getCurrentEpoch)
+}
+
+// GetEpochs start with the genesis epoch (or the current one in descending order) when cursor is nil
+func (c *Client) GetEpochs(
+	ctx context.Context,
+	cursor *types.SafeSuiBigInt[types.EpochId],
+	limit *uint,
+	descendingOrder bool,
+) (*types.EpochPage, error) {
+	var resp types.EpochPage
+	return &resp, c.CallContext(ctx, &resp, getEpochs, cursor, limit, descendingOrder)
+}
+
+// GetCommitteeInfo returns the committee of the current epoch when epoch is nil
+func (c *Client) GetCommitteeInfo(
+	ctx context.Context,
+	epoch *types.SafeSuiBigInt[types.EpochId],
+) (*types.CommitteeInfo, error) {
+	var resp types.CommitteeInfo
+	return &resp, c.CallContext(ctx, &resp, getCommitteeInfo, epoch)
+}
+
+func (c *Client) GetNetworkMetrics(ctx context.Context) (*types.NetworkMetrics, error) {
+	var resp types.NetworkMetrics
+	return &resp, c.CallContext(ctx, &resp, getNetworkMetrics)
+}
+
+func (c *Client) GetMoveCallMetrics(ctx context.Context) (*types.MoveCallMetrics, error) {
+	var resp types.MoveCallMetrics
+	return &resp, c.CallContext(ctx, &resp, getMoveCallMetrics)
+}
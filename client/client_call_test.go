@@ -7,7 +7,9 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/thorli9527/sui-wallet-sdk/account"
 	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/mocknode"
 	"github.com/thorli9527/sui-wallet-sdk/sui_types"
 
 	"github.com/stretchr/testify/require"
@@ -55,6 +57,39 @@ import (
 //	}
 //}
 
+// chainNode is a mock node where owner has two SUI coins, gas the first one, and a USDC coin.
+type chainNode struct {
+	*mocknode.Node
+	cli    *Client
+	signer *account.Account
+	owner  sui_types.SuiAddress
+	gas    sui_types.ObjectRef
+}
+
+func newChainNode(t *testing.T) *chainNode {
+	node := &chainNode{Node: mocknode.New()}
+	t.Cleanup(node.Close)
+	cli, err := Dial(node.URL())
+	require.NoError(t, err)
+	node.cli = cli
+	node.signer, node.owner = testAccount(t, 3)
+	node.gas = node.Mint(node.owner, "", 1_000_000_000)
+	node.Mint(node.owner, "", 2_000_000_000)
+	node.Mint(node.owner, testUsdcType, 1_000)
+	return node
+}
+
+// transferSui executes a transaction sending amount split from the gas coin to recipient.
+func (n *chainNode) transferSui(t *testing.T, recipient sui_types.SuiAddress, amount uint64) *types.SuiTransactionBlockResponse {
+	txBytes, err := splitTransfer(n.owner, recipient, nil, amount)([]*sui_types.ObjectRef{&n.gas}, 10_000_000, 1000)
+	require.NoError(t, err)
+	resp, err := n.cli.SignAndExecuteTransaction(context.Background(), n.signer, txBytes, nil)
+	require.NoError(t, err)
+	n.gas.Version = resp.Effects.Data.V1.GasObject.Reference.Version
+	n.gas.Digest = resp.Effects.Data.V1.GasObject.Reference.Digest
+	return resp
+}
+
 func Test_TagJson_Owner(t *testing.T) {
 	test := func(str string) lib.TagJson[sui_types.Owner] {
 		var s lib.TagJson[sui_types.Owner]
@@ -149,46 +184,62 @@ func TestClient_BatchGetObjectsOwnedByAddress(t *testing.T) {
 }
 
 func TestClient_GetCoinMetadata(t *testing.T) {
-	chain := ChainClient(t)
+	node := mocknode.New()
+	t.Cleanup(node.Close)
+	node.SetCoinMetadata(testUsdcType, types.SuiCoinMetadata{Decimals: 6, Name: "USD Coin", Symbol: "USDC"})
+	chain, err := Dial(node.URL())
+	require.NoError(t, err)
+
 	metadata, err := chain.GetCoinMetadata(context.TODO(), types.SuiCoinType)
-	require.Nil(t, err)
-	t.Logf("%#v", metadata)
+	require.NoError(t, err)
+	require.Equal(t, uint8(9), metadata.Decimals)
+	require.Equal(t, "SUI", metadata.Symbol)
+	metadata, err = chain.GetCoinMetadata(context.TODO(), testUsdcType)
+	require.NoError(t, err)
+	require.Equal(t, "USDC", metadata.Symbol)
 }
 
 func TestClient_GetAllBalances(t *testing.T) {
-	chain := ChainClient(t)
-	balances, err := chain.GetAllBalances(context.TODO(), *Address)
+	node := newChainNode(t)
+	chain, owner := node.cli, node.owner
+	balances, err := chain.GetAllBalances(context.TODO(), owner)
 	require.NoError(t, err)
+	require.Len(t, balances, 2)
+	totals := map[string]string{}
 	for _, balance := range balances {
-		t.Logf(
-			"Coin Name: %v, Count: %v, Total: %v, Locked: %v",
-			balance.CoinType, balance.CoinObjectCount,
-			balance.TotalBalance.String(), balance.LockedBalance,
-		)
+		totals[balance.CoinType] = balance.TotalBalance.String()
 	}
+	require.Equal(t, map[string]string{types.SUI_COIN_TYPE: "3000000000", testUsdcType: "1000"}, totals)
 }
 
 func TestClient_GetBalance(t *testing.T) {
-	chain := ChainClient(t)
-	balance, err := chain.GetBalance(context.TODO(), *Address, "")
+	node := newChainNode(t)
+	chain, owner := node.cli, node.owner
+	balance, err := chain.GetBalance(context.TODO(), owner, "")
 	require.NoError(t, err)
-	t.Logf(
-		"Coin Name: %v, Count: %v, Total: %v, Locked: %v",
-		balance.CoinType, balance.CoinObjectCount,
-		balance.TotalBalance.String(), balance.LockedBalance,
-	)
+	require.Equal(t, types.SUI_COIN_TYPE, balance.CoinType)
+	require.Equal(t, uint64(2), balance.CoinObjectCount)
+	require.Equal(t, "3000000000", balance.TotalBalance.String())
 }
 
 func TestClient_GetCoins(t *testing.T) {
-	chain := ChainClient(t)
+	node := newChainNode(t)
+	chain, owner := node.cli, node.owner
 	defaultCoinType := types.SuiCoinType
-	coins, err := chain.GetCoins(context.TODO(), *Address, &defaultCoinType, nil, 1)
+	coins, err := chain.GetCoins(context.TODO(), owner, &defaultCoinType, nil, 1)
+	require.NoError(t, err)
+	require.Len(t, coins.Data, 1)
+	require.True(t, coins.HasNextPage)
+
+	coins, err = chain.GetCoins(context.TODO(), owner, &defaultCoinType, coins.NextCursor, 1)
 	require.NoError(t, err)
-	t.Logf("%#v", coins)
+	require.Len(t, coins.Data, 1)
+	require.False(t, coins.HasNextPage)
 }
 
 func TestClient_GetAllCoins(t *testing.T) {
-	chain := ChainClient(t)
+	node := newChainNode(t)
+	chain, owner := node.cli, node.owner
 	type args struct {
 		ctx     context.Context
 		address suiAddress
@@ -199,7 +250,7 @@ func TestClient_GetAllCoins(t *testing.T) {
 		name    string
 		chain   *Client
 		args    args
-		want    *types.CoinPage
+		want    int
 		wantErr bool
 	}{
 		{
@@ -207,10 +258,11 @@ func TestClient_GetAllCoins(t *testing.T) {
 			chain: chain,
 			args: args{
 				ctx:     context.TODO(),
-				address: *Address,
+				address: owner,
 				cursor:  nil,
 				limit:   3,
 			},
+			want:    3,
 			wantErr: false,
 		},
 	}
@@ -222,7 +274,7 @@ func TestClient_GetAllCoins(t *testing.T) {
 					t.Errorf("GetAllCoins() error = %v, wantErr %v", err, tt.wantErr)
 					return
 				}
-				t.Logf("%#v", got)
+				require.Len(t, got.Data, tt.want)
 			},
 		)
 	}
@@ -282,25 +334,24 @@ func TestClient_GetObject(t *testing.T) {
 		ctx   context.Context
 		objID suiObjectID
 	}
-	chain := ChainClient(t)
-	coins, err := chain.GetCoins(context.TODO(), *Address, nil, nil, 1)
+	node := newChainNode(t)
+	chain, owner := node.cli, node.owner
+	coins, err := chain.GetCoins(context.TODO(), owner, nil, nil, 1)
 	require.NoError(t, err)
 
 	tests := []struct {
 		name    string
 		chain   *Client
 		args    args
-		want    int
 		wantErr bool
 	}{
 		{
-			name:  "test for devnet",
+			name:  "test for mock node",
 			chain: chain,
 			args: args{
 				ctx:   context.TODO(),
 				objID: coins.Data[0].CoinObjectId,
 			},
-			want:    3,
 			wantErr: false,
 		},
 	}
@@ -322,20 +373,18 @@ func TestClient_GetObject(t *testing.T) {
 					t.Errorf("GetObject() error = %v, wantErr %v", err, tt.wantErr)
 					return
 				}
-				t.Logf("%+v", got)
+				require.Equal(t, tt.args.objID, got.Data.ObjectId)
+				require.Equal(t, "0x2::coin::Coin<0x2::sui::SUI>", *got.Data.Type)
 			},
 		)
 	}
 }
 
 func TestClient_MultiGetObjects(t *testing.T) {
-	chain := ChainClient(t)
-	coins, err := chain.GetCoins(context.TODO(), *Address, nil, nil, 1)
+	node := newChainNode(t)
+	chain, owner := node.cli, node.owner
+	coins, err := chain.GetCoins(context.TODO(), owner, nil, nil, 1)
 	require.NoError(t, err)
-	if len(coins.Data) == 0 {
-		t.Log("Warning: No Object Id for test.")
-		return
-	}
 
 	obj := coins.Data[0].CoinObjectId
 	objs := []suiObjectID{obj, obj}
@@ -356,7 +405,8 @@ func TestClient_MultiGetObjects(t *testing.T) {
 }
 
 func TestClient_GetOwnedObjects(t *testing.T) {
-	cli := ChainClient(t)
+	node := newChainNode(t)
+	cli, owner := node.cli, node.owner
 
 	obj, err := sui_types.NewAddressFromHex("0x2")
 	require.Nil(t, err)
@@ -370,7 +420,7 @@ func TestClient_GetOwnedObjects(t *testing.T) {
 		},
 	}
 	limit := uint(1)
-	objs, err := cli.GetOwnedObjects(context.Background(), *Address, &query, nil, &limit)
+	objs, err := cli.GetOwnedObjects(context.Background(), owner, &query, nil, &limit)
 	require.Nil(t, err)
 	require.GreaterOrEqual(t, len(objs.Data), int(limit))
 }
@@ -412,17 +462,23 @@ func TestClient_GetTotalSupply(t *testing.T) {
 	}
 }
 func TestClient_GetTotalTransactionBlocks(t *testing.T) {
-	cli := ChainClient(t)
-	res, err := cli.GetTotalTransactionBlocks(context.Background())
+	node := newChainNode(t)
+	node.transferSui(t, node.owner, 10)
+	res, err := node.cli.GetTotalTransactionBlocks(context.Background())
 	require.Nil(t, err)
-	t.Log(res)
+	require.Equal(t, "1", res)
 }
 
 func TestClient_GetLatestCheckpointSequenceNumber(t *testing.T) {
-	cli := MainnetClient(t)
-	res, err := cli.GetLatestCheckpointSequenceNumber(context.Background())
+	node := newChainNode(t)
+	res, err := node.cli.GetLatestCheckpointSequenceNumber(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, "0", res)
+
+	node.transferSui(t, node.owner, 10)
+	res, err = node.cli.GetLatestCheckpointSequenceNumber(context.Background())
 	require.Nil(t, err)
-	t.Log(res)
+	require.Equal(t, "1", res)
 }
 
 //func TestClient_Publish(t *testing.T) {
@@ -482,29 +538,30 @@ func TestClient_GetLatestCheckpointSequenceNumber(t *testing.T) {
 //}
 
 func TestClient_TryGetPastObject(t *testing.T) {
-	cli := ChainClient(t)
-	objId, err := sui_types.NewAddressFromHex("0x11462c88e74bb00079e3c043efb664482ee4551744ee691c7623b98503cb3f4d")
-	require.Nil(t, err)
-	data, err := cli.TryGetPastObject(context.Background(), *objId, 903, nil)
+	node := newChainNode(t)
+	gas := node.gas
+	node.transferSui(t, node.owner, 10)
+	require.Greater(t, node.gas.Version, gas.Version)
+	data, err := node.cli.TryGetPastObject(context.Background(), gas.ObjectId, gas.Version, nil)
 	require.Nil(t, err)
-	t.Log(data)
+	require.NotNil(t, data.Data.VersionFound)
+	require.Equal(t, gas.Digest, data.Data.VersionFound.Digest)
 }
 
 func TestClient_GetEvents(t *testing.T) {
-	cli := MainnetClient(t)
-	digest := "D1TM8Esaj3G9xFEDirqMWt9S7HjJXFrAGYBah1zixWTL"
-	d, err := sui_types.NewDigest(digest)
-	require.Nil(t, err)
-	res, err := cli.GetEvents(context.Background(), *d)
+	node := newChainNode(t)
+	id := node.EmitEvent(node.owner, "0x5::auction::Bid", map[string]interface{}{"amount": "10"})
+	res, err := node.cli.GetEvents(context.Background(), id.TxDigest)
 	require.NoError(t, err)
-	t.Log(res)
+	require.Len(t, res, 1)
+	require.Equal(t, "0x5::auction::Bid", res[0].Type)
 }
 
 func TestClient_GetReferenceGasPrice(t *testing.T) {
-	cli := ChainClient(t)
+	cli := newChainNode(t).cli
 	gasPrice, err := cli.GetReferenceGasPrice(context.Background())
 	require.Nil(t, err)
-	t.Logf("current gas price = %v", gasPrice)
+	require.Equal(t, uint64(1000), gasPrice.Uint64())
 }
 
 // func TestClient_DevInspectTransactionBlock(t *testing.T) {
@@ -531,7 +588,10 @@ func TestClient_GetReferenceGasPrice(t *testing.T) {
 // }
 
 func TestClient_QueryTransactionBlocks(t *testing.T) {
-	cli := ChainClient(t)
+	node := newChainNode(t)
+	_, recipient := testAccount(t, 4)
+	executed := node.transferSui(t, recipient, 10)
+	cli := node.cli
 	limit := uint(10)
 	type args struct {
 		ctx             context.Context
@@ -552,7 +612,7 @@ func TestClient_QueryTransactionBlocks(t *testing.T) {
 				ctx: context.TODO(),
 				query: types.SuiTransactionBlockResponseQuery{
 					Filter: &types.TransactionFilter{
-						FromAddress: Address,
+						FromAddress: &node.owner,
 					},
 					Options: &types.SuiTransactionBlockResponseOptions{
 						ShowInput:   true,
@@ -579,7 +639,8 @@ func TestClient_QueryTransactionBlocks(t *testing.T) {
 					t.Errorf("QueryTransactionBlocks() error = %v, wantErr %v", err, tt.wantErr)
 					return
 				}
-				t.Logf("%#v", got)
+				require.Len(t, got.Data, 1)
+				require.Equal(t, executed.Digest, got.Data[0].Digest)
 			},
 		)
 	}
@@ -610,7 +671,11 @@ func TestClient_ResolveNameServiceNames(t *testing.T) {
 }
 
 func TestClient_QueryEvents(t *testing.T) {
-	cli := ChainClient(t)
+	node := newChainNode(t)
+	_, other := testAccount(t, 4)
+	node.EmitEvent(node.owner, "0x5::auction::Bid", map[string]interface{}{"amount": "10"})
+	node.EmitEvent(other, "0x5::auction::Bid", map[string]interface{}{"amount": "20"})
+	cli := node.cli
 	limit := uint(10)
 	type args struct {
 		ctx             context.Context
@@ -630,7 +695,7 @@ func TestClient_QueryEvents(t *testing.T) {
 			args: args{
 				ctx: context.TODO(),
 				query: types.EventFilter{
-					Sender: Address,
+					Sender: &node.owner,
 				},
 				cursor:          nil,
 				limit:           &limit,
@@ -652,7 +717,8 @@ func TestClient_QueryEvents(t *testing.T) {
 					t.Errorf("QueryEvents() error = %v, wantErr %v", err, tt.wantErr)
 					return
 				}
-				t.Log(got)
+				require.Len(t, got.Data, 1)
+				require.Equal(t, node.owner, got.Data[0].Sender)
 			},
 		)
 	}
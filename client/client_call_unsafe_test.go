@@ -261,7 +261,7 @@ func executeTxn(
 		ShowEffects: true,
 	}
 	resp, err := cli.ExecuteTransactionBlock(
-		context.TODO(), txBytes, []any{signature}, &options,
+		context.TODO(), txBytes, []sui_types.Signature{signature}, &options,
 		types.TxnRequestTypeWaitForLocalExecution,
 	)
 	require.NoError(t, err)
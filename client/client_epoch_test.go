@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+func TestClient_GetEpochsIterator(t *testing.T) {
+	handler := func(params []json.RawMessage) (interface{}, error) {
+		var cursor *types.SafeSuiBigInt[types.EpochId]
+		var descending bool
+		_ = json.Unmarshal(params[0], &cursor)
+		_ = json.Unmarshal(params[2], &descending)
+		if !descending {
+			return nil, errors.New("not descending")
+		}
+		next := uint64(4)
+		if cursor != nil {
+			next = cursor.Uint64() - 1
+		}
+		epoch := types.EpochInfo{Epoch: types.NewSafeSuiBigInt(next)}
+		return types.EpochPage{Data: []types.EpochInfo{epoch}, NextCursor: &epoch.Epoch, HasNextPage: next > 0}, nil
+	}
+	standIn := newRpcStandIn(t, map[string]rpcHandler{getEpochs.String(): handler})
+	epochs, err := standIn.client(t).GetEpochsIterator(true).Collect(context.Background())
+	require.NoError(t, err)
+	require.Len(t, epochs, 5)
+	require.Equal(t, uint64(0), epochs[4].Epoch.Uint64())
+}
+
+func TestClient_TryMultiGetPastObjects(t *testing.T) {
+	id, _ := sui_types.NewObjectIdFromHex("0x5")
+	var requests []types.SuiGetPastObjectRequest
+	standIn := newRpcStandIn(
+		t, map[string]rpcHandler{
+			tryMultiGetPastObjects.String(): func(params []json.RawMessage) (interface{}, error) {
+				if err := json.Unmarshal(params[0], &requests); err != nil {
+					return nil, err
+				}
+				return json.RawMessage(`[{"status": "ObjectNotExists", "details": "0x5"}]`), nil
+			},
+		},
+	)
+	resp, err := standIn.client(t).TryMultiGetPastObjects(
+		context.Background(),
+		[]types.SuiGetPastObjectRequest{{ObjectId: *id, Version: types.NewSafeSuiBigInt[sui_types.SequenceNumber](3)}},
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), requests[0].Version.Uint64())
+	require.Len(t, resp, 1)
+	require.Equal(t, *id, *resp[0].Data.ObjectNotExists)
+}
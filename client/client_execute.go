@@ -0,0 +1,288 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+var (
+	ErrNoSignatures          = errors.New("transaction has no signatures")
+	ErrLocalExecutionTimeout = errors.New("transaction was not executed locally by the node before the timeout")
+)
+
+type executeConfig struct {
+	localExecutionTimeout time.Duration
+	pollInterval          time.Duration
+	maxAttempts           int
+	retryDelay            time.Duration
+	attemptTimeout        time.Duration
+}
+
+func newExecuteConfig(executeOptions []ExecuteOption) executeConfig {
+	config := executeConfig{
+		localExecutionTimeout: 30 * time.Second,
+		pollInterval:          500 * time.Millisecond,
+		maxAttempts:           3,
+		retryDelay:            time.Second,
+	}
+	for _, option := range executeOptions {
+		option(&config)
+	}
+	return config
+}
+
+// TransactionSigner signs the transaction bytes, *account.Account is one.
+type TransactionSigner interface {
+	SignSecureWithoutEncode(txnBytes []byte, intent sui_types.Intent) (sui_types.Signature, error)
+}
+
+type ExecuteOption func(*executeConfig)
+
+// WithLocalExecutionTimeout sets how long a WaitForLocalExecution request polls for the transaction
+// after the node returned before executing it locally.
+func WithLocalExecutionTimeout(timeout time.Duration) ExecuteOption {
+	return func(c *executeConfig) {
+		c.localExecutionTimeout = timeout
+	}
+}
+
+// WithLocalExecutionPollInterval sets the delay between the GetTransactionBlock polls.
+func WithLocalExecutionPollInterval(interval time.Duration) ExecuteOption {
+	return func(c *executeConfig) {
+		c.pollInterval = interval
+	}
+}
+
+// WithExecuteRetry sets how many times SignAndExecuteTransaction submits the transaction, and the
+// delay between the attempts. Only transport errors and timeouts are retried.
+func WithExecuteRetry(maxAttempts int, delay time.Duration) ExecuteOption {
+	return func(c *executeConfig) {
+		c.maxAttempts = maxAttempts
+		c.retryDelay = delay
+	}
+}
+
+// WithAttemptTimeout bounds each submission of SignAndExecuteTransaction, the context of the call
+// bounds all of them.
+func WithAttemptTimeout(timeout time.Duration) ExecuteOption {
+	return func(c *executeConfig) {
+		c.attemptTimeout = timeout
+	}
+}
+
+// ExecuteTransactionBlock submits the signed transaction. An empty requestType is chosen like the
+// node does: WaitForLocalExecution if the options show object or balance changes, which only a
+// local execution has, and WaitForEffectsCert otherwise.
+//
+// With WaitForLocalExecution, a response without confirmed local execution is followed by polling
+// GetTransactionBlock while the node does not find the transaction, ErrLocalExecutionTimeout is
+// returned along with the response if it is not visible in time, and any other error of the lookup
+// right away. Use Failed on the response to check the execution status.
+func (c *Client) ExecuteTransactionBlock(
+	ctx context.Context,
+	txBytes suiBase64Data,
+	signatures []sui_types.Signature,
+	options *types.SuiTransactionBlockResponseOptions,
+	requestType types.ExecuteTransactionRequestType,
+	executeOptions ...ExecuteOption,
+) (*types.SuiTransactionBlockResponse, error) {
+	config := newExecuteConfig(executeOptions)
+	requestType, err := executeRequestType(signatures, options, requestType)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := types.SuiTransactionBlockResponse{}
+	err = c.CallContext(ctx, &resp, executeTransactionBlock, txBytes, signatures, options, requestType)
+	if err != nil {
+		return nil, err
+	}
+	if requestType != types.TxnRequestTypeWaitForLocalExecution ||
+		resp.ConfirmedLocalExecution == nil || *resp.ConfirmedLocalExecution {
+		return &resp, nil
+	}
+	executed, err := c.waitForLocalExecution(ctx, resp.Digest, options, config)
+	if err != nil {
+		return &resp, err
+	}
+	return executed, nil
+}
+
+// SignAndExecuteTransactionData serializes data and calls SignAndExecuteTransaction.
+func (c *Client) SignAndExecuteTransactionData(
+	ctx context.Context,
+	signer TransactionSigner,
+	data sui_types.TransactionData,
+	options *types.SuiTransactionBlockResponseOptions,
+	executeOptions ...ExecuteOption,
+) (*types.SuiTransactionBlockResponse, error) {
+	txBytes, err := bcs.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.SignAndExecuteTransaction(ctx, signer, txBytes, options, executeOptions...)
+}
+
+// SignAndExecuteTransaction signs txBytes with signer and executes it, the effects are always shown.
+//
+// A submission which fails with a transport error or a timeout may still have reached the
+// validators, so the transaction is looked up by its digest, computed locally, before it is
+// submitted again. A transaction executed with a failure status returns the response along with a
+// *types.ExecutionError.
+func (c *Client) SignAndExecuteTransaction(
+	ctx context.Context,
+	signer TransactionSigner,
+	txBytes suiBase64Data,
+	options *types.SuiTransactionBlockResponseOptions,
+	executeOptions ...ExecuteOption,
+) (*types.SuiTransactionBlockResponse, error) {
+	config := newExecuteConfig(executeOptions)
+	showOptions := types.SuiTransactionBlockResponseOptions{}
+	if options != nil {
+		showOptions = *options
+	}
+	showOptions.ShowEffects = true
+
+	signature, err := signer.SignSecureWithoutEncode(txBytes, sui_types.DefaultIntent())
+	if err != nil {
+		return nil, err
+	}
+	signatures := []sui_types.Signature{signature}
+	requestType, err := executeRequestType(signatures, &showOptions, "")
+	if err != nil {
+		return nil, err
+	}
+	digest := sui_types.NewTransactionDigestFromBytes(txBytes)
+
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := executeAttemptContext(ctx, config)
+		resp, err := c.ExecuteTransactionBlock(
+			attemptCtx, txBytes, signatures, &showOptions, requestType, executeOptions...,
+		)
+		cancel()
+		if err == nil || errors.Is(err, ErrLocalExecutionTimeout) {
+			return executionResult(resp, err)
+		}
+		if attempt >= config.maxAttempts || !isRetryableExecuteError(ctx, err) {
+			return nil, err
+		}
+
+		lookupCtx, cancel := executeAttemptContext(ctx, config)
+		resp, lookupErr := c.GetTransactionBlock(lookupCtx, digest, showOptions)
+		cancel()
+		if lookupErr == nil {
+			return executionResult(resp, nil)
+		}
+		if err := sleepContext(ctx, config.retryDelay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func executeAttemptContext(ctx context.Context, config executeConfig) (context.Context, context.CancelFunc) {
+	if config.attemptTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, config.attemptTimeout)
+}
+
+func executionResult(
+	resp *types.SuiTransactionBlockResponse,
+	err error,
+) (*types.SuiTransactionBlockResponse, error) {
+	if executionErr := types.NewExecutionError(resp); executionErr != nil {
+		return resp, executionErr
+	}
+	return resp, err
+}
+
+// isRetryableExecuteError reports whether the submission may not have reached the node, the node
+// rejecting the transaction is final unless it was overloaded.
+func isRetryableExecuteError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return errors.Is(rpcErr, types.ErrNodeOverloaded)
+	}
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError ||
+			httpErr.StatusCode == http.StatusRequestTimeout ||
+			httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+func executeRequestType(
+	signatures []sui_types.Signature,
+	options *types.SuiTransactionBlockResponseOptions,
+	requestType types.ExecuteTransactionRequestType,
+) (types.ExecuteTransactionRequestType, error) {
+	if len(signatures) == 0 {
+		return "", ErrNoSignatures
+	}
+	for i, signature := range signatures {
+		if signature.Ed25519SuiSignature == nil && signature.Secp256k1SuiSignature == nil &&
+			signature.Secp256r1SuiSignature == nil {
+			return "", fmt.Errorf("signature %d is empty", i)
+		}
+	}
+	needLocalExecution := options != nil && (options.ShowObjectChanges || options.ShowBalanceChanges)
+	switch requestType {
+	case "":
+		if needLocalExecution {
+			return types.TxnRequestTypeWaitForLocalExecution, nil
+		}
+		return types.TxnRequestTypeWaitForEffectsCert, nil
+	case types.TxnRequestTypeWaitForEffectsCert:
+		if needLocalExecution {
+			return "", errors.New("object and balance changes are only shown with WaitForLocalExecution")
+		}
+		return requestType, nil
+	case types.TxnRequestTypeWaitForLocalExecution:
+		return requestType, nil
+	default:
+		return "", fmt.Errorf("unknown execute transaction request type %q", requestType)
+	}
+}
+
+func (c *Client) waitForLocalExecution(
+	ctx context.Context,
+	digest suiDigest,
+	options *types.SuiTransactionBlockResponseOptions,
+	config executeConfig,
+) (*types.SuiTransactionBlockResponse, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, config.localExecutionTimeout)
+	defer cancel()
+	var queryOptions types.SuiTransactionBlockResponseOptions
+	if options != nil {
+		queryOptions = *options
+	}
+	for {
+		resp, err := c.GetTransactionBlock(timeoutCtx, digest, queryOptions)
+		if err == nil {
+			confirmed := true
+			resp.ConfirmedLocalExecution = &confirmed
+			return resp, nil
+		}
+		if timeoutCtx.Err() == nil && !errors.Is(err, types.ErrTransactionNotFound) {
+			// only a transaction the node has not executed yet is polled again
+			return nil, err
+		}
+		if err := sleepContext(timeoutCtx, config.pollInterval); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			return nil, ErrLocalExecutionTimeout
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+const failedTransactionJson = `{
+	"digest": "HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn",
+	"effects": {
+		"messageVersion": "v1",
+		"status": {"status": "failure", "error": "InsufficientGas"},
+		"executedEpoch": "1",
+		"gasUsed": {"computationCost": "1", "storageCost": "0", "storageRebate": "0", "nonRefundableStorageFee": "0"},
+		"transactionDigest": "HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn"
+	}
+}`
+
+func TestClient_ExecuteTransactionBlock_WaitForLocalExecution(t *testing.T) {
+	var sent []json.RawMessage
+	lookups := 0
+	standIn := newRpcStandIn(
+		t, map[string]rpcHandler{
+			executeTransactionBlock.String(): func(params []json.RawMessage) (interface{}, error) {
+				sent = params
+				return json.RawMessage(`{"digest": "HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn", "confirmedLocalExecution": false}`), nil
+			},
+			getTransactionBlock.String(): func(params []json.RawMessage) (interface{}, error) {
+				lookups++
+				if lookups < 3 {
+					return nil, errors.New("Could not find the referenced transaction")
+				}
+				return json.RawMessage(failedTransactionJson), nil
+			},
+		},
+	)
+	cli := standIn.client(t)
+	signature := sui_types.Signature{Ed25519SuiSignature: &sui_types.Ed25519SuiSignature{}}
+	options := &types.SuiTransactionBlockResponseOptions{ShowEffects: true, ShowObjectChanges: true}
+
+	resp, err := cli.ExecuteTransactionBlock(
+		context.Background(), suiBase64Data{1, 2, 3}, []sui_types.Signature{signature}, options, "",
+		WithLocalExecutionPollInterval(time.Millisecond),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 3, lookups)
+	require.True(t, *resp.ConfirmedLocalExecution)
+	require.True(t, resp.Failed())
+	require.Equal(t, "InsufficientGas", resp.ExecutionStatus().Error)
+
+	var signatures []sui_types.Signature
+	require.NoError(t, json.Unmarshal(sent[1], &signatures))
+	require.Equal(t, signature, signatures[0])
+	require.JSONEq(t, `"WaitForLocalExecution"`, string(sent[3]))
+
+	standIn.handle(
+		getTransactionBlock.String(), func(params []json.RawMessage) (interface{}, error) {
+			return nil, errors.New("Could not find the referenced transaction")
+		},
+	)
+	resp, err = cli.ExecuteTransactionBlock(
+		context.Background(), suiBase64Data{1, 2, 3}, []sui_types.Signature{signature}, options,
+		types.TxnRequestTypeWaitForLocalExecution,
+		WithLocalExecutionTimeout(20*time.Millisecond), WithLocalExecutionPollInterval(time.Millisecond),
+	)
+	require.ErrorIs(t, err, ErrLocalExecutionTimeout)
+	require.False(t, *resp.ConfirmedLocalExecution)
+
+	// any other error of the lookup is returned right away, a new stand-in does not see the
+	// lookups of the timed out call
+	lookups = 0
+	cli = newRpcStandIn(
+		t, map[string]rpcHandler{
+			executeTransactionBlock.String(): func(params []json.RawMessage) (interface{}, error) {
+				return json.RawMessage(`{"digest": "HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn", "confirmedLocalExecution": false}`), nil
+			},
+			getTransactionBlock.String(): func(params []json.RawMessage) (interface{}, error) {
+				lookups++
+				return nil, &RPCError{Code: RPCErrorInvalidParams, Message: "Invalid params"}
+			},
+		},
+	).client(t)
+	_, err = cli.ExecuteTransactionBlock(
+		context.Background(), suiBase64Data{1, 2, 3}, []sui_types.Signature{signature}, options,
+		types.TxnRequestTypeWaitForLocalExecution,
+		WithLocalExecutionTimeout(time.Second), WithLocalExecutionPollInterval(time.Millisecond),
+	)
+	require.ErrorContains(t, err, "Invalid params")
+	require.NotErrorIs(t, err, ErrLocalExecutionTimeout)
+	require.Equal(t, 1, lookups)
+}
+
+func TestClient_ExecuteTransactionBlock_InvalidRequest(t *testing.T) {
+	standIn := newRpcStandIn(t, map[string]rpcHandler{})
+	cli := standIn.client(t)
+	signature := sui_types.Signature{Ed25519SuiSignature: &sui_types.Ed25519SuiSignature{}}
+	ctx := context.Background()
+
+	_, err := cli.ExecuteTransactionBlock(ctx, suiBase64Data{1}, nil, nil, "")
+	require.ErrorIs(t, err, ErrNoSignatures)
+	_, err = cli.ExecuteTransactionBlock(ctx, suiBase64Data{1}, []sui_types.Signature{{}}, nil, "")
+	require.Error(t, err)
+	_, err = cli.ExecuteTransactionBlock(
+		ctx, suiBase64Data{1}, []sui_types.Signature{signature},
+		&types.SuiTransactionBlockResponseOptions{ShowBalanceChanges: true}, types.TxnRequestTypeWaitForEffectsCert,
+	)
+	require.Error(t, err)
+	_, err = cli.ExecuteTransactionBlock(ctx, suiBase64Data{1}, []sui_types.Signature{signature}, nil, "WaitForever")
+	require.Error(t, err)
+	require.Equal(t, 0, standIn.callCount(executeTransactionBlock.String()))
+}
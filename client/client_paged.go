@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+// MARK - Paged Iterators
+
+// pageLimit maps the iterator limit to the optional limit param, nil uses the node default.
+func pageLimit(limit uint) *uint {
+	if limit == 0 {
+		return nil
+	}
+	return &limit
+}
+
+// GetCoinsIterator iterates all the coins of coinType owned by owner, see GetCoins.
+func (c *Client) GetCoinsIterator(
+	owner suiAddress,
+	coinType *string,
+	options ...IteratorOption,
+) *PageIterator[types.Coin, suiObjectID] {
+	return NewPageIterator(
+		func(ctx context.Context, cursor *suiObjectID, limit uint) (*types.CoinPage, error) {
+			var resp types.CoinPage
+			return &resp, c.CallContext(ctx, &resp, getCoins, owner, coinType, cursor, pageLimit(limit))
+		}, options...,
+	)
+}
+
+// GetAllCoinsIterator iterates the coins of every type owned by owner.
+func (c *Client) GetAllCoinsIterator(
+	owner suiAddress,
+	options ...IteratorOption,
+) *PageIterator[types.Coin, suiObjectID] {
+	return NewPageIterator(
+		func(ctx context.Context, cursor *suiObjectID, limit uint) (*types.CoinPage, error) {
+			var resp types.CoinPage
+			return &resp, c.CallContext(ctx, &resp, getAllCoins, owner, cursor, pageLimit(limit))
+		}, options...,
+	)
+}
+
+// GetOwnedObjectsIterator iterates the objects owned by address matching query.
+func (c *Client) GetOwnedObjectsIterator(
+	address suiAddress,
+	query *types.SuiObjectResponseQuery,
+	options ...IteratorOption,
+) *PageIterator[types.SuiObjectResponse, suiObjectID] {
+	return NewPageIterator(
+		func(ctx context.Context, cursor *suiObjectID, limit uint) (*types.ObjectsPage, error) {
+			var resp types.ObjectsPage
+			return &resp, c.CallContext(ctx, &resp, getOwnedObjects, address, query, cursor, pageLimit(limit))
+		}, options...,
+	)
+}
+
+func (c *Client) QueryTransactionBlocksIterator(
+	query types.SuiTransactionBlockResponseQuery,
+	descendingOrder bool,
+	options ...IteratorOption,
+) *PageIterator[types.SuiTransactionBlockResponse, suiDigest] {
+	return NewPageIterator(
+		func(ctx context.Context, cursor *suiDigest, limit uint) (*types.TransactionBlocksPage, error) {
+			return c.QueryTransactionBlocks(ctx, query, cursor, pageLimit(limit), descendingOrder)
+		}, options...,
+	)
+}
+
+func (c *Client) QueryEventsIterator(
+	query types.EventFilter,
+	descendingOrder bool,
+	options ...IteratorOption,
+) *PageIterator[types.SuiEvent, types.EventId] {
+	return NewPageIterator(
+		func(ctx context.Context, cursor *types.EventId, limit uint) (*types.EventPage, error) {
+			return c.QueryEvents(ctx, query, cursor, pageLimit(limit), descendingOrder)
+		}, options...,
+	)
+}
+
+func (c *Client) ResolveNameServiceNamesIterator(
+	owner suiAddress,
+	options ...IteratorOption,
+) *PageIterator[string, suiObjectID] {
+	return NewPageIterator(
+		func(ctx context.Context, cursor *suiObjectID, limit uint) (*types.SuiNamePage, error) {
+			return c.ResolveNameServiceNames(ctx, owner, cursor, pageLimit(limit))
+		}, options...,
+	)
+}
+
+func (c *Client) GetDynamicFieldsIterator(
+	parentObjectId suiObjectID,
+	options ...IteratorOption,
+) *PageIterator[types.DynamicFieldInfo, suiObjectID] {
+	return NewPageIterator(
+		func(ctx context.Context, cursor *suiObjectID, limit uint) (*types.DynamicFieldPage, error) {
+			return c.GetDynamicFields(ctx, parentObjectId, cursor, pageLimit(limit))
+		}, options...,
+	)
+}
+
+func (c *Client) GetCheckpointsIterator(
+	descendingOrder bool,
+	options ...IteratorOption,
+) *PageIterator[types.Checkpoint, types.SafeSuiBigInt[types.CheckpointSequenceNumber]] {
+	return NewPageIterator(
+		func(
+			ctx context.Context,
+			cursor *types.SafeSuiBigInt[types.CheckpointSequenceNumber],
+			limit uint,
+		) (*types.CheckpointPage, error) {
+			return c.GetCheckpoints(ctx, cursor, pageLimit(limit), descendingOrder)
+		}, options...,
+	)
+}
+
+func (c *Client) GetEpochsIterator(
+	descendingOrder bool,
+	options ...IteratorOption,
+) *PageIterator[types.EpochInfo, types.SafeSuiBigInt[types.EpochId]] {
+	return NewPageIterator(
+		func(ctx context.Context, cursor *types.SafeSuiBigInt[types.EpochId], limit uint) (*types.EpochPage, error) {
+			return c.GetEpochs(ctx, cursor, pageLimit(limit), descendingOrder)
+		}, options...,
+	)
+}
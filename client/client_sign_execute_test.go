@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/account"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+func testSigner(t *testing.T) *account.Account {
+	scheme, err := sui_types.NewSignatureScheme(0)
+	require.NoError(t, err)
+	return account.NewAccount(scheme, make([]byte, 32))
+}
+
+func TestClient_SignAndExecuteTransaction_LooksUpBeforeRetry(t *testing.T) {
+	txBytes := suiBase64Data{1, 2, 3}
+	digest := sui_types.NewTransactionDigestFromBytes(txBytes)
+	executed := fmt.Sprintf(`{"digest": "%v", "effects": {"messageVersion": "v1", "status": {"status": "success"}}}`, digest)
+	standIn := newRpcStandIn(
+		t, map[string]rpcHandler{
+			executeTransactionBlock.String(): func(params []json.RawMessage) (interface{}, error) {
+				time.Sleep(200 * time.Millisecond)
+				return json.RawMessage(executed), nil
+			},
+			getTransactionBlock.String(): func(params []json.RawMessage) (interface{}, error) {
+				var queried sui_types.TransactionDigest
+				if err := json.Unmarshal(params[0], &queried); err != nil {
+					return nil, err
+				}
+				if queried.String() != digest.String() {
+					return nil, errors.New("Could not find the referenced transaction")
+				}
+				return json.RawMessage(executed), nil
+			},
+		},
+	)
+	cli := standIn.client(t)
+
+	resp, err := cli.SignAndExecuteTransaction(
+		context.Background(), testSigner(t), txBytes, nil,
+		WithAttemptTimeout(50*time.Millisecond), WithExecuteRetry(3, time.Millisecond),
+	)
+	require.NoError(t, err)
+	require.Equal(t, digest.String(), resp.Digest.String())
+	require.Equal(t, 1, standIn.callCount(executeTransactionBlock.String()))
+	require.Equal(t, 1, standIn.callCount(getTransactionBlock.String()))
+}
+
+func TestClient_SignAndExecuteTransaction_Retry(t *testing.T) {
+	standIn := newRpcStandIn(
+		t, map[string]rpcHandler{
+			executeTransactionBlock.String(): func(params []json.RawMessage) (interface{}, error) {
+				time.Sleep(200 * time.Millisecond)
+				return nil, errors.New("not reached")
+			},
+			getTransactionBlock.String(): func(params []json.RawMessage) (interface{}, error) {
+				return nil, errors.New("Could not find the referenced transaction")
+			},
+		},
+	)
+	cli := standIn.client(t)
+
+	_, err := cli.SignAndExecuteTransaction(
+		context.Background(), testSigner(t), suiBase64Data{1, 2, 3}, nil,
+		WithAttemptTimeout(20*time.Millisecond), WithExecuteRetry(2, time.Millisecond),
+	)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, 2, standIn.callCount(executeTransactionBlock.String()))
+	require.Equal(t, 1, standIn.callCount(getTransactionBlock.String()))
+
+	standIn.handle(
+		executeTransactionBlock.String(), func(params []json.RawMessage) (interface{}, error) {
+			return nil, errors.New("Invalid user signature")
+		},
+	)
+	_, err = cli.SignAndExecuteTransaction(
+		context.Background(), testSigner(t), suiBase64Data{1, 2, 3}, nil, WithExecuteRetry(3, time.Millisecond),
+	)
+	require.ErrorContains(t, err, "Invalid user signature")
+	require.Equal(t, 3, standIn.callCount(executeTransactionBlock.String()))
+	require.Equal(t, 1, standIn.callCount(getTransactionBlock.String()))
+
+	standIn.handle(
+		executeTransactionBlock.String(), func(params []json.RawMessage) (interface{}, error) {
+			return nil, &RPCError{Code: RPCErrorTransient, Message: "TooManyTransactionsPendingExecution"}
+		},
+	)
+	_, err = cli.SignAndExecuteTransaction(
+		context.Background(), testSigner(t), suiBase64Data{1, 2, 3}, nil, WithExecuteRetry(2, time.Millisecond),
+	)
+	require.ErrorIs(t, err, types.ErrNodeOverloaded)
+	require.Equal(t, 5, standIn.callCount(executeTransactionBlock.String()))
+	require.Equal(t, 2, standIn.callCount(getTransactionBlock.String()))
+}
+
+func TestClient_SignAndExecuteTransaction_ExecutionError(t *testing.T) {
+	var sent []json.RawMessage
+	standIn := newRpcStandIn(
+		t, map[string]rpcHandler{
+			executeTransactionBlock.String(): func(params []json.RawMessage) (interface{}, error) {
+				sent = params
+				return json.RawMessage(failedTransactionJson), nil
+			},
+		},
+	)
+	cli := standIn.client(t)
+	signer := testSigner(t)
+	txBytes := suiBase64Data{1, 2, 3}
+
+	resp, err := cli.SignAndExecuteTransaction(context.Background(), signer, txBytes, nil)
+	var executionErr *types.ExecutionError
+	require.ErrorAs(t, err, &executionErr)
+	require.Equal(t, types.ExecutionErrorInsufficientGas, executionErr.Kind)
+	require.Nil(t, executionErr.Command)
+	require.Same(t, resp, executionErr.Response)
+
+	var options types.SuiTransactionBlockResponseOptions
+	require.NoError(t, json.Unmarshal(sent[2], &options))
+	require.True(t, options.ShowEffects)
+	var signatures []sui_types.Signature
+	require.NoError(t, json.Unmarshal(sent[1], &signatures))
+	expected, err := signer.SignSecureWithoutEncode(txBytes, sui_types.DefaultIntent())
+	require.NoError(t, err)
+	require.Equal(t, expected, signatures[0])
+}
@@ -2,9 +2,12 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/fardream/go-bcs/bcs"
 	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/move_types"
 	"github.com/thorli9527/sui-wallet-sdk/sui_types"
 	"github.com/thorli9527/sui-wallet-sdk/sui_types/sui_system_state"
 	"github.com/thorli9527/sui-wallet-sdk/types"
@@ -144,3 +147,282 @@ func BCS_RequestWithdrawStake(
 	)
 	return bcs.Marshal(tx)
 }
+
+// MinStakingThreshold is the smallest principal of a StakedSui, 1 SUI. The staking pool rejects a
+// smaller stake, and a split leaving a smaller part on either side.
+const MinStakingThreshold uint64 = 1_000_000_000
+
+var ErrStakeBelowThreshold = errors.New("stake principal is below the staking threshold of 1 SUI")
+
+func checkStakeAmount(amount uint64) error {
+	if amount < MinStakingThreshold {
+		return fmt.Errorf("%w: %d", ErrStakeBelowThreshold, amount)
+	}
+	return nil
+}
+
+// BCS_RequestAddStakeMulCoin stakes coins with validator through sui_system::request_add_stake_mul_coin,
+// which merges them. A nil amount stakes all of them, otherwise amount is staked and the change is
+// sent back to the signer. The coins must not be gas coins.
+func BCS_RequestAddStakeMulCoin(
+	signer suiAddress,
+	coins []*sui_types.ObjectRef,
+	amount *types.SafeSuiBigInt[uint64],
+	validator suiAddress,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	if len(coins) == 0 {
+		return nil, errors.New("no coins to stake")
+	}
+	stakeAmount := move_types.None[uint64]()
+	if amount != nil {
+		if err := checkStakeAmount(amount.Uint64()); err != nil {
+			return nil, err
+		}
+		stakeAmount = move_types.Some(amount.Uint64())
+	}
+	ptb := sui_types.NewProgrammableTransactionBuilder()
+	systemArg, err := ptb.Obj(sui_types.SuiSystemMutObj)
+	if err != nil {
+		return nil, err
+	}
+	coinObjs := make([]sui_types.ObjectArg, len(coins))
+	for i, coin := range coins {
+		coinObjs[i] = sui_types.ObjectArg{ImmOrOwnedObject: coin}
+	}
+	coinsArg, err := ptb.MakeObjList(coinObjs)
+	if err != nil {
+		return nil, err
+	}
+	amountArg, err := ptb.Pure(stakeAmount)
+	if err != nil {
+		return nil, err
+	}
+	validatorArg, err := ptb.Pure(validator)
+	if err != nil {
+		return nil, err
+	}
+	ptb.Command(
+		sui_types.Command{
+			MoveCall: &sui_types.ProgrammableMoveCall{
+				Package:   *sui_types.SuiSystemAddress,
+				Module:    sui_system_state.SuiSystemModuleName,
+				Function:  sui_types.AddStakeMulCoinFunName,
+				Arguments: []sui_types.Argument{systemArg, coinsArg, amountArg, validatorArg},
+			},
+		},
+	)
+	tx := sui_types.NewProgrammable(signer, gas, ptb.Finish(), gasBudget, gasPrice)
+	return bcs.Marshal(tx)
+}
+
+// BCS_RequestAddStakeWithCoin stakes a coin which is not a gas coin with validator. A nil amount
+// stakes the whole coin, otherwise amount is split from it.
+func BCS_RequestAddStakeWithCoin(
+	signer suiAddress,
+	coin *sui_types.ObjectRef,
+	amount *types.SafeSuiBigInt[uint64],
+	validator suiAddress,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	ptb := sui_types.NewProgrammableTransactionBuilder()
+	systemArg, err := ptb.Obj(sui_types.SuiSystemMutObj)
+	if err != nil {
+		return nil, err
+	}
+	stakeArg, err := ptb.Obj(sui_types.ObjectArg{ImmOrOwnedObject: coin})
+	if err != nil {
+		return nil, err
+	}
+	if amount != nil {
+		if err := checkStakeAmount(amount.Uint64()); err != nil {
+			return nil, err
+		}
+		amountArg, err := ptb.Pure(amount.Uint64())
+		if err != nil {
+			return nil, err
+		}
+		stakeArg = ptb.Command(
+			sui_types.Command{
+				SplitCoins: &struct {
+					Argument  sui_types.Argument
+					Arguments []sui_types.Argument
+				}{Argument: stakeArg, Arguments: []sui_types.Argument{amountArg}},
+			},
+		)
+	}
+	validatorArg, err := ptb.Pure(validator)
+	if err != nil {
+		return nil, err
+	}
+	ptb.Command(
+		sui_types.Command{
+			MoveCall: &sui_types.ProgrammableMoveCall{
+				Package:   *sui_types.SuiSystemAddress,
+				Module:    sui_system_state.SuiSystemModuleName,
+				Function:  sui_types.AddStakeFunName,
+				Arguments: []sui_types.Argument{systemArg, stakeArg, validatorArg},
+			},
+		},
+	)
+	tx := sui_types.NewProgrammable(signer, gas, ptb.Finish(), gasBudget, gasPrice)
+	return bcs.Marshal(tx)
+}
+
+// BCS_SplitStakedSui splits amount of the principal of a StakedSui into a new StakedSui sent to the
+// signer. Both parts must keep at least MinStakingThreshold.
+func BCS_SplitStakedSui(
+	signer suiAddress,
+	stakedSuiRef *sui_types.ObjectRef,
+	amount types.SafeSuiBigInt[uint64],
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	if err := checkStakeAmount(amount.Uint64()); err != nil {
+		return nil, err
+	}
+	ptb := sui_types.NewProgrammableTransactionBuilder()
+	stakeArg, err := ptb.Obj(sui_types.ObjectArg{ImmOrOwnedObject: stakedSuiRef})
+	if err != nil {
+		return nil, err
+	}
+	amountArg, err := ptb.Pure(amount.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	ptb.Command(
+		sui_types.Command{
+			MoveCall: &sui_types.ProgrammableMoveCall{
+				Package:   *sui_types.SuiSystemAddress,
+				Module:    sui_types.StakingPoolModuleName,
+				Function:  sui_types.SplitStakedSuiFunName,
+				Arguments: []sui_types.Argument{stakeArg, amountArg},
+			},
+		},
+	)
+	tx := sui_types.NewProgrammable(signer, gas, ptb.Finish(), gasBudget, gasPrice)
+	return bcs.Marshal(tx)
+}
+
+// BCS_JoinStakedSui merges others into the StakedSui of stakedSuiRef. They must be staked in the
+// same pool and activated in the same epoch, see types.Stake.
+func BCS_JoinStakedSui(
+	signer suiAddress,
+	stakedSuiRef *sui_types.ObjectRef,
+	others []*sui_types.ObjectRef,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	if len(others) == 0 {
+		return nil, errors.New("no staked sui to join")
+	}
+	ptb := sui_types.NewProgrammableTransactionBuilder()
+	stakeArg, err := ptb.Obj(sui_types.ObjectArg{ImmOrOwnedObject: stakedSuiRef})
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range others {
+		otherArg, err := ptb.Obj(sui_types.ObjectArg{ImmOrOwnedObject: other})
+		if err != nil {
+			return nil, err
+		}
+		ptb.Command(
+			sui_types.Command{
+				MoveCall: &sui_types.ProgrammableMoveCall{
+					Package:   *sui_types.SuiSystemAddress,
+					Module:    sui_types.StakingPoolModuleName,
+					Function:  sui_types.JoinStakedSuiFunName,
+					Arguments: []sui_types.Argument{stakeArg, otherArg},
+				},
+			},
+		)
+	}
+	tx := sui_types.NewProgrammable(signer, gas, ptb.Finish(), gasBudget, gasPrice)
+	return bcs.Marshal(tx)
+}
+
+// BCS_RequestWithdrawStakePartial withdraws amount of the principal of a StakedSui and its rewards,
+// the rest stays staked. The withdrawn SUI is sent to the signer as a coin.
+func BCS_RequestWithdrawStakePartial(
+	signer suiAddress,
+	stakedSuiRef *sui_types.ObjectRef,
+	amount types.SafeSuiBigInt[uint64],
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	if err := checkStakeAmount(amount.Uint64()); err != nil {
+		return nil, err
+	}
+	ptb := sui_types.NewProgrammableTransactionBuilder()
+	systemArg, err := ptb.Obj(sui_types.SuiSystemMutObj)
+	if err != nil {
+		return nil, err
+	}
+	stakeArg, err := ptb.Obj(sui_types.ObjectArg{ImmOrOwnedObject: stakedSuiRef})
+	if err != nil {
+		return nil, err
+	}
+	amountArg, err := ptb.Pure(amount.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	signerArg, err := ptb.Pure(signer)
+	if err != nil {
+		return nil, err
+	}
+	// the entry functions cannot take the split StakedSui, the public ones are called instead
+	splitArg := ptb.Command(
+		sui_types.Command{
+			MoveCall: &sui_types.ProgrammableMoveCall{
+				Package:   *sui_types.SuiSystemAddress,
+				Module:    sui_types.StakingPoolModuleName,
+				Function:  sui_types.SplitFunName,
+				Arguments: []sui_types.Argument{stakeArg, amountArg},
+			},
+		},
+	)
+	balanceArg := ptb.Command(
+		sui_types.Command{
+			MoveCall: &sui_types.ProgrammableMoveCall{
+				Package:   *sui_types.SuiSystemAddress,
+				Module:    sui_system_state.SuiSystemModuleName,
+				Function:  sui_types.WithdrawStakeNonEntryFunName,
+				Arguments: []sui_types.Argument{systemArg, splitArg},
+			},
+		},
+	)
+	coinArg := ptb.Command(
+		sui_types.Command{
+			MoveCall: &sui_types.ProgrammableMoveCall{
+				Package:       *sui_types.SuiFrameworkAddress,
+				Module:        sui_types.CoinModuleName,
+				Function:      sui_types.CoinFromBalanceFunName,
+				TypeArguments: []move_types.TypeTag{suiTypeTag()},
+				Arguments:     []sui_types.Argument{balanceArg},
+			},
+		},
+	)
+	ptb.Command(
+		sui_types.Command{
+			TransferObjects: &struct {
+				Arguments []sui_types.Argument
+				Argument  sui_types.Argument
+			}{Arguments: []sui_types.Argument{coinArg}, Argument: signerArg},
+		},
+	)
+	tx := sui_types.NewProgrammable(signer, gas, ptb.Finish(), gasBudget, gasPrice)
+	return bcs.Marshal(tx)
+}
+
+// suiTypeTag is the type tag of `0x2::sui::SUI`.
+func suiTypeTag() move_types.TypeTag {
+	return move_types.TypeTag{
+		Struct: &move_types.StructTag{
+			Address: *sui_types.SuiFrameworkAddress,
+			Module:  sui_types.SuiModuleName,
+			Name:    sui_types.SuiStructName,
+		},
+	}
+}
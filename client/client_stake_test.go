@@ -5,7 +5,11 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/fardream/go-bcs/bcs"
 	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/mocknode"
+	"github.com/thorli9527/sui-wallet-sdk/move_types"
 	"github.com/thorli9527/sui-wallet-sdk/sui_types"
 	"github.com/thorli9527/sui-wallet-sdk/types"
 )
@@ -14,62 +18,84 @@ const (
 	ComingChatValidatorAddress = "0x520289e77c838bae8501ae92b151b99a54407288fdd20dee6e5416bfe943eb7a"
 )
 
-func TestClient_GetLatestSuiSystemState(t *testing.T) {
-	cli := MainnetClient(t)
-	state, err := cli.GetLatestSuiSystemState(context.Background())
-	require.Nil(t, err)
-	t.Logf("system state = %v", state)
+// newStakeNode returns a mock node at epoch 10 with two validators, and an owner with an active
+// stake with the first one and a pending stake with the second one.
+func newStakeNode(t *testing.T) (*mocknode.Node, *Client, sui_types.SuiAddress, []sui_types.ObjectRef) {
+	node := mocknode.New(mocknode.WithEpoch(10))
+	t.Cleanup(node.Close)
+	cli, err := Dial(node.URL())
+	require.NoError(t, err)
+	_, owner := testAccount(t, 1)
+	_, validator1 := testAccount(t, 2)
+	_, validator2 := testAccount(t, 3)
+	node.AddValidator(validator1, "validator 1", 0.073)
+	node.AddValidator(validator2, "validator 2", 0.05)
+	stakes := []sui_types.ObjectRef{
+		node.AddStake(owner, validator1, SUI(1000).Uint64(), 4),
+		node.AddStake(owner, validator2, SUI(2).Uint64(), 10),
+	}
+	return node, cli, owner, stakes
 }
 
 func TestClient_GetValidatorsApy(t *testing.T) {
-	cli := ChainClient(t)
+	_, cli, _, _ := newStakeNode(t)
 	apys, err := cli.GetValidatorsApy(context.Background())
-	require.Nil(t, err)
-	t.Logf("current epoch %v", apys.Epoch)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), apys.Epoch.Uint64())
+	require.Len(t, apys.Apys, 2)
 	apyMap := apys.ApyMap()
-	for idx := 0; idx < 10; idx++ {
-		key := apys.Apys[idx].Address
-		t.Logf("%v apy = %v", key, apyMap[key])
-	}
+	require.Equal(t, 0.073, apyMap[apys.Apys[0].Address])
+	require.Equal(t, 0.05, apyMap[apys.Apys[1].Address])
 }
 
 func TestGetDelegatedStakes(t *testing.T) {
-	cli := ChainClient(t)
-
-	address, err := sui_types.NewAddressFromHex("0xd77955e670f42c1bc5e94b9e68e5fe9bdbed9134d784f2a14dfe5fc1b24b5d9f")
-	require.Nil(t, err)
-	stakes, err := cli.GetStakes(context.Background(), *address)
-	require.Nil(t, err)
-
-	for _, validator := range stakes {
-		for _, stake := range validator.Stakes {
-			if stake.Data.StakeStatus.Data.Active != nil {
-				t.Logf(
-					"earned amount %10v at %v",
-					stake.Data.StakeStatus.Data.Active.EstimatedReward.Uint64(),
-					validator.ValidatorAddress,
-				)
-			}
-		}
-	}
+	_, cli, owner, stakes := newStakeNode(t)
+	delegated, err := cli.GetStakes(context.Background(), owner)
+	require.NoError(t, err)
+	require.Len(t, delegated, 2)
+
+	active := delegated[0].Stakes[0].Data
+	require.Equal(t, stakes[0].ObjectId, active.StakedSuiId)
+	require.True(t, active.IsActive())
+	require.Equal(t, uint64(5), active.StakeActiveEpoch.Uint64())
+	// 5 epochs at 7.3% a year
+	require.Equal(t, SUI(1).Uint64(), active.StakeStatus.Data.Active.EstimatedReward.Uint64())
+
+	pending := delegated[1].Stakes[0].Data
+	require.Equal(t, stakes[1].ObjectId, pending.StakedSuiId)
+	require.NotNil(t, pending.StakeStatus.Data.Pending)
 }
 
 func TestGetStakesByIds(t *testing.T) {
-	cli := TestnetClient(t)
-	owner, err := sui_types.NewAddressFromHex("0xd77955e670f42c1bc5e94b9e68e5fe9bdbed9134d784f2a14dfe5fc1b24b5d9f")
-	stakes, err := cli.GetStakes(context.Background(), *owner)
-	require.Nil(t, err)
-	require.GreaterOrEqual(t, len(stakes), 1)
-
-	stake1 := stakes[0].Stakes[0].Data
-	stakeId := stake1.StakedSuiId
-	stakesFromId, err := cli.GetStakesByIds(context.Background(), []suiObjectID{stakeId})
-	require.Nil(t, err)
-	require.GreaterOrEqual(t, len(stakesFromId), 1)
+	_, cli, owner, stakes := newStakeNode(t)
+	delegated, err := cli.GetStakes(context.Background(), owner)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(delegated), 1)
+
+	stake1 := delegated[0].Stakes[0].Data
+	stakesFromId, err := cli.GetStakesByIds(context.Background(), []suiObjectID{stake1.StakedSuiId})
+	require.NoError(t, err)
+	require.Len(t, stakesFromId, 1)
+	require.Equal(t, delegated[0].ValidatorAddress, stakesFromId[0].ValidatorAddress)
+	require.Equal(t, stake1, stakesFromId[0].Stakes[0].Data)
+
+	_, err = cli.GetStakesByIds(context.Background(), []suiObjectID{stakes[0].ObjectId, *sui_types.SuiSystemStateObjectId})
+	require.Error(t, err)
+}
 
-	queriedStake := stakesFromId[0].Stakes[0].Data
-	require.Equal(t, stake1, queriedStake)
-	t.Log(stakesFromId)
+func TestClient_GetLatestSuiSystemState(t *testing.T) {
+	_, cli, _, _ := newStakeNode(t)
+	state, err := cli.GetLatestSuiSystemState(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), state.Epoch.Uint64())
+	require.Len(t, state.ActiveValidators, 2)
+	require.Equal(t, "validator 1", state.ActiveValidators[0].Name)
+	require.Equal(t, SUI(1002).Uint64(), state.TotalStake.Uint64())
+
+	epoch, err := cli.GetCurrentEpoch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), epoch.Epoch.Uint64())
+	require.Len(t, epoch.Validators, 2)
 }
 
 func TestRequestAddDelegation(t *testing.T) {
@@ -125,3 +151,81 @@ func TestRequestWithdrawDelegation(t *testing.T) {
 
 	simulateCheck(t, cli, txBytes, true)
 }
+
+func decodeProgrammable(t *testing.T, txBytes []byte) *sui_types.ProgrammableTransaction {
+	var tx sui_types.TransactionData
+	err := lib.UnmarshalBCS(txBytes, &tx)
+	require.NoError(t, err)
+	require.NotNil(t, tx.V1.Kind.ProgrammableTransaction)
+	return tx.V1.Kind.ProgrammableTransaction
+}
+
+func moveCalls(pt *sui_types.ProgrammableTransaction) []move_types.Identifier {
+	var functions []move_types.Identifier
+	for _, command := range pt.Commands {
+		if command.MoveCall != nil {
+			functions = append(functions, command.MoveCall.Function)
+		}
+	}
+	return functions
+}
+
+func TestBCS_StakeBuilders(t *testing.T) {
+	_, signer := testAccount(t, 1)
+	validator, err := sui_types.NewAddressFromHex(ComingChatValidatorAddress)
+	require.NoError(t, err)
+	ref := func(id byte) *sui_types.ObjectRef {
+		return &sui_types.ObjectRef{ObjectId: sui_types.ObjectID{id}, Version: 1, Digest: make([]byte, 32)}
+	}
+	gas := []*sui_types.ObjectRef{ref(0xff)}
+	amount := types.NewSafeSuiBigInt(uint64(3_000_000_000))
+
+	txBytes, err := BCS_RequestAddStakeMulCoin(signer, []*sui_types.ObjectRef{ref(1), ref(2)}, &amount, *validator, gas, 1e7, 1000)
+	require.NoError(t, err)
+	pt := decodeProgrammable(t, txBytes)
+	require.Equal(t, []move_types.Identifier{sui_types.AddStakeMulCoinFunName}, moveCalls(pt))
+	require.NotNil(t, pt.Commands[0].MakeMoveVec)
+	require.Len(t, pt.Commands[0].MakeMoveVec.Arguments, 2)
+	someAmount, err := bcs.Marshal(move_types.Some(amount.Uint64()))
+	require.NoError(t, err)
+	require.Equal(t, someAmount, []byte(*pt.Inputs[3].Pure))
+	// all the coins
+	txBytes, err = BCS_RequestAddStakeMulCoin(signer, []*sui_types.ObjectRef{ref(1)}, nil, *validator, gas, 1e7, 1000)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0}, []byte(*decodeProgrammable(t, txBytes).Inputs[2].Pure))
+
+	txBytes, err = BCS_RequestAddStakeWithCoin(signer, ref(1), &amount, *validator, gas, 1e7, 1000)
+	require.NoError(t, err)
+	pt = decodeProgrammable(t, txBytes)
+	require.NotNil(t, pt.Commands[0].SplitCoins)
+	require.Nil(t, pt.Commands[0].SplitCoins.Argument.GasCoin)
+	require.Equal(t, []move_types.Identifier{sui_types.AddStakeFunName}, moveCalls(pt))
+
+	txBytes, err = BCS_SplitStakedSui(signer, ref(1), amount, gas, 1e7, 1000)
+	require.NoError(t, err)
+	require.Equal(t, []move_types.Identifier{sui_types.SplitStakedSuiFunName}, moveCalls(decodeProgrammable(t, txBytes)))
+
+	txBytes, err = BCS_JoinStakedSui(signer, ref(1), []*sui_types.ObjectRef{ref(2), ref(3)}, gas, 1e7, 1000)
+	require.NoError(t, err)
+	pt = decodeProgrammable(t, txBytes)
+	require.Equal(t, []move_types.Identifier{sui_types.JoinStakedSuiFunName, sui_types.JoinStakedSuiFunName}, moveCalls(pt))
+	require.Len(t, pt.Inputs, 3)
+
+	txBytes, err = BCS_RequestWithdrawStakePartial(signer, ref(1), amount, gas, 1e7, 1000)
+	require.NoError(t, err)
+	pt = decodeProgrammable(t, txBytes)
+	require.Equal(
+		t, []move_types.Identifier{
+			sui_types.SplitFunName, sui_types.WithdrawStakeNonEntryFunName, sui_types.CoinFromBalanceFunName,
+		}, moveCalls(pt),
+	)
+	require.NotNil(t, pt.Commands[3].TransferObjects)
+
+	small := types.NewSafeSuiBigInt(MinStakingThreshold - 1)
+	_, err = BCS_SplitStakedSui(signer, ref(1), small, gas, 1e7, 1000)
+	require.ErrorIs(t, err, ErrStakeBelowThreshold)
+	_, err = BCS_RequestAddStakeWithCoin(signer, ref(1), &small, *validator, gas, 1e7, 1000)
+	require.ErrorIs(t, err, ErrStakeBelowThreshold)
+	_, err = BCS_RequestAddStakeMulCoin(signer, nil, nil, *validator, gas, 1e7, 1000)
+	require.Error(t, err)
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+// SubscribeEvent subscribes to events matching filter, they are delivered in the order the node emits them.
+func (c *WebsocketClient) SubscribeEvent(
+	ctx context.Context,
+	filter types.EventFilter,
+) (*Subscription[types.SuiEvent], error) {
+	return subscribeWith(ctx, c, subscribeEvent, unsubscribeEvent, decodeJson[types.SuiEvent], filter)
+}
+
+// SubscribeTransaction subscribes to the effects of transactions matching filter.
+func (c *WebsocketClient) SubscribeTransaction(
+	ctx context.Context,
+	filter types.TransactionFilter,
+) (*Subscription[types.SuiTransactionBlockEffects], error) {
+	return subscribeWith(
+		ctx, c, subscribeTransaction, unsubscribeTransaction,
+		func(raw json.RawMessage) (types.SuiTransactionBlockEffects, error) {
+			var effects lib.TagJson[types.SuiTransactionBlockEffects]
+			err := json.Unmarshal(raw, &effects)
+			return effects.Data, err
+		}, filter,
+	)
+}
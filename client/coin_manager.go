@@ -0,0 +1,362 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+var ErrNoGasCoin = errors.New("no SUI coin can pay the gas budget")
+
+// DefaultCoinGasBudget is the gas budget of the transactions of a CoinManager, 0.05 SUI.
+const DefaultCoinGasBudget = 50_000_000
+
+// CoinStats describes how fragmented the coins of a type owned by an address are.
+type CoinStats struct {
+	CoinType string
+	Count    int
+	Total    *big.Int
+	Smallest uint64
+	Largest  uint64
+	Median   uint64
+	// DustCount and DustBalance are the coins below the dust threshold and their balance.
+	DustCount   int
+	DustBalance *big.Int
+	// MergeTransactions is how many transactions MergeDust needs to merge the dust.
+	MergeTransactions int
+}
+
+// Fragmented reports whether the coins are too many to be picked in a single transaction, when
+// types.PickupCoins may fail with types.ErrNeedMergeCoin.
+func (s *CoinStats) Fragmented() bool {
+	return s.Count > types.MAX_INPUT_COUNT_MERGE
+}
+
+// NewCoinStats computes the stats of coins, which are of the same type.
+func NewCoinStats(coinType string, coins types.Coins, dustThreshold uint64) *CoinStats {
+	stats := &CoinStats{CoinType: coinType, Count: len(coins), Total: coins.TotalBalance(), DustBalance: new(big.Int)}
+	if len(coins) == 0 {
+		return stats
+	}
+	balances := make([]uint64, len(coins))
+	for i, coin := range coins {
+		balances[i] = coin.Balance.Uint64()
+		if balances[i] < dustThreshold {
+			stats.DustCount++
+			stats.DustBalance.Add(stats.DustBalance, new(big.Int).SetUint64(balances[i]))
+		}
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i] < balances[j] })
+	stats.Smallest, stats.Largest, stats.Median = balances[0], balances[len(balances)-1], balances[len(balances)/2]
+	dust := stats.DustCount
+	if dust == len(coins) {
+		// the largest coin is the one the others are merged into
+		dust--
+	}
+	stats.MergeTransactions = (dust + types.MAX_INPUT_COUNT_MERGE - 1) / types.MAX_INPUT_COUNT_MERGE
+	return stats
+}
+
+// BCS_MergeCoins merges coins into target. A nil target merges them into the gas coin, which is
+// how SUI coins are merged. At most types.MAX_INPUT_COUNT_MERGE coins are merged in a transaction.
+func BCS_MergeCoins(
+	signer suiAddress,
+	target *sui_types.ObjectRef,
+	coins []*sui_types.ObjectRef,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	if len(coins) == 0 {
+		return nil, errors.New("no coins to merge")
+	}
+	if len(coins) > types.MAX_INPUT_COUNT_MERGE {
+		return nil, fmt.Errorf("cannot merge %d coins, the limit is %d", len(coins), types.MAX_INPUT_COUNT_MERGE)
+	}
+	ptb := sui_types.NewProgrammableTransactionBuilder()
+	targetArg := sui_types.Argument{GasCoin: &lib.EmptyEnum{}}
+	if target != nil {
+		var err error
+		if targetArg, err = ptb.Obj(sui_types.ObjectArg{ImmOrOwnedObject: target}); err != nil {
+			return nil, err
+		}
+	}
+	coinArgs := make([]sui_types.Argument, len(coins))
+	for i, coin := range coins {
+		coinArg, err := ptb.Obj(sui_types.ObjectArg{ImmOrOwnedObject: coin})
+		if err != nil {
+			return nil, err
+		}
+		coinArgs[i] = coinArg
+	}
+	ptb.Command(
+		sui_types.Command{
+			MergeCoins: &struct {
+				Argument  sui_types.Argument
+				Arguments []sui_types.Argument
+			}{Argument: targetArg, Arguments: coinArgs},
+		},
+	)
+	tx := sui_types.NewProgrammable(signer, gas, ptb.Finish(), gasBudget, gasPrice)
+	return bcs.Marshal(tx)
+}
+
+// BCS_SplitGasCoins splits count coins of size from the gas coin and sends them to the signer. At
+// most types.MAX_INPUT_COUNT_MERGE coins are split in a transaction.
+func BCS_SplitGasCoins(
+	signer suiAddress,
+	gas []*sui_types.ObjectRef,
+	count int,
+	size uint64,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	if count <= 0 || count > types.MAX_INPUT_COUNT_MERGE {
+		return nil, fmt.Errorf("cannot split %d coins, the limit is %d", count, types.MAX_INPUT_COUNT_MERGE)
+	}
+	ptb := sui_types.NewProgrammableTransactionBuilder()
+	sizeArg, err := ptb.Pure(size)
+	if err != nil {
+		return nil, err
+	}
+	sizeArgs := make([]sui_types.Argument, count)
+	for i := range sizeArgs {
+		sizeArgs[i] = sizeArg
+	}
+	split := ptb.Command(
+		sui_types.Command{
+			SplitCoins: &struct {
+				Argument  sui_types.Argument
+				Arguments []sui_types.Argument
+			}{Argument: sui_types.Argument{GasCoin: &lib.EmptyEnum{}}, Arguments: sizeArgs},
+		},
+	)
+	splitCoins := make([]sui_types.Argument, count)
+	for i := range splitCoins {
+		splitCoins[i] = sui_types.Argument{
+			NestedResult: &struct {
+				Result1 uint16
+				Result2 uint16
+			}{Result1: *split.Result, Result2: uint16(i)},
+		}
+	}
+	recipientArg, err := ptb.Pure(signer)
+	if err != nil {
+		return nil, err
+	}
+	ptb.Command(
+		sui_types.Command{
+			TransferObjects: &struct {
+				Arguments []sui_types.Argument
+				Argument  sui_types.Argument
+			}{Arguments: splitCoins, Argument: recipientArg},
+		},
+	)
+	tx := sui_types.NewProgrammable(signer, gas, ptb.Finish(), gasBudget, gasPrice)
+	return bcs.Marshal(tx)
+}
+
+type coinManagerConfig struct {
+	gasBudget uint64
+	options   []ExecuteOption
+}
+
+type CoinManagerOption func(*coinManagerConfig)
+
+// WithCoinGasBudget sets the gas budget of every transaction, DefaultCoinGasBudget by default.
+func WithCoinGasBudget(gasBudget uint64) CoinManagerOption {
+	return func(c *coinManagerConfig) {
+		c.gasBudget = gasBudget
+	}
+}
+
+// WithCoinExecuteOptions sets the options of SignAndExecuteTransaction.
+func WithCoinExecuteOptions(options ...ExecuteOption) CoinManagerOption {
+	return func(c *coinManagerConfig) {
+		c.options = options
+	}
+}
+
+// CoinManager keeps the coins of an address usable: it merges dust coins, which make
+// types.PickupCoins fail with types.ErrNeedMergeCoin, and splits SUI into a pool of gas coins for
+// transactions executed in parallel. Each transaction is executed before the next one is built
+// from the new coin versions.
+type CoinManager struct {
+	client *Client
+	signer TransactionSigner
+	owner  suiAddress
+	config coinManagerConfig
+}
+
+func NewCoinManager(client *Client, signer TransactionSigner, owner suiAddress, options ...CoinManagerOption) *CoinManager {
+	config := coinManagerConfig{gasBudget: DefaultCoinGasBudget}
+	for _, option := range options {
+		option(&config)
+	}
+	return &CoinManager{client: client, signer: signer, owner: owner, config: config}
+}
+
+// Coins returns all the coins of coinType owned by the address, an empty coinType is SUI.
+func (m *CoinManager) Coins(ctx context.Context, coinType string) (types.Coins, error) {
+	if coinType == "" {
+		coinType = types.SUI_COIN_TYPE
+	}
+	return m.client.GetCoinsIterator(m.owner, &coinType).Collect(ctx)
+}
+
+// Stats returns the fragmentation stats of the coins of coinType, coins with a balance below
+// dustThreshold are dust.
+func (m *CoinManager) Stats(ctx context.Context, coinType string, dustThreshold uint64) (*CoinStats, error) {
+	coins, err := m.Coins(ctx, coinType)
+	if err != nil {
+		return nil, err
+	}
+	if coinType == "" {
+		coinType = types.SUI_COIN_TYPE
+	}
+	return NewCoinStats(coinType, coins, dustThreshold), nil
+}
+
+// MergeDust merges the coins of coinType with a balance below dustThreshold into the largest one,
+// a zero threshold merges all the coins into one. It returns the responses of the executed
+// transactions, the last one along with the error that stopped it.
+func (m *CoinManager) MergeDust(
+	ctx context.Context,
+	coinType string,
+	dustThreshold uint64,
+) ([]*types.SuiTransactionBlockResponse, error) {
+	isSui := coinType == ""
+	if normalized, err := types.NormalizeStructType(coinType); err == nil {
+		isSui = normalized == types.SUI_COIN_TYPE
+	}
+	gasPrice, err := m.client.GetReferenceGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var responses []*types.SuiTransactionBlockResponse
+	for {
+		coins, err := m.Coins(ctx, coinType)
+		if err != nil {
+			return responses, err
+		}
+		if len(coins) < 2 {
+			return responses, nil
+		}
+		sort.SliceStable(coins, func(i, j int) bool { return coins[i].Balance.Uint64() > coins[j].Balance.Uint64() })
+		target := coins[0].Reference()
+		var dust []*sui_types.ObjectRef
+		for _, coin := range coins[1:] {
+			if dustThreshold == 0 || coin.Balance.Uint64() < dustThreshold {
+				dust = append(dust, coin.Reference())
+			}
+		}
+		if len(dust) == 0 {
+			return responses, nil
+		}
+		if len(dust) > types.MAX_INPUT_COUNT_MERGE {
+			dust = dust[:types.MAX_INPUT_COUNT_MERGE]
+		}
+
+		var txBytes []byte
+		if isSui {
+			// the largest coin pays the gas and the dust is merged into it
+			txBytes, err = BCS_MergeCoins(
+				m.owner, nil, dust, []*sui_types.ObjectRef{target}, m.config.gasBudget, gasPrice.Uint64(),
+			)
+		} else {
+			var gas *sui_types.ObjectRef
+			if gas, err = m.gasCoin(ctx); err == nil {
+				txBytes, err = BCS_MergeCoins(
+					m.owner, target, dust, []*sui_types.ObjectRef{gas}, m.config.gasBudget, gasPrice.Uint64(),
+				)
+			}
+		}
+		if err != nil {
+			return responses, err
+		}
+		resp, err := m.execute(ctx, txBytes)
+		if resp != nil {
+			responses = append(responses, resp)
+		}
+		if err != nil {
+			return responses, err
+		}
+	}
+}
+
+// SplitGasCoins splits count SUI coins of size from the largest SUI coin, which pays the gas. More
+// than types.MAX_INPUT_COUNT_MERGE coins are split in several transactions.
+func (m *CoinManager) SplitGasCoins(
+	ctx context.Context,
+	count int,
+	size uint64,
+) ([]*types.SuiTransactionBlockResponse, error) {
+	if count <= 0 || size == 0 {
+		return nil, errors.New("the count and size of the gas coins must be positive")
+	}
+	gasPrice, err := m.client.GetReferenceGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var responses []*types.SuiTransactionBlockResponse
+	for count > 0 {
+		batch := count
+		if batch > types.MAX_INPUT_COUNT_MERGE {
+			batch = types.MAX_INPUT_COUNT_MERGE
+		}
+		need := new(big.Int).Mul(new(big.Int).SetUint64(size), big.NewInt(int64(batch)))
+		need.Add(need, new(big.Int).SetUint64(m.config.gasBudget))
+		gas, err := m.largestCoin(ctx, need)
+		if err != nil {
+			return responses, err
+		}
+		txBytes, err := BCS_SplitGasCoins(
+			m.owner, []*sui_types.ObjectRef{gas}, batch, size, m.config.gasBudget, gasPrice.Uint64(),
+		)
+		if err != nil {
+			return responses, err
+		}
+		resp, err := m.execute(ctx, txBytes)
+		if resp != nil {
+			responses = append(responses, resp)
+		}
+		if err != nil {
+			return responses, err
+		}
+		count -= batch
+	}
+	return responses, nil
+}
+
+// gasCoin returns the largest SUI coin if it can pay the gas budget.
+func (m *CoinManager) gasCoin(ctx context.Context) (*sui_types.ObjectRef, error) {
+	return m.largestCoin(ctx, new(big.Int).SetUint64(m.config.gasBudget))
+}
+
+func (m *CoinManager) largestCoin(ctx context.Context, atLeast *big.Int) (*sui_types.ObjectRef, error) {
+	coins, err := m.Coins(ctx, types.SUI_COIN_TYPE)
+	if err != nil {
+		return nil, err
+	}
+	var largest *types.Coin
+	for i := range coins {
+		if largest == nil || coins[i].Balance.Uint64() > largest.Balance.Uint64() {
+			largest = &coins[i]
+		}
+	}
+	if largest == nil || new(big.Int).SetUint64(largest.Balance.Uint64()).Cmp(atLeast) < 0 {
+		return nil, ErrNoGasCoin
+	}
+	return largest.Reference(), nil
+}
+
+func (m *CoinManager) execute(ctx context.Context, txBytes []byte) (*types.SuiTransactionBlockResponse, error) {
+	// the object changes make the node wait for the local execution, the next coins read sees them
+	options := &types.SuiTransactionBlockResponseOptions{ShowEffects: true, ShowObjectChanges: true}
+	return m.client.SignAndExecuteTransaction(ctx, m.signer, txBytes, options, m.config.options...)
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/mocknode"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+const testUsdcType = "0xa1ec7fc00a6f40db9693ad1415d0c193ad3906494428cf252621037bd7117e29::usdc::USDC"
+
+func newTestCoinManager(t *testing.T) (*mocknode.Node, *CoinManager, sui_types.SuiAddress) {
+	node := mocknode.New(mocknode.WithGasCost(1000, 1000))
+	t.Cleanup(node.Close)
+	cli, err := Dial(node.URL())
+	require.NoError(t, err)
+	signer, owner := testAccount(t, 7)
+	return node, NewCoinManager(cli, signer, owner, WithCoinGasBudget(10_000_000)), owner
+}
+
+func TestCoinManager_MergeDust(t *testing.T) {
+	node, manager, owner := newTestCoinManager(t)
+	ctx := context.Background()
+	node.Mint(owner, "", 1_000_000_000)
+	for i := 0; i < 300; i++ {
+		node.Mint(owner, "", 10)
+	}
+
+	stats, err := manager.Stats(ctx, "", 100)
+	require.NoError(t, err)
+	require.Equal(t, 301, stats.Count)
+	require.True(t, stats.Fragmented())
+	require.Equal(t, 300, stats.DustCount)
+	require.Equal(t, "3000", stats.DustBalance.String())
+	require.Equal(t, uint64(10), stats.Median)
+	require.Equal(t, 2, stats.MergeTransactions)
+
+	responses, err := manager.MergeDust(ctx, "", 100)
+	require.NoError(t, err)
+	require.Len(t, responses, 2)
+	coins, err := manager.Coins(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, coins, 1)
+	fees := int64(0)
+	for _, resp := range responses {
+		fees += resp.Effects.Data.GasFee()
+	}
+	require.Equal(t, int64(1_000_003_000)-fees, int64(coins[0].Balance.Uint64()))
+
+	for i := 0; i < 3; i++ {
+		node.Mint(owner, testUsdcType, uint64(i+1))
+	}
+	node.Mint(owner, testUsdcType, 1000)
+	responses, err = manager.MergeDust(ctx, testUsdcType, 0)
+	require.NoError(t, err)
+	require.Len(t, responses, 1)
+	usdc, err := manager.Coins(ctx, testUsdcType)
+	require.NoError(t, err)
+	require.Len(t, usdc, 1)
+	require.Equal(t, uint64(1006), usdc[0].Balance.Uint64())
+}
+
+func TestCoinManager_SplitGasCoins(t *testing.T) {
+	node, manager, owner := newTestCoinManager(t)
+	ctx := context.Background()
+	node.Mint(owner, "", 100_000_000)
+	node.Mint(owner, "", 5_000)
+
+	_, err := manager.SplitGasCoins(ctx, 300, 1_000_000)
+	require.ErrorIs(t, err, ErrNoGasCoin)
+
+	responses, err := manager.SplitGasCoins(ctx, 260, 100_000)
+	require.NoError(t, err)
+	require.Len(t, responses, 2)
+	coins, err := manager.Coins(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, coins, 262)
+	stats := NewCoinStats(types.SUI_COIN_TYPE, coins, 100_001)
+	require.Equal(t, 261, stats.DustCount)
+	require.Equal(t, uint64(5_000), stats.Smallest)
+}
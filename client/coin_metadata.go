@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+var ErrCoinMetadataNotFound = errors.New("coin type has no metadata")
+
+func coinMetadataKey(coinType string) string {
+	if coinType == "" {
+		return types.SUI_COIN_TYPE
+	}
+	if normalized, err := types.NormalizeStructType(coinType); err == nil {
+		return normalized
+	}
+	return coinType
+}
+
+// CoinMetadata returns the metadata of coinType, an empty coinType is SUI. The metadata is cached by
+// the client, a coin type without metadata fails with ErrCoinMetadataNotFound and is not cached.
+func (c *Client) CoinMetadata(ctx context.Context, coinType string) (*types.SuiCoinMetadata, error) {
+	key := coinMetadataKey(coinType)
+	c.metadataLock.Lock()
+	metadata, ok := c.coinMetadata[key]
+	c.metadataLock.Unlock()
+	if ok {
+		return metadata, nil
+	}
+
+	if err := c.CallContext(ctx, &metadata, getCoinMetadata, key); err != nil {
+		return nil, err
+	}
+	if metadata == nil {
+		return nil, fmt.Errorf("%w: %s", ErrCoinMetadataNotFound, key)
+	}
+	c.metadataLock.Lock()
+	defer c.metadataLock.Unlock()
+	if c.coinMetadata == nil {
+		c.coinMetadata = make(map[string]*types.SuiCoinMetadata)
+	}
+	c.coinMetadata[key] = metadata
+	return metadata, nil
+}
+
+// FormatCoinAmount formats base units of coinType with the decimals and symbol of its metadata,
+// like "1.5 SUI".
+func (c *Client) FormatCoinAmount(ctx context.Context, coinType string, amount *big.Int) (string, error) {
+	metadata, err := c.CoinMetadata(ctx, coinType)
+	if err != nil {
+		return "", err
+	}
+	return metadata.FormatAmount(amount), nil
+}
+
+// ParseCoinAmount parses a human amount of coinType, like "1.5", into base units with the decimals
+// of its metadata.
+func (c *Client) ParseCoinAmount(ctx context.Context, coinType string, amount string) (*big.Int, error) {
+	metadata, err := c.CoinMetadata(ctx, coinType)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.ParseAmount(amount)
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+func TestClient_CoinMetadata(t *testing.T) {
+	standIn := newRpcStandIn(
+		t, map[string]rpcHandler{
+			getCoinMetadata.String(): func(params []json.RawMessage) (interface{}, error) {
+				var coinType string
+				_ = json.Unmarshal(params[0], &coinType)
+				if coinType != types.SUI_COIN_TYPE {
+					return nil, nil
+				}
+				return types.SuiCoinMetadata{Decimals: 9, Name: "Sui", Symbol: "SUI"}, nil
+			},
+		},
+	)
+	cli := standIn.client(t)
+	ctx := context.Background()
+
+	metadata, err := cli.CoinMetadata(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, uint8(9), metadata.Decimals)
+	formatted, err := cli.FormatCoinAmount(
+		ctx, "0x0000000000000000000000000000000000000000000000000000000000000002::sui::SUI", big.NewInt(2_500_000_000),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "2.5 SUI", formatted)
+	parsed, err := cli.ParseCoinAmount(ctx, types.SUI_COIN_TYPE, "0.001")
+	require.NoError(t, err)
+	require.Equal(t, int64(1_000_000), parsed.Int64())
+	require.Equal(t, 1, standIn.callCount(getCoinMetadata.String()))
+
+	// a coin type without metadata is asked again
+	for i := 0; i < 2; i++ {
+		_, err = cli.CoinMetadata(ctx, "0x5::fake::FAKE")
+		require.ErrorIs(t, err, ErrCoinMetadataNotFound)
+	}
+	require.Equal(t, 3, standIn.callCount(getCoinMetadata.String()))
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+type coinSelectionConfig struct {
+	selector types.CoinSelector
+	limit    int
+}
+
+// CoinSelectionOption configures how the payment helpers select the coins they pay with.
+type CoinSelectionOption func(*coinSelectionConfig)
+
+// WithCoinSelector sets the selector of the coins, types.DefaultCoinSelector by default.
+func WithCoinSelector(selector types.CoinSelector) CoinSelectionOption {
+	return func(c *coinSelectionConfig) {
+		c.selector = selector
+	}
+}
+
+// WithMaxInputCoins sets the max number of coins selected, types.MAX_INPUT_COUNT_MERGE by default.
+func WithMaxInputCoins(limit int) CoinSelectionOption {
+	return func(c *coinSelectionConfig) {
+		c.limit = limit
+	}
+}
+
+func newCoinSelectionConfig(opts []CoinSelectionOption) *coinSelectionConfig {
+	config := &coinSelectionConfig{selector: types.DefaultCoinSelector}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+func totalAmount(amounts []uint64) *big.Int {
+	total := new(big.Int)
+	for _, amount := range amounts {
+		total.Add(total, new(big.Int).SetUint64(amount))
+	}
+	return total
+}
+
+func totalSafeAmount(amounts []types.SafeSuiBigInt[uint64]) *big.Int {
+	total := new(big.Int)
+	for _, amount := range amounts {
+		total.Add(total, new(big.Int).SetUint64(amount.Uint64()))
+	}
+	return total
+}
+
+// SelectCoins selects coins of coinType owned by owner covering amount and gasBudget with selector,
+// types.DefaultCoinSelector when it is nil, from all the pages of coins, at most limit of them,
+// types.MAX_INPUT_COUNT_MERGE by default. An empty coinType is SUI. The coins are given to Pay and
+// PaySui by PickedCoins.CoinIds, and to the ProgrammableTransactionBuilder by PickedCoins.CoinRefs.
+// It fails like types.PickupCoinsWith.
+func (c *Client) SelectCoins(
+	ctx context.Context,
+	owner suiAddress,
+	coinType string,
+	amount *big.Int,
+	gasBudget uint64,
+	selector types.CoinSelector,
+	limit int,
+) (*types.PickedCoins, error) {
+	if coinType == "" {
+		coinType = types.SUI_COIN_TYPE
+	}
+	if selector == nil {
+		selector = types.DefaultCoinSelector
+	}
+	coins, err := c.GetCoinsIterator(owner, &coinType).Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return types.PickupCoinsWith(selector, &types.CoinPage{Data: coins}, *amount, gasBudget, limit)
+}
+
+// PayWithSelection is Pay with the coins of coinType of the signer covering the amounts, selected
+// as configured by opts. The gas is paid by gas, or by a SUI coin the node picks when it is nil.
+func (c *Client) PayWithSelection(
+	ctx context.Context,
+	signer suiAddress,
+	coinType string,
+	recipients []suiAddress,
+	amounts []types.SafeSuiBigInt[uint64],
+	gas *suiObjectID,
+	gasBudget types.SafeSuiBigInt[uint64],
+	opts ...CoinSelectionOption,
+) (*types.TransactionBytes, error) {
+	config := newCoinSelectionConfig(opts)
+	picked, err := c.SelectCoins(ctx, signer, coinType, totalSafeAmount(amounts), 0, config.selector, config.limit)
+	if err != nil {
+		return nil, err
+	}
+	return c.Pay(ctx, signer, picked.CoinIds(), recipients, amounts, gas, gasBudget)
+}
+
+// PaySuiWithSelection is PaySui with the SUI coins of the signer covering the amounts and the gas
+// budget, selected as configured by opts.
+func (c *Client) PaySuiWithSelection(
+	ctx context.Context,
+	signer suiAddress,
+	recipients []suiAddress,
+	amounts []types.SafeSuiBigInt[uint64],
+	gasBudget types.SafeSuiBigInt[uint64],
+	opts ...CoinSelectionOption,
+) (*types.TransactionBytes, error) {
+	config := newCoinSelectionConfig(opts)
+	picked, err := c.SelectCoins(
+		ctx, signer, "", totalSafeAmount(amounts), gasBudget.Uint64(), config.selector, config.limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return c.PaySui(ctx, signer, picked.CoinIds(), recipients, amounts, gasBudget)
+}
+
+// BCS_PayWithSelection pays the amounts to the recipients with coins selected from coins as
+// configured by opts, all of a single type which is not paying the gas.
+func BCS_PayWithSelection(
+	signer suiAddress,
+	coins types.Coins,
+	recipients []suiAddress,
+	amounts []uint64,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+	opts ...CoinSelectionOption,
+) ([]byte, error) {
+	config := newCoinSelectionConfig(opts)
+	picked, err := types.PickupCoinsWith(
+		config.selector, &types.CoinPage{Data: coins}, *totalAmount(amounts), 0, config.limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	ptb := sui_types.NewProgrammableTransactionBuilder()
+	if err := ptb.Pay(picked.CoinRefs(), recipients, amounts); err != nil {
+		return nil, err
+	}
+	tx := sui_types.NewProgrammable(signer, gas, ptb.Finish(), gasBudget, gasPrice)
+	return bcs.Marshal(tx)
+}
+
+// BCS_PaySuiWithSelection pays the amounts to the recipients from the gas coins, selected from
+// the SUI coins as configured by opts to cover the amounts and the gas budget.
+func BCS_PaySuiWithSelection(
+	signer suiAddress,
+	coins types.Coins,
+	recipients []suiAddress,
+	amounts []uint64,
+	gasBudget, gasPrice uint64,
+	opts ...CoinSelectionOption,
+) ([]byte, error) {
+	config := newCoinSelectionConfig(opts)
+	picked, err := types.PickupCoinsWith(
+		config.selector, &types.CoinPage{Data: coins}, *totalAmount(amounts), gasBudget, config.limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	// the coins must pay the gas budget too, not only the amounts
+	change := new(big.Int).Sub(&picked.TotalAmount, &picked.TargetAmount)
+	if change.Cmp(new(big.Int).SetUint64(gasBudget)) < 0 {
+		return nil, types.ErrInsufficientBalance
+	}
+	ptb := sui_types.NewProgrammableTransactionBuilder()
+	if err := ptb.PaySui(recipients, amounts); err != nil {
+		return nil, err
+	}
+	tx := sui_types.NewProgrammable(signer, picked.CoinRefs(), ptb.Finish(), gasBudget, gasPrice)
+	return bcs.Marshal(tx)
+}
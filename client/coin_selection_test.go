@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+func TestClient_SelectCoins(t *testing.T) {
+	node, manager, owner := newTestCoinManager(t)
+	ctx := context.Background()
+	for _, balance := range []uint64{700, 400, 350, 250, 5} {
+		node.Mint(owner, testUsdcType, balance)
+	}
+
+	picked, err := manager.client.SelectCoins(ctx, owner, testUsdcType, big.NewInt(600), 0, types.BestFit{}, 0)
+	require.NoError(t, err)
+	require.Equal(t, "600", picked.TotalAmount.String())
+	require.Len(t, picked.CoinRefs(), 2)
+	picked, err = manager.client.SelectCoins(ctx, owner, testUsdcType, big.NewInt(600), 0, nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, "700", picked.TotalAmount.String())
+	_, err = manager.client.SelectCoins(ctx, owner, testUsdcType, big.NewInt(1500), 0, types.SmallestFirst{}, 2)
+	require.ErrorIs(t, err, types.ErrNeedMergeCoin)
+	_, err = manager.client.SelectCoins(ctx, owner, "", big.NewInt(1), 0, types.SmallestFirst{}, 0)
+	require.ErrorIs(t, err, types.ErrNoCoinsFound)
+}
+
+func TestClient_PaySuiWithSelection(t *testing.T) {
+	ledger := newCoinLedger(10)
+	var inputCoins []suiObjectID
+	standIn := newRpcStandIn(
+		t, map[string]rpcHandler{
+			getCoins.String(): ledger.getCoins,
+			paySui.String(): func(params []json.RawMessage) (interface{}, error) {
+				require.NoError(t, json.Unmarshal(params[1], &inputCoins))
+				return types.TransactionBytes{}, nil
+			},
+		},
+	)
+	cli := standIn.client(t)
+	_, recipient := testAccount(t, 9)
+	recipients := []suiAddress{recipient}
+	amounts := []types.SafeSuiBigInt[uint64]{types.NewSafeSuiBigInt(uint64(10))}
+
+	// the balances are 0 to 9, the coins pay 10 and a gas budget of 5
+	_, err := cli.PaySuiWithSelection(context.Background(), sui_types.SuiAddress{}, recipients, amounts, types.NewSafeSuiBigInt(uint64(5)))
+	require.NoError(t, err)
+	require.Equal(t, []suiObjectID{ledger.coins[9].CoinObjectId, ledger.coins[8].CoinObjectId}, inputCoins)
+	_, err = cli.PaySuiWithSelection(
+		context.Background(), sui_types.SuiAddress{}, recipients, amounts, types.NewSafeSuiBigInt(uint64(5)),
+		WithCoinSelector(types.SmallestFirst{}), WithMaxInputCoins(1),
+	)
+	require.ErrorIs(t, err, types.ErrNeedMergeCoin)
+}
+
+func TestBCS_PayWithSelection(t *testing.T) {
+	node, cli, signer, owner := newTestExecutor(t)
+	ctx := context.Background()
+	_, recipient := testAccount(t, 9)
+	for _, balance := range []uint64{300, 200, 100} {
+		node.Mint(owner, testUsdcType, balance)
+	}
+	gas := node.Mint(owner, "", 1_000_000_000)
+	node.Mint(owner, "", 1_000_000_000)
+
+	usdcType := testUsdcType
+	usdc, err := cli.GetCoinsIterator(owner, &usdcType).Collect(ctx)
+	require.NoError(t, err)
+	txBytes, err := BCS_PayWithSelection(
+		owner, usdc, []suiAddress{recipient}, []uint64{250}, []*sui_types.ObjectRef{&gas}, 10_000_000, 1000,
+		WithCoinSelector(types.BestFit{}),
+	)
+	require.NoError(t, err)
+	resp, err := cli.SignAndExecuteTransaction(ctx, signer, txBytes, nil)
+	require.NoError(t, err)
+	require.True(t, resp.Effects.Data.IsSuccess())
+
+	sui, err := cli.GetSuiCoinsOwnedByAddress(ctx, owner)
+	require.NoError(t, err)
+	txBytes, err = BCS_PaySuiWithSelection(owner, sui, []suiAddress{recipient}, []uint64{1_500_000_000}, 10_000_000, 1000)
+	require.NoError(t, err)
+	resp, err = cli.SignAndExecuteTransaction(ctx, signer, txBytes, nil)
+	require.NoError(t, err)
+	require.True(t, resp.Effects.Data.IsSuccess())
+	_, err = BCS_PaySuiWithSelection(owner, sui, []suiAddress{recipient}, []uint64{1_995_000_000}, 10_000_000, 1000)
+	require.ErrorIs(t, err, types.ErrInsufficientBalance)
+
+	balance, err := cli.GetBalance(ctx, recipient, testUsdcType)
+	require.NoError(t, err)
+	require.Equal(t, "250", balance.TotalBalance.String())
+	balance, err = cli.GetBalance(ctx, recipient, "")
+	require.NoError(t, err)
+	require.Equal(t, "1500000000", balance.TotalBalance.String())
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+// EventCursorStore persists the id of the last event an EventStream has delivered.
+type EventCursorStore interface {
+	// Load returns the saved cursor, or nil if nothing was saved yet.
+	Load(ctx context.Context) (*types.EventId, error)
+	Save(ctx context.Context, cursor types.EventId) error
+}
+
+type MemoryCursorStore struct {
+	lock   sync.Mutex
+	cursor *types.EventId
+}
+
+func NewMemoryCursorStore() *MemoryCursorStore {
+	return &MemoryCursorStore{}
+}
+
+func (s *MemoryCursorStore) Load(ctx context.Context) (*types.EventId, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.cursor == nil {
+		return nil, nil
+	}
+	cursor := *s.cursor
+	return &cursor, nil
+}
+
+func (s *MemoryCursorStore) Save(ctx context.Context, cursor types.EventId) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.cursor = &cursor
+	return nil
+}
+
+// FileCursorStore keeps the cursor as json in a file, it is replaced atomically on every save.
+type FileCursorStore struct {
+	lock sync.Mutex
+	path string
+}
+
+func NewFileCursorStore(path string) *FileCursorStore {
+	return &FileCursorStore{path: path}
+}
+
+func (s *FileCursorStore) Load(ctx context.Context) (*types.EventId, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cursor types.EventId
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+func (s *FileCursorStore) Save(ctx context.Context, cursor types.EventId) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
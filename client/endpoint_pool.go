@@ -0,0 +1,380 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+var ErrNoEndpoints = errors.New("endpoint pool has no rpc url")
+
+// overloadBackoff is how long an overloaded endpoint is skipped when it sends no Retry-After.
+const overloadBackoff = time.Second
+
+// RoutingPolicy decides which healthy endpoint of an EndpointPool serves a request first.
+type RoutingPolicy int
+
+const (
+	// RoutingRoundRobin spreads the requests over the healthy endpoints in turn.
+	RoutingRoundRobin RoutingPolicy = iota
+	// RoutingLowestLatency sends every request to the endpoint with the lowest health check latency.
+	RoutingLowestLatency
+)
+
+// writeMethods are the methods whose effects a following read expects to see.
+var writeMethods = map[string]bool{
+	executeTransactionBlock.String(): true,
+}
+
+type poolConfig struct {
+	httpClient          *http.Client
+	policy              RoutingPolicy
+	healthCheckInterval time.Duration
+	healthCheckTimeout  time.Duration
+	maxCheckpointLag    uint64
+}
+
+type PoolOption func(*poolConfig)
+
+func WithPoolHTTPClient(hc *http.Client) PoolOption {
+	return func(c *poolConfig) {
+		c.httpClient = hc
+	}
+}
+
+func WithRoutingPolicy(policy RoutingPolicy) PoolOption {
+	return func(c *poolConfig) {
+		c.policy = policy
+	}
+}
+
+// WithHealthCheckInterval sets how often every endpoint is asked for its latest checkpoint, and
+// how long a check may take. A zero interval disables the background checks, CheckHealth still runs
+// them on demand.
+func WithHealthCheckInterval(interval, timeout time.Duration) PoolOption {
+	return func(c *poolConfig) {
+		c.healthCheckInterval = interval
+		c.healthCheckTimeout = timeout
+	}
+}
+
+// WithMaxCheckpointLag sets how many checkpoints an endpoint may be behind the freshest one and
+// still be routed to.
+func WithMaxCheckpointLag(lag uint64) PoolOption {
+	return func(c *poolConfig) {
+		c.maxCheckpointLag = lag
+	}
+}
+
+// EndpointStatus is the state of an endpoint as of its last health check or request.
+type EndpointStatus struct {
+	RpcUrl     string
+	Healthy    bool
+	Checkpoint uint64
+	Latency    time.Duration
+	// Err is the error which marked the endpoint unhealthy.
+	Err error
+}
+
+type poolEndpoint struct {
+	EndpointStatus
+	checker *Client
+	// backoffUntil is when an endpoint answering it is overloaded is routed to again.
+	backoffUntil time.Time
+}
+
+// EndpointPool routes the requests of a Client among several nodes. The endpoints are health
+// checked with GetLatestCheckpointSequenceNumber, requests go to the healthy ones which are at most
+// the max checkpoint lag behind the freshest, and fail over to the next endpoint on transport errors.
+// An endpoint answering it is overloaded is skipped for a while, without being marked unhealthy.
+//
+// After a transaction is executed through an endpoint, reads are pinned to that endpoint until the
+// others report the checkpoint of the transaction, so they do not miss the write for lagging behind.
+// While the transaction is not in a checkpoint yet, the others have to report a checkpoint later
+// than the writer had before the write.
+type EndpointPool struct {
+	config poolConfig
+
+	lock      sync.Mutex
+	endpoints []*poolEndpoint
+	next      int
+	// writer is the endpoint of the last write, it serves the reads until the endpoints reach the
+	// write checkpoint.
+	writer          *poolEndpoint
+	writeCheckpoint uint64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewEndpointPool checks the health of the endpoints in the background until Close.
+func NewEndpointPool(rpcUrls []string, options ...PoolOption) (*EndpointPool, error) {
+	if len(rpcUrls) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	config := poolConfig{
+		httpClient:          newHTTPClient(),
+		healthCheckInterval: 10 * time.Second,
+		healthCheckTimeout:  5 * time.Second,
+		maxCheckpointLag:    10,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+	pool := &EndpointPool{config: config, done: make(chan struct{})}
+	for _, rpcUrl := range rpcUrls {
+		rpcUrl = strings.TrimRight(rpcUrl, "/")
+		checker, err := DialWithClient(rpcUrl, config.httpClient)
+		if err != nil {
+			return nil, err
+		}
+		pool.endpoints = append(
+			pool.endpoints, &poolEndpoint{
+				EndpointStatus: EndpointStatus{RpcUrl: rpcUrl, Healthy: true},
+				checker:        checker,
+			},
+		)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool.cancel = cancel
+	if config.healthCheckInterval > 0 {
+		go pool.checkLoop(ctx)
+	} else {
+		close(pool.done)
+	}
+	return pool, nil
+}
+
+// DialPool returns a Client sending its requests through pool.
+func DialPool(pool *EndpointPool, options ...ClientOption) (*Client, error) {
+	client := &Client{client: pool.config.httpClient}
+	newClientConfig(options).apply(client, pool.send)
+	return client, nil
+}
+
+// Close stops the background health checks.
+func (p *EndpointPool) Close() {
+	p.cancel()
+	<-p.done
+}
+
+// Endpoints returns the status of every endpoint.
+func (p *EndpointPool) Endpoints() []EndpointStatus {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	statuses := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		statuses[i] = e.EndpointStatus
+	}
+	return statuses
+}
+
+// CheckHealth checks every endpoint now.
+func (p *EndpointPool) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func(e *poolEndpoint) {
+			defer wg.Done()
+			p.checkEndpoint(ctx, e)
+		}(e)
+	}
+	wg.Wait()
+}
+
+func (p *EndpointPool) checkLoop(ctx context.Context) {
+	defer close(p.done)
+	for {
+		p.CheckHealth(ctx)
+		if err := sleepContext(ctx, p.config.healthCheckInterval); err != nil {
+			return
+		}
+	}
+}
+
+func (p *EndpointPool) checkEndpoint(ctx context.Context, e *poolEndpoint) {
+	ctx, cancel := context.WithTimeout(ctx, p.config.healthCheckTimeout)
+	defer cancel()
+	start := time.Now()
+	checkpoint, err := e.checker.GetLatestCheckpointSequenceNumber(ctx)
+	latency := time.Since(start)
+	var sequence uint64
+	if err == nil {
+		sequence, err = strconv.ParseUint(checkpoint, 10, 64)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if err != nil {
+		e.Healthy = false
+		e.Err = err
+		return
+	}
+	e.Healthy = true
+	e.Err = nil
+	e.Checkpoint = sequence
+	if e.Latency == 0 {
+		e.Latency = latency
+	} else {
+		e.Latency = (e.Latency*7 + latency*3) / 10
+	}
+}
+
+// candidates orders the endpoints to try for a request, the routable ones first and the others as
+// a last resort, freshest first.
+func (p *EndpointPool) candidates(write bool) []*poolEndpoint {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var freshest uint64
+	for _, e := range p.endpoints {
+		if e.Healthy && e.Checkpoint > freshest {
+			freshest = e.Checkpoint
+		}
+	}
+	minCheckpoint := uint64(0)
+	if freshest > p.config.maxCheckpointLag {
+		minCheckpoint = freshest - p.config.maxCheckpointLag
+	}
+	if !write && p.writeCheckpoint > minCheckpoint {
+		minCheckpoint = p.writeCheckpoint
+	}
+
+	// the endpoint a transaction was executed through has it before reporting its checkpoint, so a
+	// read always has a routable endpoint while that one is healthy
+	now := time.Now()
+	var routable, others []*poolEndpoint
+	for _, e := range p.endpoints {
+		pinned := !write && e == p.writer
+		if e.Healthy && (e.Checkpoint >= minCheckpoint || pinned) && !now.Before(e.backoffUntil) {
+			routable = append(routable, e)
+		} else {
+			others = append(others, e)
+		}
+	}
+
+	switch p.config.policy {
+	case RoutingLowestLatency:
+		sort.SliceStable(
+			routable, func(i, j int) bool {
+				return routable[i].Latency < routable[j].Latency
+			},
+		)
+	default:
+		if len(routable) > 0 {
+			start := p.next % len(routable)
+			rotated := make([]*poolEndpoint, 0, len(routable))
+			routable = append(append(rotated, routable[start:]...), routable[:start]...)
+			p.next++
+		}
+	}
+	sort.SliceStable(
+		others, func(i, j int) bool {
+			if others[i].Healthy != others[j].Healthy {
+				return others[i].Healthy
+			}
+			return others[i].Checkpoint > others[j].Checkpoint
+		},
+	)
+	return append(routable, others...)
+}
+
+func (p *EndpointPool) send(ctx context.Context, req *Request) ([]byte, error) {
+	write := false
+	for _, method := range req.Methods {
+		write = write || writeMethods[method]
+	}
+	var lastErr error
+	for _, e := range p.candidates(write) {
+		spanFromContext(ctx).SetAttribute(AttributeRpcEndpoint, e.RpcUrl)
+		body, err := postRequest(ctx, p.config.httpClient, e.RpcUrl, req)
+		if err == nil {
+			if write {
+				p.recordWrite(e, body)
+			}
+			return body, nil
+		}
+		if !isRetryableHTTPError(ctx, err) {
+			return nil, err
+		}
+		if errors.Is(err, types.ErrNodeOverloaded) {
+			p.backOff(e, err)
+		} else {
+			p.markUnhealthy(e, err)
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// recordWrite pins the reads to e until the endpoints reach the checkpoint of the transactions in
+// body, or the checkpoint after the one e had when they are not in a checkpoint yet.
+func (p *EndpointPool) recordWrite(e *poolEndpoint, body []byte) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	checkpoint, ok := executedCheckpoint(body)
+	if !ok {
+		checkpoint = e.Checkpoint + 1
+	}
+	p.writer = e
+	if checkpoint > p.writeCheckpoint {
+		p.writeCheckpoint = checkpoint
+	}
+}
+
+// executedCheckpoint returns the latest checkpoint of the transaction block responses in body, a
+// single response or a batch.
+func executedCheckpoint(body []byte) (uint64, bool) {
+	var msgs []jsonrpcMessage
+	if err := json.Unmarshal(body, &msgs); err != nil {
+		var msg jsonrpcMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return 0, false
+		}
+		msgs = []jsonrpcMessage{msg}
+	}
+	var checkpoint uint64
+	found := false
+	for _, msg := range msgs {
+		var resp struct {
+			Checkpoint *types.SafeSuiBigInt[uint64] `json:"checkpoint"`
+		}
+		if json.Unmarshal(msg.Result, &resp) != nil || resp.Checkpoint == nil {
+			continue
+		}
+		if !found || resp.Checkpoint.Uint64() > checkpoint {
+			checkpoint = resp.Checkpoint.Uint64()
+		}
+		found = true
+	}
+	return checkpoint, found
+}
+
+// backOff skips the overloaded endpoint for its Retry-After, or the overload backoff, keeping it
+// healthy.
+func (p *EndpointPool) backOff(e *poolEndpoint, err error) {
+	delay := retryAfterDelay(err)
+	if delay <= 0 {
+		delay = overloadBackoff
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	e.backoffUntil = time.Now().Add(delay)
+}
+
+// markUnhealthy takes the endpoint out of the routing until its next successful health check.
+func (p *EndpointPool) markUnhealthy(e *poolEndpoint, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	e.Healthy = false
+	e.Err = err
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// poolNode is a node of the endpoint pool tests, it answers every call with its name.
+type poolNode struct {
+	name string
+
+	lock       sync.Mutex
+	checkpoint uint64
+	checkDelay time.Duration
+	down       bool
+	overloaded bool
+	// executed is the checkpoint of the executed transactions, if any
+	executed string
+	calls    []string
+}
+
+func newPoolNode(t *testing.T, name string, checkpoint uint64) (*poolNode, string) {
+	node := &poolNode{name: name, checkpoint: checkpoint}
+	server := httptest.NewServer(http.HandlerFunc(node.serveHTTP))
+	t.Cleanup(server.Close)
+	return node, server.URL
+}
+
+func (n *poolNode) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var msg jsonrpcMessage
+	_ = json.NewDecoder(r.Body).Decode(&msg)
+	n.lock.Lock()
+	down, overloaded, checkpoint, checkDelay, executed := n.down, n.overloaded, n.checkpoint, n.checkDelay, n.executed
+	if !down && !overloaded && msg.Method != getLatestCheckpointSequenceNumber.String() {
+		n.calls = append(n.calls, msg.Method)
+	}
+	n.lock.Unlock()
+	if down {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	var result interface{} = n.name
+	switch {
+	case msg.Method == getLatestCheckpointSequenceNumber.String():
+		time.Sleep(checkDelay)
+		result = fmt.Sprint(checkpoint)
+	case overloaded:
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	case msg.Method == executeTransactionBlock.String() && executed != "":
+		result = map[string]string{"checkpoint": executed}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": vsn, "id": msg.ID, "result": result})
+}
+
+func (n *poolNode) set(checkpoint uint64, down bool) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.checkpoint = checkpoint
+	n.down = down
+}
+
+func (n *poolNode) setOverloaded(overloaded bool) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.overloaded = overloaded
+}
+
+func (n *poolNode) callCount() int {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return len(n.calls)
+}
+
+func newTestPool(t *testing.T, rpcUrls []string, options ...PoolOption) (*EndpointPool, *Client) {
+	options = append([]PoolOption{WithHealthCheckInterval(0, time.Second)}, options...)
+	pool, err := NewEndpointPool(rpcUrls, options...)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	pool.CheckHealth(context.Background())
+	cli, err := DialPool(pool)
+	require.NoError(t, err)
+	return pool, cli
+}
+
+func callNode(t *testing.T, cli *Client, method Method) string {
+	var name string
+	require.NoError(t, cli.CallContext(context.Background(), &name, method))
+	return name
+}
+
+func TestEndpointPool_RoundRobinAndFailover(t *testing.T) {
+	a, urlA := newPoolNode(t, "a", 100)
+	_, urlB := newPoolNode(t, "b", 98)
+	lagging, urlC := newPoolNode(t, "c", 50)
+	pool, cli := newTestPool(t, []string{urlA, urlB, urlC}, WithMaxCheckpointLag(5))
+
+	var names []string
+	for i := 0; i < 4; i++ {
+		names = append(names, callNode(t, cli, getTotalTransactionBlocks))
+	}
+	require.Equal(t, []string{"a", "b", "a", "b"}, names)
+	require.Equal(t, 0, lagging.callCount())
+
+	a.set(100, true)
+	for i := 0; i < 3; i++ {
+		require.Equal(t, "b", callNode(t, cli, getTotalTransactionBlocks))
+	}
+	statuses := pool.Endpoints()
+	require.False(t, statuses[0].Healthy)
+	require.Error(t, statuses[0].Err)
+
+	a.set(101, false)
+	pool.CheckHealth(context.Background())
+	require.True(t, pool.Endpoints()[0].Healthy)
+	require.Equal(t, uint64(101), pool.Endpoints()[0].Checkpoint)
+}
+
+func TestEndpointPool_LowestLatency(t *testing.T) {
+	slow, urlA := newPoolNode(t, "a", 100)
+	slow.checkDelay = 30 * time.Millisecond
+	_, urlB := newPoolNode(t, "b", 100)
+	_, cli := newTestPool(t, []string{urlA, urlB}, WithRoutingPolicy(RoutingLowestLatency))
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, "b", callNode(t, cli, getTotalTransactionBlocks))
+	}
+	require.Equal(t, 0, slow.callCount())
+}
+
+func TestEndpointPool_ReadAfterWrite(t *testing.T) {
+	fresh, urlA := newPoolNode(t, "a", 120)
+	lagging, urlB := newPoolNode(t, "b", 100)
+	pool, cli := newTestPool(t, []string{urlA, urlB}, WithMaxCheckpointLag(50))
+
+	require.Equal(t, "a", callNode(t, cli, executeTransactionBlock))
+	for i := 0; i < 3; i++ {
+		require.Equal(t, "a", callNode(t, cli, getTransactionBlock))
+	}
+	require.Equal(t, 0, lagging.callCount())
+
+	// the writer failing over lets the reads go to the lagging node rather than fail
+	fresh.set(120, true)
+	require.Equal(t, "b", callNode(t, cli, getTransactionBlock))
+
+	fresh.set(121, false)
+	lagging.set(125, false)
+	pool.CheckHealth(context.Background())
+	names := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		names[callNode(t, cli, getTransactionBlock)] = true
+	}
+	require.Equal(t, map[string]bool{"a": true, "b": true}, names)
+}
+
+func TestEndpointPool_ReadAfterWriteCheckpoint(t *testing.T) {
+	writer, urlA := newPoolNode(t, "a", 100)
+	writer.executed = "105"
+	other, urlB := newPoolNode(t, "b", 100)
+	pool, cli := newTestPool(t, []string{urlA, urlB})
+
+	var resp map[string]string
+	require.NoError(t, cli.CallContext(context.Background(), &resp, executeTransactionBlock))
+	require.Equal(t, "105", resp["checkpoint"])
+
+	// the other endpoint had the checkpoint of the writer, the transaction is in a later one
+	other.set(104, false)
+	pool.CheckHealth(context.Background())
+	for i := 0; i < 3; i++ {
+		require.Equal(t, "a", callNode(t, cli, getTransactionBlock))
+	}
+	require.Equal(t, 0, other.callCount())
+
+	other.set(105, false)
+	pool.CheckHealth(context.Background())
+	names := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		names[callNode(t, cli, getTransactionBlock)] = true
+	}
+	require.Equal(t, map[string]bool{"a": true, "b": true}, names)
+}
+
+func TestEndpointPool_Overloaded(t *testing.T) {
+	overloaded, urlA := newPoolNode(t, "a", 100)
+	_, urlB := newPoolNode(t, "b", 100)
+	pool, cli := newTestPool(t, []string{urlA, urlB})
+
+	overloaded.setOverloaded(true)
+	for i := 0; i < 3; i++ {
+		require.Equal(t, "b", callNode(t, cli, getTotalTransactionBlocks))
+	}
+	status := pool.Endpoints()[0]
+	require.True(t, status.Healthy)
+	require.NoError(t, status.Err)
+
+	// the endpoint is routed to again once the backoff is over
+	overloaded.setOverloaded(false)
+	time.Sleep(overloadBackoff)
+	names := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		names[callNode(t, cli, getTotalTransactionBlocks)] = true
+	}
+	require.Equal(t, map[string]bool{"a": true, "b": true}, names)
+}
+
+func TestEndpointPool_NoEndpoints(t *testing.T) {
+	_, err := NewEndpointPool(nil)
+	require.ErrorIs(t, err, ErrNoEndpoints)
+}
@@ -1,10 +1,16 @@
 package client
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
 
 type HTTPError struct {
 	StatusCode int
 	Status     string
+	Header     http.Header
 	Body       []byte
 }
 
@@ -14,3 +20,9 @@ func (err HTTPError) Error() string {
 	}
 	return fmt.Sprintf("%v: %s", err.Status, err.Body)
 }
+
+// Is matches types.ErrNodeOverloaded for the 429 and 503 statuses.
+func (err HTTPError) Is(target error) bool {
+	return target == types.ErrNodeOverloaded &&
+		(err.StatusCode == http.StatusTooManyRequests || err.StatusCode == http.StatusServiceUnavailable)
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+func TestRPCError(t *testing.T) {
+	standIn := newRpcStandIn(
+		t, map[string]rpcHandler{
+			dryRunTransactionBlock.String(): func(params []json.RawMessage) (interface{}, error) {
+				return nil, &RPCError{
+					Code: RPCErrorInvalidParams,
+					Message: `Error checking transaction input objects: MoveAbort(MoveLocation { module: ModuleId { address: 0x2, ` +
+						`name: Identifier("coin") }, function: 7, instruction: 9, function_name: Some("split") }, 0)`,
+				}
+			},
+			getTransactionBlock.String(): func(params []json.RawMessage) (interface{}, error) {
+				return nil, &RPCError{Code: RPCErrorTransient, Message: "try again later"}
+			},
+		},
+	)
+	cli := standIn.client(t)
+	ctx := context.Background()
+
+	_, err := cli.DryRunTransaction(ctx, suiBase64Data{1})
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, RPCErrorInvalidParams, rpcErr.ErrorCode())
+	var abort *types.MoveAbort
+	require.ErrorAs(t, err, &abort)
+	require.Equal(t, "split", abort.Function)
+	require.False(t, errors.Is(err, types.ErrNodeOverloaded))
+
+	_, err = cli.GetTransactionBlock(ctx, nil, types.SuiTransactionBlockResponseOptions{})
+	require.ErrorIs(t, err, types.ErrNodeOverloaded)
+	require.False(t, errors.As(err, &abort))
+
+	require.ErrorIs(t, HTTPError{StatusCode: http.StatusTooManyRequests}, types.ErrNodeOverloaded)
+	require.NotErrorIs(t, HTTPError{StatusCode: http.StatusBadGateway}, types.ErrNodeOverloaded)
+}
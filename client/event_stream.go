@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+type eventStreamConfig struct {
+	pageSize      uint
+	pollInterval  time.Duration
+	minRetryDelay time.Duration
+	maxRetryDelay time.Duration
+	onError       func(error)
+}
+
+type EventStreamOption func(*eventStreamConfig)
+
+// WithEventPageSize sets the limit of every QueryEvents call.
+func WithEventPageSize(size uint) EventStreamOption {
+	return func(c *eventStreamConfig) {
+		c.pageSize = size
+	}
+}
+
+// WithPollInterval sets how long the stream waits for new events once it reached the latest one.
+func WithPollInterval(interval time.Duration) EventStreamOption {
+	return func(c *eventStreamConfig) {
+		c.pollInterval = interval
+	}
+}
+
+// WithEventRetryBackoff sets the exponential delay between retries of a failed QueryEvents call.
+func WithEventRetryBackoff(minDelay, maxDelay time.Duration) EventStreamOption {
+	return func(c *eventStreamConfig) {
+		c.minRetryDelay = minDelay
+		c.maxRetryDelay = maxDelay
+	}
+}
+
+// WithEventErrorHandler is called with every QueryEvents error before it is retried.
+func WithEventErrorHandler(onError func(error)) EventStreamOption {
+	return func(c *eventStreamConfig) {
+		c.onError = onError
+	}
+}
+
+// EventStream pages through the events matching a filter in ascending order and keeps polling
+// for new ones. The id of every delivered event is saved to the cursor store, and a stream
+// built on the same store resumes right after it.
+type EventStream struct {
+	client *Client
+	filter types.EventFilter
+	store  EventCursorStore
+	config eventStreamConfig
+}
+
+func NewEventStream(
+	client *Client,
+	filter types.EventFilter,
+	store EventCursorStore,
+	options ...EventStreamOption,
+) *EventStream {
+	config := eventStreamConfig{
+		pageSize:      50,
+		pollInterval:  2 * time.Second,
+		minRetryDelay: 200 * time.Millisecond,
+		maxRetryDelay: 30 * time.Second,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+	if store == nil {
+		store = NewMemoryCursorStore()
+	}
+	return &EventStream{
+		client: client,
+		filter: filter,
+		store:  store,
+		config: config,
+	}
+}
+
+// Run calls handler for every event in order until ctx is done or handler fails.
+// The cursor is saved only after handler returns nil, so a failed event is delivered
+// again on the next run.
+func (s *EventStream) Run(ctx context.Context, handler func(context.Context, types.SuiEvent) error) error {
+	cursor, err := s.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		page, err := s.nextPage(ctx, cursor)
+		if err != nil {
+			return err
+		}
+		for _, event := range page.Data {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := handler(ctx, event); err != nil {
+				return err
+			}
+			if err := s.store.Save(ctx, event.Id); err != nil {
+				return err
+			}
+			id := event.Id
+			cursor = &id
+		}
+		if page.HasNextPage && len(page.Data) > 0 {
+			continue
+		}
+		if err := sleepContext(ctx, s.config.pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// Events runs the stream in the background and delivers the events on a channel. An event
+// counts as delivered once it is received from the channel. The error channel receives the
+// reason the stream stopped, both channels are closed afterwards.
+func (s *EventStream) Events(ctx context.Context) (<-chan types.SuiEvent, <-chan error) {
+	events := make(chan types.SuiEvent)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(events)
+		defer close(errs)
+		err := s.Run(
+			ctx, func(ctx context.Context, event types.SuiEvent) error {
+				select {
+				case events <- event:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			},
+		)
+		errs <- err
+	}()
+	return events, errs
+}
+
+func (s *EventStream) nextPage(ctx context.Context, cursor *types.EventId) (*types.EventPage, error) {
+	limit := s.config.pageSize
+	delay := s.config.minRetryDelay
+	for {
+		page, err := s.client.QueryEvents(ctx, s.filter, cursor, &limit, false)
+		if err == nil {
+			return page, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) && rpcErr.Code == RPCErrorInvalidParams {
+			return nil, err // invalid params never succeed
+		}
+		if s.config.onError != nil {
+			s.config.onError(err)
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+		delay *= 2
+		if delay > s.config.maxRetryDelay {
+			delay = s.config.maxRetryDelay
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
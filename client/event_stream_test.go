@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+type eventLog struct {
+	lock   sync.Mutex
+	events []types.SuiEvent
+	fails  int
+}
+
+func (l *eventLog) append(count int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	digest, _ := suiDigestFromString("HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn")
+	for i := 0; i < count; i++ {
+		seq := uint64(len(l.events))
+		l.events = append(
+			l.events, types.SuiEvent{
+				Id:   types.EventId{TxDigest: *digest, EventSeq: types.NewSafeSuiBigInt(seq)},
+				Type: "0x2::m::E",
+			},
+		)
+	}
+}
+
+func (l *eventLog) queryEvents(params []json.RawMessage) (interface{}, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.fails > 0 {
+		l.fails--
+		return nil, errors.New("node overloaded")
+	}
+	var cursor *types.EventId
+	var limit uint
+	_ = json.Unmarshal(params[1], &cursor)
+	_ = json.Unmarshal(params[2], &limit)
+	start := 0
+	if cursor != nil {
+		start = int(cursor.EventSeq.Uint64()) + 1
+	}
+	end := start + int(limit)
+	if end > len(l.events) {
+		end = len(l.events)
+	}
+	page := types.EventPage{Data: append([]types.SuiEvent{}, l.events[start:end]...), HasNextPage: end < len(l.events)}
+	if end > start {
+		page.NextCursor = &page.Data[len(page.Data)-1].Id
+	}
+	return page, nil
+}
+
+func suiDigestFromString(str string) (*suiDigest, error) {
+	var digest suiDigest
+	err := json.Unmarshal([]byte(`"`+str+`"`), &digest)
+	return &digest, err
+}
+
+func newEventStandIn(t *testing.T, log *eventLog) *Client {
+	standIn := newRpcStandIn(t, map[string]rpcHandler{queryEvents.String(): log.queryEvents})
+	return standIn.client(t)
+}
+
+func collectEvents(
+	t *testing.T,
+	stream *EventStream,
+	count int,
+) []uint64 {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var seqs []uint64
+	err := stream.Run(
+		ctx, func(ctx context.Context, event types.SuiEvent) error {
+			seqs = append(seqs, event.Id.EventSeq.Uint64())
+			if len(seqs) == count {
+				cancel()
+			}
+			return nil
+		},
+	)
+	require.ErrorIs(t, err, context.Canceled)
+	return seqs
+}
+
+func TestEventStream_ResumeFromCursor(t *testing.T) {
+	log := &eventLog{}
+	log.append(7)
+	cli := newEventStandIn(t, log)
+	store := NewFileCursorStore(filepath.Join(t.TempDir(), "cursor.json"))
+
+	stream := NewEventStream(cli, types.EventFilter{}, store, WithEventPageSize(3), WithPollInterval(10*time.Millisecond))
+	require.Equal(t, []uint64{0, 1, 2, 3, 4}, collectEvents(t, stream, 5))
+
+	saved, err := store.Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), saved.EventSeq.Uint64())
+
+	// a restarted stream continues after the saved cursor and then follows new events
+	log.append(3)
+	restarted := NewEventStream(cli, types.EventFilter{}, store, WithEventPageSize(3), WithPollInterval(10*time.Millisecond))
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		log.append(2)
+	}()
+	require.Equal(t, []uint64{5, 6, 7, 8, 9, 10, 11}, collectEvents(t, restarted, 7))
+}
+
+func TestEventStream_HandlerFailure(t *testing.T) {
+	log := &eventLog{fails: 2}
+	log.append(4)
+	cli := newEventStandIn(t, log)
+	store := NewMemoryCursorStore()
+	var retried []error
+
+	stream := NewEventStream(
+		cli, types.EventFilter{}, store,
+		WithEventRetryBackoff(time.Millisecond, 5*time.Millisecond),
+		WithEventErrorHandler(func(err error) { retried = append(retried, err) }),
+	)
+	failure := errors.New("handler failed")
+	err := stream.Run(
+		context.Background(), func(ctx context.Context, event types.SuiEvent) error {
+			if event.Id.EventSeq.Uint64() == 2 {
+				return failure
+			}
+			return nil
+		},
+	)
+	require.ErrorIs(t, err, failure)
+	require.Len(t, retried, 2)
+
+	saved, err := store.Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), saved.EventSeq.Uint64())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errs := stream.Events(ctx)
+	require.Equal(t, uint64(2), (<-events).Id.EventSeq.Uint64())
+	require.Equal(t, uint64(3), (<-events).Id.EventSeq.Uint64())
+	cancel()
+	require.ErrorIs(t, <-errs, context.Canceled)
+	saved, err = store.Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), saved.EventSeq.Uint64())
+}
@@ -0,0 +1,582 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+var ErrExecutorClosed = errors.New("executor is closed")
+
+// ExecutorJob is a transaction executed by an Executor.
+type ExecutorJob struct {
+	// Objects are the owned objects the transaction uses besides its gas coin, the transactions
+	// sharing one of them are executed one after another.
+	Objects []suiObjectID
+	// Build returns the transaction bytes paying the gas with the leased coins, its arguments are
+	// those of the BCS_ functions. The latest versions of Objects are given by Executor.ObjectRef.
+	Build   func(gas []*sui_types.ObjectRef, gasBudget, gasPrice uint64) ([]byte, error)
+	Options *types.SuiTransactionBlockResponseOptions
+}
+
+// ExecutorResult is the outcome of a job submitted to an Executor.
+type ExecutorResult struct {
+	done chan struct{}
+	resp *types.SuiTransactionBlockResponse
+	err  error
+}
+
+// Done is closed once the job is executed.
+func (r *ExecutorResult) Done() <-chan struct{} {
+	return r.done
+}
+
+// Wait returns the response of the job like SignAndExecuteTransaction does, or the error of the
+// context if it is done first.
+func (r *ExecutorResult) Wait(ctx context.Context) (*types.SuiTransactionBlockResponse, error) {
+	select {
+	case <-r.done:
+		return r.resp, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type executorTask struct {
+	ctx    context.Context
+	job    ExecutorJob
+	result *ExecutorResult
+}
+
+type executorConfig struct {
+	concurrency     int
+	queueSize       int
+	gasBudget       uint64
+	gasCoins        []*sui_types.ObjectRef
+	options         []ExecuteOption
+	quarantineGrace time.Duration
+	quarantinePoll  time.Duration
+}
+
+type ExecutorOption func(*executorConfig)
+
+// WithExecutorConcurrency sets how many transactions are executed at once, 4 by default. It is
+// also bounded by the number of gas coins.
+func WithExecutorConcurrency(concurrency int) ExecutorOption {
+	return func(c *executorConfig) {
+		c.concurrency = concurrency
+	}
+}
+
+// WithExecutorQueueSize sets how many submitted jobs wait for a worker before Submit blocks.
+func WithExecutorQueueSize(size int) ExecutorOption {
+	return func(c *executorConfig) {
+		c.queueSize = size
+	}
+}
+
+// WithExecutorGasBudget sets the gas budget of every transaction, DefaultCoinGasBudget by default.
+// The coins of the pool must hold at least as much.
+func WithExecutorGasBudget(gasBudget uint64) ExecutorOption {
+	return func(c *executorConfig) {
+		c.gasBudget = gasBudget
+	}
+}
+
+// WithExecutorGasCoins sets the coins of the gas pool, by default every SUI coin of the owner which
+// can pay the gas budget. The jobs must not use them as inputs.
+func WithExecutorGasCoins(gasCoins ...*sui_types.ObjectRef) ExecutorOption {
+	return func(c *executorConfig) {
+		c.gasCoins = gasCoins
+	}
+}
+
+// WithExecutorQuarantine sets how long the gas coin of a submission without effects, whose
+// transaction may still be executed, is held back: the transaction is looked up every pollInterval,
+// 1s by default, until it is found or the node still does not know it after gracePeriod, 30s by
+// default.
+func WithExecutorQuarantine(gracePeriod, pollInterval time.Duration) ExecutorOption {
+	return func(c *executorConfig) {
+		c.quarantineGrace = gracePeriod
+		c.quarantinePoll = pollInterval
+	}
+}
+
+// WithExecutorExecuteOptions sets the options of SignAndExecuteTransaction.
+func WithExecutorExecuteOptions(options ...ExecuteOption) ExecutorOption {
+	return func(c *executorConfig) {
+		c.options = options
+	}
+}
+
+// Executor executes the transactions of a signer in parallel without equivocating: each
+// transaction leases its own coin of a gas pool, whose reference is updated from the effects, and
+// transactions using the same owned objects are executed one after another.
+//
+// A gas coin left without effects by a submission which timed out or was cancelled is quarantined,
+// with the owned objects of the job, until the transaction is found or the node still does not know
+// it after a grace period, see WithExecutorQuarantine. The coin of a transaction the node rejected
+// is reloaded at once. A coin which cannot pay the gas budget anymore is dropped from the pool.
+// CoinManager.SplitGasCoins can fill the pool.
+//
+//	executor, err := client.NewExecutor(ctx, cli, signer, owner, client.WithExecutorConcurrency(8))
+//	defer executor.Close()
+//	result, err := executor.Submit(ctx, client.ExecutorJob{Build: build})
+//	resp, err := result.Wait(ctx)
+type Executor struct {
+	client   *Client
+	signer   TransactionSigner
+	owner    suiAddress
+	config   executorConfig
+	gasPrice uint64
+
+	gasCoins chan *gasCoin
+	poolLock sync.Mutex
+	poolSize int
+	drained  chan struct{}
+
+	locksLock sync.Mutex
+	locks     map[suiObjectID]*objectLock
+
+	refsLock sync.RWMutex
+	refs     map[suiObjectID]sui_types.ObjectRef
+
+	closeLock sync.RWMutex
+	closed    bool
+	tasks     chan *executorTask
+	workers   sync.WaitGroup
+
+	// ctx stops the quarantines once the executor is closed
+	ctx         context.Context
+	cancel      context.CancelFunc
+	quarantined sync.WaitGroup
+}
+
+// gasCoin is a coin of the gas pool with its balance, kept up to date from the effects.
+type gasCoin struct {
+	ref     sui_types.ObjectRef
+	balance uint64
+}
+
+type objectLock struct {
+	sync.Mutex
+	users int
+}
+
+// NewExecutor loads the gas price and the gas pool, and starts the workers.
+func NewExecutor(
+	ctx context.Context,
+	client *Client,
+	signer TransactionSigner,
+	owner suiAddress,
+	options ...ExecutorOption,
+) (*Executor, error) {
+	config := executorConfig{
+		concurrency:     4,
+		gasBudget:       DefaultCoinGasBudget,
+		quarantineGrace: 30 * time.Second,
+		quarantinePoll:  time.Second,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+	if config.concurrency <= 0 {
+		config.concurrency = 1
+	}
+	gasPrice, err := client.GetReferenceGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var gasCoins []*gasCoin
+	if config.gasCoins == nil {
+		coins, err := client.GetCoinsIterator(owner, nil).Collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, coin := range coins {
+			gasCoins = append(gasCoins, &gasCoin{ref: *coin.Reference(), balance: coin.Balance.Uint64()})
+		}
+	} else if gasCoins, err = loadGasCoins(ctx, client, config.gasCoins); err != nil {
+		return nil, err
+	}
+	pool := make([]*gasCoin, 0, len(gasCoins))
+	for _, gas := range gasCoins {
+		if gas.balance >= config.gasBudget {
+			pool = append(pool, gas)
+		}
+	}
+	if len(pool) == 0 {
+		return nil, ErrNoGasCoin
+	}
+
+	e := &Executor{
+		client:   client,
+		signer:   signer,
+		owner:    owner,
+		config:   config,
+		gasPrice: gasPrice.Uint64(),
+		gasCoins: make(chan *gasCoin, len(pool)),
+		poolSize: len(pool),
+		drained:  make(chan struct{}),
+		locks:    make(map[suiObjectID]*objectLock),
+		refs:     make(map[suiObjectID]sui_types.ObjectRef),
+		tasks:    make(chan *executorTask, config.queueSize),
+	}
+	e.ctx, e.cancel = context.WithCancel(context.Background())
+	for _, gas := range pool {
+		e.gasCoins <- gas
+	}
+	e.workers.Add(config.concurrency)
+	for i := 0; i < config.concurrency; i++ {
+		go e.work()
+	}
+	return e, nil
+}
+
+// Submit queues job, it blocks while the queue is full unless ctx is done first. The context also
+// bounds the execution of the job.
+func (e *Executor) Submit(ctx context.Context, job ExecutorJob) (*ExecutorResult, error) {
+	e.closeLock.RLock()
+	defer e.closeLock.RUnlock()
+	if e.closed {
+		return nil, ErrExecutorClosed
+	}
+	task := &executorTask{ctx: ctx, job: job, result: &ExecutorResult{done: make(chan struct{})}}
+	select {
+	case e.tasks <- task:
+		return task.result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Execute submits job and waits for its response.
+func (e *Executor) Execute(ctx context.Context, job ExecutorJob) (*types.SuiTransactionBlockResponse, error) {
+	result, err := e.Submit(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	return result.Wait(ctx)
+}
+
+// Close stops accepting jobs and waits for the queued ones to be executed. The coins still in
+// quarantine are left out of the pool.
+func (e *Executor) Close() {
+	e.closeLock.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.tasks)
+	}
+	e.closeLock.Unlock()
+	e.workers.Wait()
+	e.cancel()
+	e.quarantined.Wait()
+}
+
+// GasCoins returns how many coins are left in the gas pool, leased and quarantined ones included.
+func (e *Executor) GasCoins() int {
+	e.poolLock.Lock()
+	defer e.poolLock.Unlock()
+	return e.poolSize
+}
+
+// ObjectRef returns the latest reference of an object created or mutated by the executed
+// transactions.
+func (e *Executor) ObjectRef(id suiObjectID) (sui_types.ObjectRef, bool) {
+	e.refsLock.RLock()
+	defer e.refsLock.RUnlock()
+	ref, ok := e.refs[id]
+	return ref, ok
+}
+
+func (e *Executor) work() {
+	defer e.workers.Done()
+	for task := range e.tasks {
+		task.result.resp, task.result.err = e.run(task.ctx, task.job)
+		close(task.result.done)
+	}
+}
+
+func (e *Executor) run(ctx context.Context, job ExecutorJob) (*types.SuiTransactionBlockResponse, error) {
+	unlock := e.lockObjects(job.Objects)
+	gas, err := e.leaseGas(ctx)
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+
+	ref := gas.ref
+	txBytes, err := job.Build([]*sui_types.ObjectRef{&ref}, e.config.gasBudget, e.gasPrice)
+	if err != nil {
+		unlock()
+		e.returnGas(gas)
+		return nil, err
+	}
+	resp, err := e.client.SignAndExecuteTransaction(ctx, e.signer, txBytes, job.Options, e.config.options...)
+	switch {
+	case resp != nil && resp.Effects.Data.V1 != nil:
+		e.updateRefs(resp.Effects.Data.V1)
+		unlock()
+		e.settleGas(gas, txBytes, resp.Effects.Data.V1)
+	case errors.Is(err, types.ErrInsufficientGas):
+		unlock()
+		e.dropGas()
+	case isRejectedSubmission(err):
+		unlock()
+		e.reloadGas(gas)
+	default:
+		e.quarantineGas(gas, txBytes, unlock)
+	}
+	return resp, err
+}
+
+// isRejectedSubmission reports whether the node answered a submission with an error, so its
+// transaction is not executed. Timeouts, cancellations and transport errors leave it unknown.
+func isRejectedSubmission(err error) bool {
+	var rpcErr *RPCError
+	return errors.As(err, &rpcErr) && !errors.Is(rpcErr, types.ErrNodeOverloaded)
+}
+
+// lockObjects locks the objects in a fixed order, so two jobs cannot wait for each other.
+func (e *Executor) lockObjects(ids []suiObjectID) (unlock func()) {
+	ids = append([]suiObjectID(nil), ids...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	var locks []*objectLock
+	e.locksLock.Lock()
+	for i, id := range ids {
+		if i > 0 && id == ids[i-1] {
+			continue
+		}
+		lock, ok := e.locks[id]
+		if !ok {
+			lock = &objectLock{}
+			e.locks[id] = lock
+		}
+		lock.users++
+		locks = append(locks, lock)
+	}
+	e.locksLock.Unlock()
+
+	for _, lock := range locks {
+		lock.Lock()
+	}
+	return func() {
+		for _, lock := range locks {
+			lock.Unlock()
+		}
+		e.locksLock.Lock()
+		defer e.locksLock.Unlock()
+		for i, id := range ids {
+			if i > 0 && id == ids[i-1] {
+				continue
+			}
+			if lock := e.locks[id]; lock != nil {
+				if lock.users--; lock.users == 0 {
+					delete(e.locks, id)
+				}
+			}
+		}
+	}
+}
+
+func (e *Executor) leaseGas(ctx context.Context) (*gasCoin, error) {
+	select {
+	case gas := <-e.gasCoins:
+		return gas, nil
+	case <-e.drained:
+		return nil, ErrNoGasCoin
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// returnGas returns a coin to the pool, or drops it if it cannot pay the gas budget anymore.
+func (e *Executor) returnGas(gas *gasCoin) {
+	if gas.balance < e.config.gasBudget {
+		e.dropGas()
+		return
+	}
+	e.gasCoins <- gas
+}
+
+func (e *Executor) dropGas() {
+	e.poolLock.Lock()
+	defer e.poolLock.Unlock()
+	if e.poolSize--; e.poolSize == 0 {
+		close(e.drained)
+	}
+}
+
+// settleGas returns the gas coin of an executed transaction to the pool with its reference and
+// balance after the transaction. The balance is that before less the gas fee, unless a command of
+// the transaction takes the gas coin, then it is reloaded from the node.
+func (e *Executor) settleGas(gas *gasCoin, txBytes []byte, effects *types.SuiTransactionBlockEffectsV1) {
+	if usesGasCoin(txBytes) {
+		e.reloadGas(gas)
+		return
+	}
+	gas.ref.Version = effects.GasObject.Reference.Version
+	gas.ref.Digest = effects.GasObject.Reference.Digest
+	charged := effects.GasUsed.ComputationCost.Uint64() + effects.GasUsed.StorageCost.Uint64()
+	rebate := effects.GasUsed.StorageRebate.Uint64()
+	switch {
+	case rebate >= charged:
+		gas.balance += rebate - charged
+	case gas.balance > charged-rebate:
+		gas.balance -= charged - rebate
+	default:
+		gas.balance = 0
+	}
+	e.returnGas(gas)
+}
+
+// reloadGas returns the gas coin to the pool with its reference and balance on the node. The
+// coin is returned as it is if the node cannot be reached.
+func (e *Executor) reloadGas(gas *gasCoin) {
+	// the context of the job may be done, reloading is needed all the same
+	object, err := e.client.GetObject(context.Background(), gas.ref.ObjectId, &types.SuiObjectDataOptions{ShowBcs: true})
+	if err != nil {
+		e.returnGas(gas)
+		return
+	}
+	if object.Data == nil {
+		e.dropGas()
+		return
+	}
+	if balance, err := coinBalance(object); err == nil {
+		gas.balance = balance
+	}
+	gas.ref = object.Data.Reference()
+	e.returnGas(gas)
+}
+
+// quarantineGas holds the gas coin and the owned objects of a submission without effects until
+// its transaction is found, then the coin is settled from the effects, or the node still does not
+// know it after the grace period, then the coin is reloaded.
+func (e *Executor) quarantineGas(gas *gasCoin, txBytes []byte, unlock func()) {
+	e.quarantined.Add(1)
+	go func() {
+		defer e.quarantined.Done()
+		defer unlock()
+		digest := sui_types.NewTransactionDigestFromBytes(txBytes)
+		deadline := time.Now().Add(e.config.quarantineGrace)
+		ticker := time.NewTicker(e.config.quarantinePoll)
+		defer ticker.Stop()
+		for {
+			resp, err := e.client.GetTransactionBlock(
+				e.ctx, digest, types.SuiTransactionBlockResponseOptions{ShowEffects: true},
+			)
+			switch {
+			case err == nil && resp.Effects.Data.V1 != nil:
+				e.updateRefs(resp.Effects.Data.V1)
+				e.settleGas(gas, txBytes, resp.Effects.Data.V1)
+				return
+			case errors.Is(err, types.ErrTransactionNotFound) && !time.Now().Before(deadline):
+				e.reloadGas(gas)
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-e.ctx.Done():
+				// the executor is closed, the coin is left out of the pool
+				return
+			}
+		}
+	}()
+}
+
+// loadGasCoins loads the balances of the coins given by WithExecutorGasCoins.
+func loadGasCoins(ctx context.Context, client *Client, refs []*sui_types.ObjectRef) ([]*gasCoin, error) {
+	ids := make([]suiObjectID, len(refs))
+	for i, ref := range refs {
+		ids[i] = ref.ObjectId
+	}
+	objects, err := client.MultiGetObjects(ctx, ids, &types.SuiObjectDataOptions{ShowBcs: true})
+	if err != nil {
+		return nil, err
+	}
+	gasCoins := make([]*gasCoin, 0, len(objects))
+	for i := range objects {
+		if objects[i].Data == nil {
+			continue
+		}
+		balance, err := coinBalance(&objects[i])
+		if err != nil {
+			return nil, err
+		}
+		gasCoins = append(gasCoins, &gasCoin{ref: objects[i].Data.Reference(), balance: balance})
+	}
+	return gasCoins, nil
+}
+
+func coinBalance(object *types.SuiObjectResponse) (uint64, error) {
+	coin, err := types.DecodeMoveObjectAs[sui_types.Coin](object)
+	if err != nil {
+		return 0, err
+	}
+	return coin.Balance.Value, nil
+}
+
+// usesGasCoin reports whether a command of the transaction takes the gas coin, which may change
+// its balance by more than the gas fee. Transactions which cannot be decoded are assumed to.
+func usesGasCoin(txBytes []byte) bool {
+	var tx sui_types.TransactionData
+	if err := lib.UnmarshalBCS(txBytes, &tx); err != nil {
+		return true
+	}
+	if tx.V1 == nil || tx.V1.Kind.ProgrammableTransaction == nil {
+		return true
+	}
+	for _, command := range tx.V1.Kind.ProgrammableTransaction.Commands {
+		var args []sui_types.Argument
+		switch {
+		case command.MoveCall != nil:
+			args = command.MoveCall.Arguments
+		case command.TransferObjects != nil:
+			args = command.TransferObjects.Arguments
+		case command.SplitCoins != nil:
+			args = append([]sui_types.Argument{command.SplitCoins.Argument}, command.SplitCoins.Arguments...)
+		case command.MergeCoins != nil:
+			args = append([]sui_types.Argument{command.MergeCoins.Argument}, command.MergeCoins.Arguments...)
+		case command.MakeMoveVec != nil:
+			args = command.MakeMoveVec.Arguments
+		case command.Upgrade != nil:
+			args = []sui_types.Argument{command.Upgrade.Argument}
+		}
+		for _, arg := range args {
+			if arg.GasCoin != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (e *Executor) updateRefs(effects *types.SuiTransactionBlockEffectsV1) {
+	e.refsLock.Lock()
+	defer e.refsLock.Unlock()
+	for _, changed := range [][]types.OwnedObjectRef{effects.Created, effects.Mutated, effects.Unwrapped} {
+		for _, owned := range changed {
+			if id, err := sui_types.NewObjectIdFromHex(owned.Reference.ObjectId); err == nil {
+				e.refs[*id] = sui_types.ObjectRef{
+					ObjectId: *id,
+					Version:  owned.Reference.Version,
+					Digest:   owned.Reference.Digest,
+				}
+			}
+		}
+	}
+	for _, removed := range [][]types.SuiObjectRef{effects.Deleted, effects.Wrapped, effects.UnwrappedThenDeleted} {
+		for _, ref := range removed {
+			if id, err := sui_types.NewObjectIdFromHex(ref.ObjectId); err == nil {
+				delete(e.refs, *id)
+			}
+		}
+	}
+}
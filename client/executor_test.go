@@ -0,0 +1,241 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/account"
+	"github.com/thorli9527/sui-wallet-sdk/mocknode"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+func testAccount(t *testing.T, seed byte) (*account.Account, sui_types.SuiAddress) {
+	scheme, err := sui_types.NewSignatureScheme(0)
+	require.NoError(t, err)
+	signer := account.NewAccount(scheme, append(make([]byte, 31), seed))
+	address, err := sui_types.NewAddressFromHex(signer.Address)
+	require.NoError(t, err)
+	return signer, *address
+}
+
+func newTestExecutor(t *testing.T) (*mocknode.Node, *Client, *account.Account, sui_types.SuiAddress) {
+	node := mocknode.New(mocknode.WithGasCost(1000, 1000))
+	t.Cleanup(node.Close)
+	cli, err := Dial(node.URL())
+	require.NoError(t, err)
+	signer, owner := testAccount(t, 8)
+	return node, cli, signer, owner
+}
+
+// splitTransfer sends amount split from coin, or from the gas coin when coin is nil, to recipient.
+func splitTransfer(
+	sender, recipient sui_types.SuiAddress,
+	coin *sui_types.ObjectRef,
+	amount uint64,
+) func(gas []*sui_types.ObjectRef, gasBudget, gasPrice uint64) ([]byte, error) {
+	return func(gas []*sui_types.ObjectRef, gasBudget, gasPrice uint64) ([]byte, error) {
+		ptb := sui_types.NewProgrammableTransactionBuilder()
+		if coin == nil {
+			if err := ptb.TransferSui(recipient, &amount); err != nil {
+				return nil, err
+			}
+		} else if err := ptb.Pay([]*sui_types.ObjectRef{coin}, []sui_types.SuiAddress{recipient}, []uint64{amount}); err != nil {
+			return nil, err
+		}
+		return bcs.Marshal(sui_types.NewProgrammable(sender, gas, ptb.Finish(), gasBudget, gasPrice))
+	}
+}
+
+func TestExecutor(t *testing.T) {
+	node, cli, signer, owner := newTestExecutor(t)
+	ctx := context.Background()
+	_, recipient := testAccount(t, 9)
+	for i := 0; i < 4; i++ {
+		node.Mint(owner, "", 1_000_000_000)
+	}
+	// too small to pay the gas budget, it is not in the pool
+	node.Mint(owner, "", 1000)
+	usdc := node.Mint(owner, testUsdcType, 1_000)
+
+	executor, err := NewExecutor(
+		ctx, cli, signer, owner, WithExecutorConcurrency(8), WithExecutorGasBudget(10_000_000),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 4, executor.GasCoins())
+
+	var results []*ExecutorResult
+	for i := 0; i < 20; i++ {
+		job := ExecutorJob{Build: splitTransfer(owner, recipient, nil, 10)}
+		if i%2 == 0 {
+			// the usdc coin is used by every other job, which cannot run in parallel
+			build := func(gas []*sui_types.ObjectRef, gasBudget, gasPrice uint64) ([]byte, error) {
+				coin := usdc
+				if ref, ok := executor.ObjectRef(usdc.ObjectId); ok {
+					coin = ref
+				}
+				return splitTransfer(owner, recipient, &coin, 1)(gas, gasBudget, gasPrice)
+			}
+			job = ExecutorJob{Objects: []sui_types.ObjectID{usdc.ObjectId}, Build: build}
+		}
+		result, err := executor.Submit(ctx, job)
+		require.NoError(t, err)
+		results = append(results, result)
+	}
+	for _, result := range results {
+		resp, err := result.Wait(ctx)
+		require.NoError(t, err)
+		require.True(t, resp.Effects.Data.IsSuccess())
+	}
+	executor.Close()
+	_, err = executor.Submit(ctx, ExecutorJob{})
+	require.ErrorIs(t, err, ErrExecutorClosed)
+
+	balance, err := cli.GetBalance(ctx, recipient, "")
+	require.NoError(t, err)
+	require.Equal(t, "100", balance.TotalBalance.String())
+	balance, err = cli.GetBalance(ctx, recipient, testUsdcType)
+	require.NoError(t, err)
+	require.Equal(t, "10", balance.TotalBalance.String())
+	ref, ok := executor.ObjectRef(usdc.ObjectId)
+	require.True(t, ok)
+	require.Greater(t, ref.Version, usdc.Version)
+}
+
+func TestExecutor_DropsGasCoins(t *testing.T) {
+	node, cli, signer, owner := newTestExecutor(t)
+	ctx := context.Background()
+	_, recipient := testAccount(t, 9)
+	gas := node.Mint(owner, "", 15_000_000)
+
+	executor, err := NewExecutor(
+		ctx, cli, signer, owner, WithExecutorGasCoins(&gas), WithExecutorGasBudget(10_000_000),
+	)
+	require.NoError(t, err)
+	defer executor.Close()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = executor.Execute(ctx, ExecutorJob{Build: splitTransfer(owner, recipient, nil, 5_000_000)})
+		}(i)
+	}
+	wg.Wait()
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else {
+			// the coin left with less than the gas budget is dropped before another job leases it
+			require.ErrorIs(t, err, ErrNoGasCoin)
+		}
+	}
+	require.Equal(t, 1, succeeded)
+	require.Equal(t, 0, executor.GasCoins())
+
+	t.Run("gas fee", func(t *testing.T) {
+		gas := node.Mint(owner, "", 10_000_001)
+		usdc := node.Mint(owner, testUsdcType, 10)
+		executor, err := NewExecutor(
+			ctx, cli, signer, owner, WithExecutorGasCoins(&gas), WithExecutorGasBudget(10_000_000),
+		)
+		require.NoError(t, err)
+		defer executor.Close()
+
+		// the gas coin only pays the gas fee, its balance is known without reloading it
+		_, err = executor.Execute(ctx, ExecutorJob{Build: splitTransfer(owner, recipient, &usdc, 1)})
+		require.NoError(t, err)
+		require.Equal(t, 0, executor.GasCoins())
+	})
+}
+
+// delayedNode forwards the requests to node, but the first executeTransactionBlock reaches it
+// only after delay, or never when delay is negative, and its response never comes back.
+func delayedNode(t *testing.T, node *mocknode.Node, delay time.Duration) string {
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		held := false
+		if bytes.Contains(body, []byte(executeTransactionBlock.String())) {
+			once.Do(func() { held = true })
+		}
+		if held {
+			if delay >= 0 {
+				time.AfterFunc(delay, func() {
+					resp, err := http.Post(node.URL(), "application/json", bytes.NewReader(body))
+					if err == nil {
+						resp.Body.Close()
+					}
+				})
+			}
+			<-r.Context().Done()
+			return
+		}
+		resp, err := http.Post(node.URL(), "application/json", bytes.NewReader(body))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestExecutor_QuarantinesGasCoins(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		delay    time.Duration
+		received string
+	}{
+		// the first transaction is executed after its submission timed out, the second one must
+		// wait for it to use the coin at its new version
+		{name: "executed late", delay: 200 * time.Millisecond, received: "20"},
+		// the first transaction is never executed, the coin is reloaded after the grace period
+		{name: "never executed", delay: -1, received: "10"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			node, _, signer, owner := newTestExecutor(t)
+			cli, err := Dial(delayedNode(t, node, test.delay))
+			require.NoError(t, err)
+			_, recipient := testAccount(t, 9)
+			gas := node.Mint(owner, "", 1_000_000_000)
+			ctx := context.Background()
+
+			executor, err := NewExecutor(
+				ctx, cli, signer, owner,
+				WithExecutorGasCoins(&gas),
+				WithExecutorGasBudget(10_000_000),
+				WithExecutorQuarantine(500*time.Millisecond, 10*time.Millisecond),
+			)
+			require.NoError(t, err)
+			defer executor.Close()
+
+			timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+			defer cancel()
+			_, err = executor.Execute(timeoutCtx, ExecutorJob{Build: splitTransfer(owner, recipient, nil, 10)})
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+
+			resp, err := executor.Execute(ctx, ExecutorJob{Build: splitTransfer(owner, recipient, nil, 10)})
+			require.NoError(t, err)
+			require.True(t, resp.Effects.Data.IsSuccess())
+			balance, err := cli.GetBalance(ctx, recipient, "")
+			require.NoError(t, err)
+			require.Equal(t, test.received, balance.TotalBalance.String())
+			require.Equal(t, 1, executor.GasCoins())
+		})
+	}
+}
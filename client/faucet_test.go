@@ -7,6 +7,7 @@ import (
 )
 
 func TestFaucetFundAccount_Devnet(t *testing.T) {
+	RequireNetwork(t)
 	// addr := M1Account(t).Address
 	addr := "0xd77955e670f42c1bc5e94b9e68e5fe9bdbed9134d784f2a14dfe5fc1b24b5d9f"
 
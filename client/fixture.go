@@ -0,0 +1,319 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+)
+
+var ErrUnexpectedCall = errors.New("no recorded interaction matches the JSON-RPC request")
+
+// Interaction is a JSON-RPC request and its response as saved in a golden file. The IDs of the
+// messages are normalized: a request message gets its index in the request, a single call 0, and a
+// response message the index of the request message it answers.
+type Interaction struct {
+	Request  json.RawMessage `json:"request"`
+	Status   int             `json:"status"`
+	Response json.RawMessage `json:"response,omitempty"`
+	// Body is the response body when it is not json, like the html of a gateway error.
+	Body string `json:"body,omitempty"`
+}
+
+// normalizeRequest returns the request body with the IDs of its messages replaced by their indexes
+// and the keys sorted, and the original IDs.
+func normalizeRequest(body []byte) (json.RawMessage, []json.RawMessage, error) {
+	body = bytes.TrimSpace(body)
+	batch := len(body) > 0 && body[0] == '['
+	var msgs []map[string]json.RawMessage
+	if batch {
+		if err := json.Unmarshal(body, &msgs); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		var msg map[string]json.RawMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return nil, nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	ids := make([]json.RawMessage, len(msgs))
+	for i, msg := range msgs {
+		ids[i] = msg["id"]
+		msg["id"] = strconv.AppendInt(nil, int64(i), 10)
+	}
+	var normalized interface{} = msgs
+	if !batch {
+		normalized = msgs[0]
+	}
+	canonical, err := canonicalJson(normalized)
+	return canonical, ids, err
+}
+
+// canonicalJson marshals v with the object keys sorted and the numbers kept as written.
+func canonicalJson(v interface{}) (json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return marshalFixture(value, "")
+}
+
+// marshalFixture marshals v without escaping the html characters, which Move types are full of.
+func marshalFixture(v interface{}, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// mapResponseIds rewrites the IDs of the messages of a response body with mapping, the messages
+// with an ID it does not know are left alone.
+func mapResponseIds(body []byte, mapping func(id json.RawMessage) (json.RawMessage, bool)) (json.RawMessage, error) {
+	body = bytes.TrimSpace(body)
+	rewrite := func(msg map[string]json.RawMessage) {
+		if id, ok := mapping(msg["id"]); ok {
+			msg["id"] = id
+		}
+	}
+	if len(body) > 0 && body[0] == '[' {
+		var msgs []map[string]json.RawMessage
+		if err := json.Unmarshal(body, &msgs); err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			rewrite(msg)
+		}
+		return json.Marshal(msgs)
+	}
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, err
+	}
+	rewrite(msg)
+	return json.Marshal(msg)
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+func fixtureResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// Recorder is an http.RoundTripper recording the JSON-RPC requests sent through it and the
+// responses of the node, Save writes them to a golden file read by NewReplayer.
+//
+//	recorder := client.NewRecorder(nil)
+//	cli, err := client.DialWithClient(rpcUrl, &http.Client{Transport: recorder})
+//	...
+//	err = recorder.Save("testdata/stake.json")
+type Recorder struct {
+	next http.RoundTripper
+
+	lock         sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder records the requests sent by next, or by http.DefaultTransport when it is nil.
+func NewRecorder(next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{next: next}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	request, ids, err := normalizeRequest(body)
+	if err != nil {
+		return nil, fmt.Errorf("record request: %w", err)
+	}
+	sent := req.Clone(req.Context())
+	sent.Body = io.NopCloser(bytes.NewReader(body))
+	sent.ContentLength = int64(len(body))
+	resp, err := r.next.RoundTrip(sent)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{Request: request, Status: resp.StatusCode}
+	response, err := mapResponseIds(
+		respBody, func(id json.RawMessage) (json.RawMessage, bool) {
+			for i, sentId := range ids {
+				if bytes.Equal(id, sentId) {
+					return strconv.AppendInt(nil, int64(i), 10), true
+				}
+			}
+			return nil, false
+		},
+	)
+	if err == nil {
+		interaction.Response, err = canonicalJson(response)
+	}
+	if err != nil {
+		interaction.Response = nil
+		interaction.Body = string(respBody)
+	}
+	r.lock.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.lock.Unlock()
+	return resp, nil
+}
+
+// Interactions returns the interactions recorded so far, in the order of their responses.
+func (r *Recorder) Interactions() []Interaction {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the golden file at path.
+func (r *Recorder) Save(path string) error {
+	data, err := marshalFixture(r.Interactions(), "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Replayer is an http.RoundTripper answering the JSON-RPC requests with the responses of a golden
+// file, without a node. A request is answered by the first interaction not replayed yet whose
+// request is the same but for the IDs, so a call repeated by a flow gets the recorded responses in
+// order. A request without one fails with ErrUnexpectedCall.
+//
+//	replayer, err := client.NewReplayer("testdata/stake.json")
+//	cli, err := client.DialWithClient("http://replay", &http.Client{Transport: replayer})
+//	...
+//	err = replayer.Done()
+type Replayer struct {
+	lock         sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// NewReplayer reads the golden file written by Recorder.Save at path.
+func NewReplayer(path string) (*Replayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var interactions []Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return nil, fmt.Errorf("read golden file %s: %w", path, err)
+	}
+	return NewReplayerOf(interactions)
+}
+
+// NewReplayerOf replays the interactions, like the ones of Recorder.Interactions.
+func NewReplayerOf(interactions []Interaction) (*Replayer, error) {
+	r := &Replayer{interactions: interactions, replayed: make([]bool, len(interactions))}
+	for i := range r.interactions {
+		// the golden file may have been edited by hand
+		request, _, err := normalizeRequest(r.interactions[i].Request)
+		if err != nil {
+			return nil, fmt.Errorf("interaction %d: %w", i, err)
+		}
+		r.interactions[i].Request = request
+	}
+	return r, nil
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	request, ids, err := normalizeRequest(body)
+	if err != nil {
+		return nil, fmt.Errorf("replay request: %w", err)
+	}
+	interaction, ok := r.take(request)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedCall, request)
+	}
+	if interaction.Response == nil {
+		return fixtureResponse(req, interaction.Status, []byte(interaction.Body)), nil
+	}
+	response, err := mapResponseIds(
+		interaction.Response, func(id json.RawMessage) (json.RawMessage, bool) {
+			i, err := strconv.Atoi(string(id))
+			if err != nil || i < 0 || i >= len(ids) {
+				return nil, false
+			}
+			return ids[i], true
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return fixtureResponse(req, interaction.Status, response), nil
+}
+
+func (r *Replayer) take(request json.RawMessage) (Interaction, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for i, interaction := range r.interactions {
+		if !r.replayed[i] && bytes.Equal(interaction.Request, request) {
+			r.replayed[i] = true
+			return interaction, true
+		}
+	}
+	return Interaction{}, false
+}
+
+// Done returns an error listing the interactions which have not been replayed, a flow which
+// stopped early or skipped a call does not match its recording.
+func (r *Replayer) Done() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var missing []string
+	for i, interaction := range r.interactions {
+		if !r.replayed[i] {
+			missing = append(missing, string(interaction.Request))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%d recorded interactions not replayed: %v", len(missing), missing)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	standIn := newRpcStandIn(
+		t, map[string]rpcHandler{
+			getReferenceGasPrice.String(): func(params []json.RawMessage) (interface{}, error) {
+				return "1000", nil
+			},
+			getTotalTransactionBlocks.String(): func(params []json.RawMessage) (interface{}, error) {
+				return "42", nil
+			},
+		},
+	)
+	ctx := context.Background()
+	flow := func(cli *Client) error {
+		if _, err := cli.GetReferenceGasPrice(ctx); err != nil {
+			return err
+		}
+		batch := NewBatch()
+		gasPrice := BatchGetReferenceGasPrice(batch)
+		total := AddBatchCall[string](batch, SuiMethod("getTotalTransactionBlocks"))
+		if err := cli.SendBatch(ctx, batch); err != nil {
+			return err
+		}
+		if price, err := gasPrice.Get(); err != nil || price.Uint64() != 1000 {
+			return errors.New("unexpected gas price")
+		}
+		if count, err := total.Get(); err != nil || count != "42" {
+			return errors.New("unexpected transaction count")
+		}
+		var ignored interface{}
+		err := cli.CallContext(ctx, &ignored, SuiMethod("getNormalizedMoveModule"), "0x2", "coin")
+		var rpcErr *RPCError
+		if !errors.As(err, &rpcErr) || rpcErr.Code != RPCErrorMethodNotFound {
+			return errors.New("unexpected method")
+		}
+		return nil
+	}
+
+	recorder := NewRecorder(nil)
+	cli, err := DialWithClient(standIn.server.URL, &http.Client{Transport: recorder})
+	require.NoError(t, err)
+	require.NoError(t, flow(cli))
+	path := filepath.Join(t.TempDir(), "flow.json")
+	require.NoError(t, recorder.Save(path))
+	golden, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(golden), `"id": 0`)
+	require.NotContains(t, string(golden), `"id": 3`)
+
+	replayer, err := NewReplayer(path)
+	require.NoError(t, err)
+	cli, err = DialWithClient("http://replay", &http.Client{Transport: replayer})
+	require.NoError(t, err)
+	// the IDs of the replayed requests differ from the recorded ones
+	atomic.StoreUint32(&cli.idCounter, 100)
+	require.NoError(t, flow(cli))
+	require.NoError(t, replayer.Done())
+	require.ErrorIs(t, flow(cli), ErrUnexpectedCall)
+}
+
+func TestReplayer_UnexpectedCall(t *testing.T) {
+	replayer, err := NewReplayerOf(
+		[]Interaction{
+			{
+				Request:  json.RawMessage(`{"jsonrpc":"2.0","id":0,"method":"suix_getReferenceGasPrice"}`),
+				Status:   http.StatusOK,
+				Response: json.RawMessage(`{"jsonrpc":"2.0","id":0,"result":"750"}`),
+			},
+			{
+				Request: json.RawMessage(`{"jsonrpc":"2.0","id":0,"method":"suix_getReferenceGasPrice"}`),
+				Status:  http.StatusBadGateway,
+				Body:    "<html>bad gateway</html>",
+			},
+		},
+	)
+	require.NoError(t, err)
+	cli, err := DialWithClient("http://replay", &http.Client{Transport: replayer})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	price, err := cli.GetReferenceGasPrice(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(750), price.Uint64())
+	require.Error(t, replayer.Done())
+
+	// the same call gets the next recorded response
+	_, err = cli.GetReferenceGasPrice(ctx)
+	var httpErr HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusBadGateway, httpErr.StatusCode)
+	require.NoError(t, replayer.Done())
+
+	_, err = cli.GetReferenceGasPrice(ctx)
+	require.ErrorIs(t, err, ErrUnexpectedCall)
+	_, err = cli.GetTotalTransactionBlocks(ctx)
+	require.ErrorIs(t, err, ErrUnexpectedCall)
+}
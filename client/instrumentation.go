@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+// Span attributes set by the Client.
+const (
+	AttributeRpcMethod         = "rpc.method"
+	AttributeRpcEndpoint       = "rpc.endpoint"
+	AttributeTransactionDigest = "sui.transaction.digest"
+)
+
+// BatchSpanName is the span name of a BatchCallContext, its methods are in the rpc.method attribute.
+const BatchSpanName = "batch"
+
+// Metrics receives the measurements of the calls of a Client, the calls of a batch are measured
+// one by one with the duration of the whole batch.
+type Metrics interface {
+	ObserveLatency(method string, latency time.Duration)
+	// IncError counts a failed call, code is the JSON-RPC error code or 0 for other errors.
+	IncError(method string, code int)
+	// AddInFlight changes the number of calls of method in flight by delta.
+	AddInFlight(method string, delta int)
+}
+
+// Tracer starts a span for every call and batch of a Client, like an OpenTelemetry tracer.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttribute(key, value string)
+	// End ends the span, err is the error of the call if it failed.
+	End(err error)
+}
+
+// WithMetrics reports the calls of the Client to metrics, nil reports them nowhere.
+func WithMetrics(metrics Metrics) ClientOption {
+	return func(c *clientConfig) {
+		if metrics == nil {
+			metrics = noopMetrics{}
+		}
+		c.metrics = metrics
+	}
+}
+
+// WithTracer traces the calls of the Client with tracer, nil does not trace them.
+func WithTracer(tracer Tracer) ClientOption {
+	return func(c *clientConfig) {
+		if tracer == nil {
+			tracer = noopTracer{}
+		}
+		c.tracer = tracer
+	}
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveLatency(string, time.Duration) {}
+func (noopMetrics) IncError(string, int)                 {}
+func (noopMetrics) AddInFlight(string, int)              {}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, string) {}
+func (noopSpan) End(error)                   {}
+
+type spanContextKey struct{}
+
+// spanFromContext returns the span of the call in ctx, for the handlers to set the endpoint on.
+func spanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanContextKey{}).(Span); ok {
+		return span
+	}
+	return noopSpan{}
+}
+
+// startCall starts the span and the metrics of a call, the returned function ends them.
+func (c *Client) startCall(ctx context.Context, method string, args []interface{}) (context.Context, func(error)) {
+	start := time.Now()
+	c.metrics.AddInFlight(method, 1)
+	ctx, span := c.tracer.Start(ctx, method)
+	span.SetAttribute(AttributeRpcMethod, method)
+	// hashing the transaction is only worth it for a span which is recorded
+	if _, noop := c.tracer.(noopTracer); !noop {
+		if digest := transactionDigestOf(method, args); digest != "" {
+			span.SetAttribute(AttributeTransactionDigest, digest)
+		}
+	}
+	ctx = context.WithValue(ctx, spanContextKey{}, span)
+	return ctx, func(err error) {
+		c.metrics.AddInFlight(method, -1)
+		c.metrics.ObserveLatency(method, time.Since(start))
+		if err != nil {
+			c.metrics.IncError(method, errorCode(err))
+		}
+		span.End(err)
+	}
+}
+
+// startBatch starts the span of a batch and the metrics of its calls, the returned function ends
+// them once the errors of the elements are set.
+func (c *Client) startBatch(ctx context.Context, b []BatchElem) (context.Context, func(error)) {
+	start := time.Now()
+	methods := make([]string, len(b))
+	for i, elem := range b {
+		methods[i] = elem.Method
+		c.metrics.AddInFlight(elem.Method, 1)
+	}
+	ctx, span := c.tracer.Start(ctx, BatchSpanName)
+	span.SetAttribute(AttributeRpcMethod, strings.Join(methods, ","))
+	ctx = context.WithValue(ctx, spanContextKey{}, span)
+	return ctx, func(err error) {
+		latency := time.Since(start)
+		for _, elem := range b {
+			c.metrics.AddInFlight(elem.Method, -1)
+			c.metrics.ObserveLatency(elem.Method, latency)
+			elemErr := elem.Error
+			if err != nil {
+				elemErr = err
+			}
+			if elemErr != nil {
+				c.metrics.IncError(elem.Method, errorCode(elemErr))
+			}
+		}
+		span.End(err)
+	}
+}
+
+func errorCode(err error) int {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code
+	}
+	return 0
+}
+
+// transactionDigestOf returns the digest of the transaction a call executes or reads.
+func transactionDigestOf(method string, args []interface{}) string {
+	if len(args) == 0 {
+		return ""
+	}
+	switch method {
+	case executeTransactionBlock.String(), dryRunTransactionBlock.String():
+		if txBytes, ok := args[0].(suiBase64Data); ok {
+			return sui_types.NewTransactionDigestFromBytes(txBytes).String()
+		}
+	case getTransactionBlock.String():
+		if digest, ok := args[0].(suiDigest); ok {
+			return digest.String()
+		}
+	}
+	return ""
+}
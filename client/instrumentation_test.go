@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+type recordingMetrics struct {
+	lock      sync.Mutex
+	latencies map[string]int
+	errors    map[string][]int
+	inFlight  map[string]int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{latencies: map[string]int{}, errors: map[string][]int{}, inFlight: map[string]int{}}
+}
+
+func (m *recordingMetrics) ObserveLatency(method string, latency time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.latencies[method]++
+}
+
+func (m *recordingMetrics) IncError(method string, code int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.errors[method] = append(m.errors[method], code)
+}
+
+func (m *recordingMetrics) AddInFlight(method string, delta int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.inFlight[method] += delta
+}
+
+type recordedSpan struct {
+	name       string
+	attributes map[string]string
+	ended      bool
+	err        error
+}
+
+func (s *recordedSpan) SetAttribute(key, value string) {
+	s.attributes[key] = value
+}
+
+func (s *recordedSpan) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordedSpan{name: name, attributes: map[string]string{}}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func TestClient_Instrumentation(t *testing.T) {
+	standIn := newRpcStandIn(
+		t, map[string]rpcHandler{
+			executeTransactionBlock.String(): func(params []json.RawMessage) (interface{}, error) {
+				return json.RawMessage(`{"digest": "HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn"}`), nil
+			},
+			getReferenceGasPrice.String(): func(params []json.RawMessage) (interface{}, error) {
+				return "1000", nil
+			},
+			getTransactionBlock.String(): func(params []json.RawMessage) (interface{}, error) {
+				return nil, errors.New("Could not find the referenced transaction")
+			},
+		},
+	)
+	metrics := newRecordingMetrics()
+	tracer := &recordingTracer{}
+	cli, err := Dial(standIn.server.URL, WithMetrics(metrics), WithTracer(tracer))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	txBytes := suiBase64Data{1, 2, 3}
+	signature := sui_types.Signature{Ed25519SuiSignature: &sui_types.Ed25519SuiSignature{}}
+	_, err = cli.ExecuteTransactionBlock(ctx, txBytes, []sui_types.Signature{signature}, nil, "")
+	require.NoError(t, err)
+	digest, err := sui_types.NewDigest("HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn")
+	require.NoError(t, err)
+	_, err = cli.GetTransactionBlock(ctx, *digest, types.SuiTransactionBlockResponseOptions{})
+	require.Error(t, err)
+
+	batch := NewBatch()
+	BatchGetReferenceGasPrice(batch)
+	AddBatchCall[string](batch, SuiXMethod("missing"))
+	require.NoError(t, cli.SendBatch(ctx, batch))
+
+	require.Len(t, tracer.spans, 3)
+	execute := tracer.spans[0]
+	require.Equal(t, executeTransactionBlock.String(), execute.name)
+	require.True(t, execute.ended)
+	require.NoError(t, execute.err)
+	require.Equal(
+		t, map[string]string{
+			AttributeRpcMethod:         executeTransactionBlock.String(),
+			AttributeRpcEndpoint:       standIn.server.URL,
+			AttributeTransactionDigest: sui_types.NewTransactionDigestFromBytes(txBytes).String(),
+		}, execute.attributes,
+	)
+	lookup := tracer.spans[1]
+	require.Equal(t, digest.String(), lookup.attributes[AttributeTransactionDigest])
+	require.Error(t, lookup.err)
+	require.Equal(t, BatchSpanName, tracer.spans[2].name)
+	require.Equal(
+		t, getReferenceGasPrice.String()+",suix_missing", tracer.spans[2].attributes[AttributeRpcMethod],
+	)
+
+	require.Equal(t, 1, metrics.latencies[executeTransactionBlock.String()])
+	require.Equal(t, 1, metrics.latencies[getReferenceGasPrice.String()])
+	require.Equal(t, []int{-32000}, metrics.errors[getTransactionBlock.String()])
+	require.Equal(t, []int{RPCErrorMethodNotFound}, metrics.errors["suix_missing"])
+	require.Empty(t, metrics.errors[executeTransactionBlock.String()])
+	for method, inFlight := range metrics.inFlight {
+		require.Zero(t, inFlight, method)
+	}
+
+	// nil metrics and tracer are the no-op ones
+	cli, err = Dial(standIn.server.URL, WithMetrics(metrics), WithTracer(tracer), WithMetrics(nil), WithTracer(nil))
+	require.NoError(t, err)
+	_, err = cli.ExecuteTransactionBlock(ctx, txBytes, []sui_types.Signature{signature}, nil, "")
+	require.NoError(t, err)
+	require.Len(t, tracer.spans, 3)
+	require.Equal(t, 1, metrics.latencies[executeTransactionBlock.String()])
+}
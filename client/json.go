@@ -3,6 +3,8 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/thorli9527/sui-wallet-sdk/types"
 )
 
 const (
@@ -19,27 +21,54 @@ type jsonrpcMessage struct {
 	ID      json.RawMessage `json:"id,omitempty"`
 	Method  string          `json:"method,omitempty"`
 	Params  json.RawMessage `json:"params,omitempty"`
-	Error   *jsonError      `json:"error,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
 	Result  json.RawMessage `json:"result,omitempty"`
 }
 
-type jsonError struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+// Error codes of RPCError.
+const (
+	RPCErrorMethodNotFound       = -32601
+	RPCErrorInvalidParams        = -32602
+	RPCErrorInternal             = -32603
+	RPCErrorTransactionExecution = -32002
+	RPCErrorTransient            = -32050
+	// RPCErrorBatchTooLarge answers a batch with more requests than the node accepts.
+	RPCErrorBatchTooLarge = -32010
+)
+
+// RPCError is the error object of a JSON-RPC response. errors.Is matches it with the categories of
+// types.ClassifyError, and errors.As finds the *types.MoveAbort in its message.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
 }
 
-func (err *jsonError) Error() string {
+func (err *RPCError) Error() string {
 	if err.Message == "" {
 		return fmt.Sprintf("json-rpc error %d", err.Code)
 	}
 	return err.Message
 }
 
-func (err *jsonError) ErrorCode() int {
+func (err *RPCError) ErrorCode() int {
 	return err.Code
 }
 
-func (err *jsonError) ErrorData() interface{} {
+func (err *RPCError) ErrorData() interface{} {
 	return err.Data
 }
+
+func (err *RPCError) Is(target error) bool {
+	if target == types.ErrNodeOverloaded && err.Code == RPCErrorTransient {
+		return true
+	}
+	return target != nil && target == types.ClassifyError(err.Message)
+}
+
+func (err *RPCError) Unwrap() error {
+	if abort := types.ParseMoveAbort(err.Message); abort != nil {
+		return abort
+	}
+	return nil
+}
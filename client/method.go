@@ -73,6 +73,9 @@ const (
 	resolveNameServiceAddress         SuiXMethod   = "resolveNameServiceAddress"
 	resolveNameServiceNames           SuiXMethod   = "resolveNameServiceNames"
 	subscribeEvent                    SuiXMethod   = "subscribeEvent"
+	subscribeTransaction              SuiXMethod   = "subscribeTransaction"
+	unsubscribeEvent                  SuiXMethod   = "unsubscribeEvent"
+	unsubscribeTransaction            SuiXMethod   = "unsubscribeTransaction"
 	batchTransaction                  UnsafeMethod = "batchTransaction"
 	mergeCoins                        UnsafeMethod = "mergeCoins"
 	moveCall                          UnsafeMethod = "moveCall"
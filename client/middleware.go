@@ -0,0 +1,291 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Request is the http request of a JSON-RPC call or batch passed through the middleware chain.
+type Request struct {
+	// Methods of the calls in the request, a single one unless it is a batch.
+	Methods []string
+	Header  http.Header
+	Body    []byte
+}
+
+// Handler sends the Request and returns the response body, a status other than 2xx is returned as
+// an HTTPError.
+type Handler func(ctx context.Context, req *Request) ([]byte, error)
+
+// Middleware wraps the next Handler of the chain.
+type Middleware func(next Handler) Handler
+
+// LogHooks are called around every http request, retries included.
+type LogHooks struct {
+	OnRequest  func(ctx context.Context, req *Request)
+	OnResponse func(ctx context.Context, req *Request, body []byte, err error, elapsed time.Duration)
+}
+
+type clientConfig struct {
+	maxBatchSize int
+	metrics      Metrics
+	tracer       Tracer
+	middlewares  []Middleware
+	retry        Middleware
+	rateLimit    Middleware
+	header       http.Header
+	headerFuncs  []func(ctx context.Context) (http.Header, error)
+	logHooks     *LogHooks
+}
+
+// ClientOption configures a Client and its middleware chain. A request passes through the
+// WithMiddleware middlewares in order, then the retry, the rate limit, the headers and the log hooks.
+type ClientOption func(*clientConfig)
+
+// WithMaxBatchSize sets how many calls BatchCallContext sends in one request, larger batches are
+// split. Zero sends every batch whole.
+func WithMaxBatchSize(size int) ClientOption {
+	return func(c *clientConfig) {
+		c.maxBatchSize = size
+	}
+}
+
+// WithMiddleware appends custom middlewares, they wrap all the others.
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(c *clientConfig) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithRetry retries network errors and the 408, 429 and 5xx statuses up to maxAttempts times in
+// total, with an exponential backoff from minDelay to maxDelay and jitter. A Retry-After header
+// delays the next attempt at least as long as it asks.
+func WithRetry(maxAttempts int, minDelay, maxDelay time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.retry = retryMiddleware(maxAttempts, minDelay, maxDelay)
+	}
+}
+
+// WithRateLimit limits the http requests, retries included, to ratePerSecond with bursts of burst
+// requests. A request waits for its turn unless its context is done first. A rate which is not
+// positive leaves the requests unlimited, a burst below 1 is 1.
+func WithRateLimit(ratePerSecond float64, burst int) ClientOption {
+	return func(c *clientConfig) {
+		if !(ratePerSecond > 0) {
+			c.rateLimit = nil
+			return
+		}
+		bucket := newTokenBucket(ratePerSecond, burst)
+		c.rateLimit = func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) ([]byte, error) {
+				if err := bucket.wait(ctx); err != nil {
+					return nil, err
+				}
+				return next(ctx, req)
+			}
+		}
+	}
+}
+
+// WithHeader sets a header on every request, like the API key of a provider.
+func WithHeader(key, value string) ClientOption {
+	return func(c *clientConfig) {
+		if c.header == nil {
+			c.header = http.Header{}
+		}
+		c.header.Set(key, value)
+	}
+}
+
+// WithHeaderFunc sets the headers returned by fn on every request, fn is called for each attempt so
+// it can refresh a token. An error of fn fails the request.
+func WithHeaderFunc(fn func(ctx context.Context) (http.Header, error)) ClientOption {
+	return func(c *clientConfig) {
+		c.headerFuncs = append(c.headerFuncs, fn)
+	}
+}
+
+// WithLogHooks calls hooks before and after every http request.
+func WithLogHooks(hooks LogHooks) ClientOption {
+	return func(c *clientConfig) {
+		c.logHooks = &hooks
+	}
+}
+
+func newClientConfig(options []ClientOption) *clientConfig {
+	config := &clientConfig{maxBatchSize: 50, metrics: noopMetrics{}, tracer: noopTracer{}}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+func (c *clientConfig) apply(client *Client, send Handler) {
+	client.maxBatchSize = c.maxBatchSize
+	client.metrics = c.metrics
+	client.tracer = c.tracer
+	client.handler = c.chain(send)
+}
+
+func (c *clientConfig) chain(handler Handler) Handler {
+	var middlewares []Middleware
+	middlewares = append(middlewares, c.middlewares...)
+	for _, m := range []Middleware{c.retry, c.rateLimit, c.headerMiddleware(), c.logMiddleware()} {
+		if m != nil {
+			middlewares = append(middlewares, m)
+		}
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+func (c *clientConfig) headerMiddleware() Middleware {
+	if len(c.header) == 0 && len(c.headerFuncs) == 0 {
+		return nil
+	}
+	static := c.header
+	funcs := c.headerFuncs
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) ([]byte, error) {
+			for key, values := range static {
+				req.Header[key] = values
+			}
+			for _, fn := range funcs {
+				header, err := fn(ctx)
+				if err != nil {
+					return nil, err
+				}
+				for key, values := range header {
+					req.Header[http.CanonicalHeaderKey(key)] = values
+				}
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+func (c *clientConfig) logMiddleware() Middleware {
+	if c.logHooks == nil {
+		return nil
+	}
+	hooks := *c.logHooks
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) ([]byte, error) {
+			if hooks.OnRequest != nil {
+				hooks.OnRequest(ctx, req)
+			}
+			start := time.Now()
+			body, err := next(ctx, req)
+			if hooks.OnResponse != nil {
+				hooks.OnResponse(ctx, req, body, err, time.Since(start))
+			}
+			return body, err
+		}
+	}
+}
+
+func retryMiddleware(maxAttempts int, minDelay, maxDelay time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) ([]byte, error) {
+			delay := minDelay
+			for attempt := 1; ; attempt++ {
+				body, err := next(ctx, req)
+				if err == nil || attempt >= maxAttempts || !isRetryableHTTPError(ctx, err) {
+					return body, err
+				}
+				wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+				if retryAfter := retryAfterDelay(err); retryAfter > wait {
+					wait = retryAfter
+				}
+				if err := sleepContext(ctx, wait); err != nil {
+					return nil, err
+				}
+				delay *= 2
+				if delay > maxDelay {
+					delay = maxDelay
+				}
+			}
+		}
+	}
+}
+
+func isRetryableHTTPError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusRequestTimeout ||
+			httpErr.StatusCode == http.StatusTooManyRequests ||
+			httpErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// retryAfterDelay returns the delay of the Retry-After header of an HTTPError, in seconds or as a date.
+func retryAfterDelay(err error) time.Duration {
+	var httpErr HTTPError
+	if !errors.As(err, &httpErr) {
+		return 0
+	}
+	value := httpErr.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// tokenBucket lets through rate requests per second with bursts of burst requests, rate is positive.
+type tokenBucket struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait takes a token, the tokens go negative to queue the waiting requests in order.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.lock.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.lock.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, delay); err != nil {
+		b.lock.Lock()
+		b.tokens++
+		b.lock.Unlock()
+		return err
+	}
+	return nil
+}
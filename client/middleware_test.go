@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// flakyNode answers with the queued statuses first, then with a JSON-RPC result.
+type flakyNode struct {
+	lock     sync.Mutex
+	statuses []int
+	header   http.Header
+	requests []*http.Request
+}
+
+func (n *flakyNode) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	n.lock.Lock()
+	n.requests = append(n.requests, r)
+	status := http.StatusOK
+	if len(n.statuses) > 0 {
+		status, n.statuses = n.statuses[0], n.statuses[1:]
+	}
+	n.lock.Unlock()
+	for key, values := range n.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(status)
+	if status == http.StatusOK {
+		_, _ = w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": "42"}`))
+	}
+}
+
+func (n *flakyNode) client(t *testing.T, options ...ClientOption) *Client {
+	server := httptest.NewServer(http.HandlerFunc(n.serveHTTP))
+	t.Cleanup(server.Close)
+	cli, err := Dial(server.URL, options...)
+	require.NoError(t, err)
+	return cli
+}
+
+func (n *flakyNode) fail(statuses ...int) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.statuses = statuses
+}
+
+func (n *flakyNode) requestCount() int {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return len(n.requests)
+}
+
+func TestMiddleware_Retry(t *testing.T) {
+	node := &flakyNode{statuses: []int{http.StatusTooManyRequests, http.StatusBadGateway}}
+	var attempts []error
+	cli := node.client(
+		t, WithRetry(3, time.Millisecond, 2*time.Millisecond), WithLogHooks(
+			LogHooks{
+				OnResponse: func(ctx context.Context, req *Request, body []byte, err error, elapsed time.Duration) {
+					require.Equal(t, []string{getTotalTransactionBlocks.String()}, req.Methods)
+					attempts = append(attempts, err)
+				},
+			},
+		),
+	)
+
+	var total string
+	require.NoError(t, cli.CallContext(context.Background(), &total, getTotalTransactionBlocks))
+	require.Equal(t, "42", total)
+	require.Equal(t, 3, node.requestCount())
+	require.Len(t, attempts, 3)
+	var httpErr HTTPError
+	require.True(t, errors.As(attempts[0], &httpErr))
+	require.Equal(t, http.StatusTooManyRequests, httpErr.StatusCode)
+	require.NoError(t, attempts[2])
+
+	node.fail(http.StatusBadRequest)
+	err := cli.CallContext(context.Background(), &total, getTotalTransactionBlocks)
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusBadRequest, httpErr.StatusCode)
+	require.Equal(t, 4, node.requestCount())
+}
+
+func TestMiddleware_RetryAfter(t *testing.T) {
+	node := &flakyNode{
+		statuses: []int{http.StatusServiceUnavailable},
+		header:   http.Header{"Retry-After": []string{"1"}},
+	}
+	cli := node.client(t, WithRetry(2, time.Millisecond, time.Millisecond))
+
+	start := time.Now()
+	var total string
+	require.NoError(t, cli.CallContext(context.Background(), &total, getTotalTransactionBlocks))
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+
+	node.fail(http.StatusServiceUnavailable)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := cli.CallContext(ctx, &total, getTotalTransactionBlocks)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestMiddleware_Headers(t *testing.T) {
+	node := &flakyNode{}
+	tokens := 0
+	var order []string
+	cli := node.client(
+		t,
+		WithHeader("x-api-key", "secret"),
+		WithHeaderFunc(
+			func(ctx context.Context) (http.Header, error) {
+				tokens++
+				if tokens > 1 {
+					return nil, errors.New("token expired")
+				}
+				return http.Header{"authorization": []string{"Bearer 1"}}, nil
+			},
+		),
+		WithMiddleware(
+			func(next Handler) Handler {
+				return func(ctx context.Context, req *Request) ([]byte, error) {
+					order = append(order, "outer")
+					require.Empty(t, req.Header.Get("X-Api-Key"))
+					return next(ctx, req)
+				}
+			},
+		),
+		WithLogHooks(
+			LogHooks{
+				OnRequest: func(ctx context.Context, req *Request) {
+					order = append(order, "log")
+					require.Equal(t, "secret", req.Header.Get("X-Api-Key"))
+				},
+			},
+		),
+	)
+
+	var total string
+	require.NoError(t, cli.CallContext(context.Background(), &total, getTotalTransactionBlocks))
+	require.Equal(t, []string{"outer", "log"}, order)
+	require.Equal(t, 1, node.requestCount())
+	header := node.requests[0].Header
+	require.Equal(t, "secret", header.Get("X-Api-Key"))
+	require.Equal(t, "Bearer 1", header.Get("Authorization"))
+	require.Equal(t, "application/json", header.Get("Content-Type"))
+
+	err := cli.CallContext(context.Background(), &total, getTotalTransactionBlocks)
+	require.EqualError(t, err, "token expired")
+	require.Equal(t, 1, node.requestCount())
+}
+
+func TestMiddleware_RateLimit(t *testing.T) {
+	node := &flakyNode{}
+	cli := node.client(t, WithRateLimit(20, 2))
+
+	start := time.Now()
+	var total string
+	for i := 0; i < 4; i++ {
+		require.NoError(t, cli.CallContext(context.Background(), &total, getTotalTransactionBlocks))
+	}
+	// the burst passes at once, the next two wait 50ms each
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, cli.CallContext(ctx, &total, getTotalTransactionBlocks), context.DeadlineExceeded)
+	require.Equal(t, 4, node.requestCount())
+
+	// a rate which is not positive does not limit the requests
+	for _, rate := range []float64{0, -1, math.NaN()} {
+		node := &flakyNode{}
+		cli := node.client(t, WithRateLimit(rate, 0))
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		for i := 0; i < 5; i++ {
+			require.NoError(t, cli.CallContext(ctx, &total, getTotalTransactionBlocks))
+		}
+		cancel()
+		require.Equal(t, 5, node.requestCount())
+	}
+}
@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+var ErrIteratorDone = errors.New("no more items in iterator")
+
+type iteratorConfig struct {
+	pageSize uint
+	maxItems int
+	prefetch bool
+}
+
+type IteratorOption func(*iteratorConfig)
+
+// WithIteratorPageSize sets the limit of every page request, 0 uses the node default.
+func WithIteratorPageSize(size uint) IteratorOption {
+	return func(c *iteratorConfig) {
+		c.pageSize = size
+	}
+}
+
+// WithMaxItems stops the iterator after n items, 0 means no limit.
+func WithMaxItems(n int) IteratorOption {
+	return func(c *iteratorConfig) {
+		c.maxItems = n
+	}
+}
+
+// WithPrefetch requests the next page in the background while the current one is consumed.
+func WithPrefetch() IteratorOption {
+	return func(c *iteratorConfig) {
+		c.prefetch = true
+	}
+}
+
+// PageFetcher requests the page after cursor, a nil cursor starts from the first item.
+type PageFetcher[T types.PageData, C types.PageCursor] func(
+	ctx context.Context,
+	cursor *C,
+	limit uint,
+) (*types.Page[T, C], error)
+
+type pageResult[T types.PageData, C types.PageCursor] struct {
+	page *types.Page[T, C]
+	err  error
+}
+
+// PageIterator follows NextCursor until HasNextPage is false. A failed page request is
+// returned by Next and retried on the following call.
+type PageIterator[T types.PageData, C types.PageCursor] struct {
+	fetch  PageFetcher[T, C]
+	config iteratorConfig
+
+	buffer  []T
+	cursor  *C
+	hasNext bool
+	yielded int
+
+	prefetched     chan pageResult[T, C]
+	cancelPrefetch context.CancelFunc
+}
+
+func NewPageIterator[T types.PageData, C types.PageCursor](
+	fetch PageFetcher[T, C],
+	options ...IteratorOption,
+) *PageIterator[T, C] {
+	it := &PageIterator[T, C]{
+		fetch:   fetch,
+		hasNext: true,
+	}
+	for _, option := range options {
+		option(&it.config)
+	}
+	return it
+}
+
+// Next returns the next item, or ErrIteratorDone once every item was returned.
+func (it *PageIterator[T, C]) Next(ctx context.Context) (T, error) {
+	var zero T
+	if it.config.maxItems > 0 && it.yielded >= it.config.maxItems {
+		it.Close()
+		return zero, ErrIteratorDone
+	}
+	for len(it.buffer) == 0 {
+		if !it.hasNext {
+			return zero, ErrIteratorDone
+		}
+		page, err := it.nextPage(ctx)
+		if err != nil {
+			return zero, err
+		}
+		it.buffer = page.Data
+		it.hasNext = page.HasNextPage && page.NextCursor != nil
+		if page.NextCursor != nil {
+			it.cursor = page.NextCursor
+		}
+		if len(page.Data) == 0 {
+			// a node must not return an empty page that has a next page, stop instead of spinning
+			it.hasNext = false
+		}
+		if it.config.prefetch && it.hasNext {
+			it.startPrefetch(ctx)
+		}
+	}
+	item := it.buffer[0]
+	it.buffer = it.buffer[1:]
+	it.yielded++
+	return item, nil
+}
+
+// Collect returns all the remaining items.
+func (it *PageIterator[T, C]) Collect(ctx context.Context) ([]T, error) {
+	var items []T
+	for {
+		item, err := it.Next(ctx)
+		if errors.Is(err, ErrIteratorDone) {
+			return items, nil
+		}
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+}
+
+// Cursor returns the cursor of the next page to request, it can start another iterator later.
+func (it *PageIterator[T, C]) Cursor() *C {
+	return it.cursor
+}
+
+// Close stops a running prefetch, the iterator must not be used afterwards.
+func (it *PageIterator[T, C]) Close() {
+	if it.cancelPrefetch != nil {
+		it.cancelPrefetch()
+		it.cancelPrefetch = nil
+	}
+	it.prefetched = nil
+	it.hasNext = false
+}
+
+func (it *PageIterator[T, C]) limit() uint {
+	limit := it.config.pageSize
+	if it.config.maxItems > 0 {
+		remaining := uint(it.config.maxItems - it.yielded)
+		if limit == 0 || remaining < limit {
+			limit = remaining
+		}
+	}
+	return limit
+}
+
+func (it *PageIterator[T, C]) nextPage(ctx context.Context) (*types.Page[T, C], error) {
+	if it.prefetched == nil {
+		return it.fetch(ctx, it.cursor, it.limit())
+	}
+	select {
+	case result := <-it.prefetched:
+		it.prefetched = nil
+		it.cancelPrefetch()
+		it.cancelPrefetch = nil
+		if result.err != nil && ctx.Err() == nil &&
+			(errors.Is(result.err, context.Canceled) || errors.Is(result.err, context.DeadlineExceeded)) {
+			// the context of the call which started the prefetch is gone, but this one is alive
+			return it.fetch(ctx, it.cursor, it.limit())
+		}
+		return result.page, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (it *PageIterator[T, C]) startPrefetch(ctx context.Context) {
+	limit := it.config.pageSize
+	if it.config.maxItems > 0 {
+		remaining := it.config.maxItems - it.yielded - len(it.buffer)
+		if remaining <= 0 {
+			return
+		}
+		if limit == 0 || uint(remaining) < limit {
+			limit = uint(remaining)
+		}
+	}
+	prefetchCtx, cancel := context.WithCancel(ctx)
+	result := make(chan pageResult[T, C], 1)
+	cursor := it.cursor
+	go func() {
+		page, err := it.fetch(prefetchCtx, cursor, limit)
+		result <- pageResult[T, C]{page: page, err: err}
+	}()
+	it.prefetched = result
+	it.cancelPrefetch = cancel
+}
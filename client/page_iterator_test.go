@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+type coinLedger struct {
+	lock   sync.Mutex
+	coins  []types.Coin
+	limits []uint
+}
+
+func newCoinLedger(count int) *coinLedger {
+	l := &coinLedger{}
+	for i := 0; i < count; i++ {
+		id, _ := sui_types.NewObjectIdFromHex(fmt.Sprintf("0x%x", i+1))
+		l.coins = append(
+			l.coins, types.Coin{
+				CoinType:     types.SuiCoinType,
+				CoinObjectId: *id,
+				Balance:      types.NewSafeSuiBigInt(uint64(i)),
+			},
+		)
+	}
+	return l
+}
+
+func (l *coinLedger) getCoins(params []json.RawMessage) (interface{}, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	var cursor *suiObjectID
+	var limit *uint
+	_ = json.Unmarshal(params[2], &cursor)
+	_ = json.Unmarshal(params[3], &limit)
+	size := uint(50)
+	if limit != nil {
+		size = *limit
+	}
+	l.limits = append(l.limits, size)
+	start := 0
+	if cursor != nil {
+		for i := range l.coins {
+			if l.coins[i].CoinObjectId == *cursor {
+				start = i + 1
+			}
+		}
+	}
+	end := start + int(size)
+	if end > len(l.coins) {
+		end = len(l.coins)
+	}
+	page := types.CoinPage{Data: l.coins[start:end], HasNextPage: end < len(l.coins)}
+	if end > start {
+		page.NextCursor = &l.coins[end-1].CoinObjectId
+	}
+	return page, nil
+}
+
+func newCoinStandIn(t *testing.T, ledger *coinLedger) (*rpcStandIn, *Client) {
+	standIn := newRpcStandIn(t, map[string]rpcHandler{getCoins.String(): ledger.getCoins})
+	return standIn, standIn.client(t)
+}
+
+func TestClient_GetSuiCoinsOwnedByAddress_AllPages(t *testing.T) {
+	ledger := newCoinLedger(450)
+	standIn, cli := newCoinStandIn(t, ledger)
+
+	coins, err := cli.GetSuiCoinsOwnedByAddress(context.Background(), sui_types.SuiAddress{})
+	require.NoError(t, err)
+	require.Len(t, coins, 450)
+	require.Equal(t, uint64(449), coins[449].Balance.Uint64())
+	require.Equal(t, 3, standIn.callCount(getCoins.String()))
+}
+
+func TestPageIterator_MaxItemsAndPrefetch(t *testing.T) {
+	ledger := newCoinLedger(30)
+	_, cli := newCoinStandIn(t, ledger)
+	ctx := context.Background()
+
+	it := cli.GetCoinsIterator(sui_types.SuiAddress{}, nil, WithIteratorPageSize(4), WithMaxItems(10), WithPrefetch())
+	coins, err := it.Collect(ctx)
+	require.NoError(t, err)
+	require.Len(t, coins, 10)
+	require.Equal(t, []uint{4, 4, 2}, ledger.limits)
+
+	// the cursor of a finished iterator resumes after the last item
+	resumed := NewPageIterator(
+		func(ctx context.Context, cursor *suiObjectID, limit uint) (*types.CoinPage, error) {
+			if cursor == nil {
+				cursor = it.Cursor()
+			}
+			return cli.GetCoinsIterator(sui_types.SuiAddress{}, nil).fetch(ctx, cursor, limit)
+		},
+	)
+	rest, err := resumed.Collect(ctx)
+	require.NoError(t, err)
+	require.Len(t, rest, 20)
+	require.Equal(t, uint64(10), rest[0].Balance.Uint64())
+}
+
+func TestPageIterator_ErrorAndCancel(t *testing.T) {
+	failure := errors.New("node overloaded")
+	fails := 1
+	it := NewPageIterator(
+		func(ctx context.Context, cursor *types.EventId, limit uint) (*types.EventPage, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if fails > 0 {
+				fails--
+				return nil, failure
+			}
+			next := types.EventId{EventSeq: types.NewSafeSuiBigInt(uint64(1))}
+			if cursor != nil {
+				next.EventSeq = types.NewSafeSuiBigInt(cursor.EventSeq.Uint64() + 1)
+			}
+			return &types.EventPage{Data: []types.SuiEvent{{Id: next}}, NextCursor: &next, HasNextPage: true}, nil
+		},
+	)
+
+	_, err := it.Next(context.Background())
+	require.ErrorIs(t, err, failure)
+	event, err := it.Next(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), event.Id.EventSeq.Uint64())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = it.Next(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	it.Close()
+	_, err = it.Next(context.Background())
+	require.ErrorIs(t, err, ErrIteratorDone)
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type rpcHandler func(params []json.RawMessage) (interface{}, error)
+
+// rpcStandIn is a local http JSON-RPC node answering with the registered handlers.
+type rpcStandIn struct {
+	server *httptest.Server
+
+	lock     sync.Mutex
+	handlers map[string]rpcHandler
+	calls    map[string]int
+}
+
+func newRpcStandIn(t *testing.T, handlers map[string]rpcHandler) *rpcStandIn {
+	s := &rpcStandIn{handlers: handlers, calls: make(map[string]int)}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *rpcStandIn) client(t *testing.T) *Client {
+	cli, err := Dial(s.server.URL)
+	require.NoError(t, err)
+	return cli
+}
+
+func (s *rpcStandIn) handle(method string, handler rpcHandler) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.handlers[method] = handler
+}
+
+func (s *rpcStandIn) callCount(method string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.calls[method]
+}
+
+func (s *rpcStandIn) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if len(raw) > 0 && raw[0] == '[' {
+		var msgs []jsonrpcMessage
+		_ = json.Unmarshal(raw, &msgs)
+		resps := make([]*jsonrpcMessage, len(msgs))
+		for i := range msgs {
+			resps[i] = s.answer(&msgs[i])
+		}
+		_ = json.NewEncoder(w).Encode(resps)
+		return
+	}
+	var msg jsonrpcMessage
+	_ = json.Unmarshal(raw, &msg)
+	_ = json.NewEncoder(w).Encode(s.answer(&msg))
+}
+
+func (s *rpcStandIn) answer(msg *jsonrpcMessage) *jsonrpcMessage {
+	s.lock.Lock()
+	handler, ok := s.handlers[msg.Method]
+	s.calls[msg.Method]++
+	s.lock.Unlock()
+	resp := &jsonrpcMessage{Version: vsn, ID: msg.ID}
+	if !ok {
+		resp.Error = &RPCError{Code: -32601, Message: "Method not found"}
+		return resp
+	}
+	var params []json.RawMessage
+	if len(msg.Params) > 0 {
+		_ = json.Unmarshal(msg.Params, &params)
+	}
+	result, err := handler(params)
+	if err != nil {
+		if jsonErr, ok := err.(*RPCError); ok {
+			resp.Error = jsonErr
+		} else {
+			resp.Error = &RPCError{Code: -32000, Message: err.Error()}
+		}
+		return resp
+	}
+	resp.Result, _ = json.Marshal(result)
+	return resp
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+// StakePosition is a StakedSui of a StakePortfolio.
+type StakePosition struct {
+	Validator   suiAddress
+	StakingPool suiObjectID
+	StakedSuiId suiObjectID
+
+	Principal uint64
+	// EstimatedReward is the reward earned so far, estimated by the node for active stakes only.
+	EstimatedReward uint64
+	RequestEpoch    types.EpochId
+	// ActivationEpoch is the epoch the stake starts earning rewards, the one after RequestEpoch.
+	ActivationEpoch types.EpochId
+	// Status is types.StakeStatusActive, types.StakeStatusPending or types.StakeStatusUnstaked.
+	Status  string
+	Pending bool
+
+	// Apy is the current APY of the validator, 0.05 is 5%, and 0 when the node does not know it.
+	Apy float64
+}
+
+// ProjectedAnnualReward is the reward of the principal in a year at the current APY of the validator.
+func (p *StakePosition) ProjectedAnnualReward() uint64 {
+	reward, _ := new(big.Float).Mul(new(big.Float).SetUint64(p.Principal), big.NewFloat(p.Apy)).Uint64()
+	return reward
+}
+
+// StakePortfolio is the view of the stakes of an owner, see Client.GetStakePortfolio.
+type StakePortfolio struct {
+	Owner     suiAddress
+	Epoch     types.EpochId
+	Positions []StakePosition
+
+	TotalPrincipal       *big.Int
+	TotalEstimatedReward *big.Int
+	// TotalPending is the principal of the pending stakes, which earn no reward yet.
+	TotalPending *big.Int
+}
+
+// TotalValue is the principal and the estimated rewards of all the stakes, what withdrawing them
+// would return.
+func (p *StakePortfolio) TotalValue() *big.Int {
+	return new(big.Int).Add(p.TotalPrincipal, p.TotalEstimatedReward)
+}
+
+// GetStakePortfolio combines the stakes of owner with the APY of their validators.
+func (c *Client) GetStakePortfolio(ctx context.Context, owner suiAddress) (*StakePortfolio, error) {
+	stakes, err := c.GetStakes(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	apys, err := c.GetValidatorsApy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewStakePortfolio(owner, stakes, apys), nil
+}
+
+// NewStakePortfolio builds the portfolio of owner from the result of Client.GetStakes and
+// Client.GetValidatorsApy.
+func NewStakePortfolio(owner suiAddress, stakes []types.DelegatedStake, apys *types.ValidatorsApy) *StakePortfolio {
+	apyOf := make(map[suiAddress]float64, len(apys.Apys))
+	for _, apy := range apys.Apys {
+		if address, err := sui_types.NewAddressFromHex(apy.Address); err == nil {
+			apyOf[*address] = apy.Apy
+		}
+	}
+	portfolio := &StakePortfolio{
+		Owner:                owner,
+		Epoch:                apys.Epoch.Uint64(),
+		TotalPrincipal:       new(big.Int),
+		TotalEstimatedReward: new(big.Int),
+		TotalPending:         new(big.Int),
+	}
+	for _, delegated := range stakes {
+		for _, stake := range delegated.Stakes {
+			position := StakePosition{
+				Validator:       delegated.ValidatorAddress,
+				StakingPool:     delegated.StakingPool,
+				StakedSuiId:     stake.Data.StakedSuiId,
+				Principal:       stake.Data.Principal.Uint64(),
+				RequestEpoch:    stake.Data.StakeRequestEpoch.Uint64(),
+				ActivationEpoch: stake.Data.StakeActiveEpoch.Uint64(),
+				Apy:             apyOf[delegated.ValidatorAddress],
+			}
+			principal := new(big.Int).SetUint64(position.Principal)
+			switch status := stake.Data.StakeStatus; {
+			case status == nil || status.Data.Pending != nil:
+				position.Status = types.StakeStatusPending
+				position.Pending = true
+				portfolio.TotalPending.Add(portfolio.TotalPending, principal)
+			case status.Data.Active != nil:
+				position.Status = types.StakeStatusActive
+				position.EstimatedReward = status.Data.Active.EstimatedReward.Uint64()
+			default:
+				position.Status = types.StakeStatusUnstaked
+			}
+			portfolio.TotalPrincipal.Add(portfolio.TotalPrincipal, principal)
+			portfolio.TotalEstimatedReward.Add(
+				portfolio.TotalEstimatedReward, new(big.Int).SetUint64(position.EstimatedReward),
+			)
+			portfolio.Positions = append(portfolio.Positions, position)
+		}
+	}
+	return portfolio
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+func TestClient_GetStakePortfolio(t *testing.T) {
+	stakes := `[{
+		"validatorAddress": "` + ComingChatValidatorAddress + `",
+		"stakingPool": "0x01",
+		"stakes": [
+			{"stakedSuiId": "0x11", "stakeRequestEpoch": "99", "stakeActiveEpoch": "100", "principal": "5000000000", "status": "Active", "estimatedReward": "25000000"},
+			{"stakedSuiId": "0x12", "stakeRequestEpoch": "120", "stakeActiveEpoch": "121", "principal": "2000000000", "status": "Pending"}
+		]
+	}, {
+		"validatorAddress": "0x02",
+		"stakingPool": "0x03",
+		"stakes": [
+			{"stakedSuiId": "0x13", "stakeRequestEpoch": "80", "stakeActiveEpoch": "81", "principal": "1000000000", "status": "Unstaked"}
+		]
+	}]`
+	apys := `{"epoch": "120", "apys": [{"address": "` + ComingChatValidatorAddress + `", "apy": 0.05}]}`
+	standIn := newRpcStandIn(
+		t, map[string]rpcHandler{
+			getStakes.String(): func(params []json.RawMessage) (interface{}, error) {
+				return json.RawMessage(stakes), nil
+			},
+			getValidatorsApy.String(): func(params []json.RawMessage) (interface{}, error) {
+				return json.RawMessage(apys), nil
+			},
+		},
+	)
+	_, owner := testAccount(t, 1)
+	portfolio, err := standIn.client(t).GetStakePortfolio(context.Background(), owner)
+	require.NoError(t, err)
+
+	require.Equal(t, types.EpochId(120), portfolio.Epoch)
+	require.Len(t, portfolio.Positions, 3)
+	active, pending, unstaked := portfolio.Positions[0], portfolio.Positions[1], portfolio.Positions[2]
+	require.Equal(t, types.StakeStatusActive, active.Status)
+	require.Equal(t, uint64(25_000_000), active.EstimatedReward)
+	require.Equal(t, types.EpochId(100), active.ActivationEpoch)
+	require.Equal(t, 0.05, active.Apy)
+	require.Equal(t, uint64(250_000_000), active.ProjectedAnnualReward())
+	require.False(t, active.Pending)
+
+	require.Equal(t, types.StakeStatusPending, pending.Status)
+	require.True(t, pending.Pending)
+	require.Equal(t, types.EpochId(121), pending.ActivationEpoch)
+	require.Zero(t, pending.EstimatedReward)
+
+	require.Equal(t, types.StakeStatusUnstaked, unstaked.Status)
+	require.Zero(t, unstaked.Apy)
+
+	require.Equal(t, "8000000000", portfolio.TotalPrincipal.String())
+	require.Equal(t, "25000000", portfolio.TotalEstimatedReward.String())
+	require.Equal(t, "2000000000", portfolio.TotalPending.String())
+	require.Equal(t, "8025000000", portfolio.TotalValue().String())
+}
@@ -0,0 +1,437 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/thorli9527/sui-wallet-sdk/move_types"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types/sui_system_state"
+)
+
+// The limits of 0x3::validator, the transactions breaking them abort.
+const (
+	MaxValidatorMetadataLength = 256
+	// MaxValidatorCommissionRate is 20%, the commission rate is in basis points.
+	MaxValidatorCommissionRate = 2000
+	MaxValidatorGasPrice       = 100_000
+
+	ValidatorProtocolPubkeyLength    = 96 // BLS12381
+	ValidatorNetworkPubkeyLength     = 32 // Ed25519
+	ValidatorWorkerPubkeyLength      = 32 // Ed25519
+	ValidatorProofOfPossessionLength = 48 // BLS12381 signature
+)
+
+var ErrInvalidValidatorInput = errors.New("invalid validator input")
+
+// ValidatorMetadata is the descriptive metadata of a validator, every field is ASCII.
+type ValidatorMetadata struct {
+	Name        string
+	Description string
+	ImageUrl    string
+	ProjectUrl  string
+}
+
+// Validate checks the fields which are set.
+func (m *ValidatorMetadata) Validate() error {
+	for _, field := range []struct{ name, value string }{
+		{"name", m.Name}, {"description", m.Description}, {"image url", m.ImageUrl}, {"project url", m.ProjectUrl},
+	} {
+		if err := checkValidatorText(field.name, field.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidatorCandidate is the registration of a validator candidate, see
+// BCS_RequestAddValidatorCandidate.
+type ValidatorCandidate struct {
+	ValidatorMetadata
+
+	ProtocolPubkey    []byte
+	NetworkPubkey     []byte
+	WorkerPubkey      []byte
+	ProofOfPossession []byte
+
+	// NetAddress is a TCP multiaddr like "/dns/validator.example.com/tcp/8080/http", the other
+	// addresses are UDP multiaddrs like "/dns/validator.example.com/udp/8084".
+	NetAddress     string
+	P2pAddress     string
+	PrimaryAddress string
+	WorkerAddress  string
+
+	GasPrice       uint64
+	CommissionRate uint64
+}
+
+// Validate checks the candidate would be accepted by 0x3::validator.
+func (c *ValidatorCandidate) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("%w: the name is empty", ErrInvalidValidatorInput)
+	}
+	if err := c.ValidatorMetadata.Validate(); err != nil {
+		return err
+	}
+	for _, key := range []struct {
+		name   string
+		value  []byte
+		length int
+	}{
+		{"protocol public key", c.ProtocolPubkey, ValidatorProtocolPubkeyLength},
+		{"network public key", c.NetworkPubkey, ValidatorNetworkPubkeyLength},
+		{"worker public key", c.WorkerPubkey, ValidatorWorkerPubkeyLength},
+		{"proof of possession", c.ProofOfPossession, ValidatorProofOfPossessionLength},
+	} {
+		if len(key.value) != key.length {
+			return fmt.Errorf("%w: the %s has %d bytes, not %d", ErrInvalidValidatorInput, key.name, len(key.value), key.length)
+		}
+	}
+	for _, address := range []struct{ name, value, transport string }{
+		{"network address", c.NetAddress, "tcp"},
+		{"p2p address", c.P2pAddress, "udp"},
+		{"primary address", c.PrimaryAddress, "udp"},
+		{"worker address", c.WorkerAddress, "udp"},
+	} {
+		if err := checkMultiaddr(address.name, address.value, address.transport); err != nil {
+			return err
+		}
+	}
+	if err := checkValidatorGasPrice(c.GasPrice); err != nil {
+		return err
+	}
+	return checkCommissionRate(c.CommissionRate)
+}
+
+func checkValidatorText(name, value string) error {
+	if len(value) > MaxValidatorMetadataLength {
+		return fmt.Errorf("%w: the %s is longer than %d bytes", ErrInvalidValidatorInput, name, MaxValidatorMetadataLength)
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] > 0x7f {
+			return fmt.Errorf("%w: the %s is not ASCII", ErrInvalidValidatorInput, name)
+		}
+	}
+	return nil
+}
+
+func checkValidatorGasPrice(gasPrice uint64) error {
+	if gasPrice > MaxValidatorGasPrice {
+		return fmt.Errorf("%w: gas price %d is above %d", ErrInvalidValidatorInput, gasPrice, MaxValidatorGasPrice)
+	}
+	return nil
+}
+
+func checkOperationCap(operationCap *sui_types.ObjectRef) error {
+	if operationCap == nil {
+		return fmt.Errorf("%w: no operation cap", ErrInvalidValidatorInput)
+	}
+	return nil
+}
+
+func checkCommissionRate(rate uint64) error {
+	if rate > MaxValidatorCommissionRate {
+		return fmt.Errorf(
+			"%w: commission rate %d is above %d basis points", ErrInvalidValidatorInput, rate, MaxValidatorCommissionRate,
+		)
+	}
+	return nil
+}
+
+// checkMultiaddr checks address is a multiaddr of a host, an ip4, ip6 or dns one, and a port of
+// transport, tcp or udp, like "/ip4/127.0.0.1/udp/8084".
+func checkMultiaddr(name, address, transport string) error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: the %s %q %s", ErrInvalidValidatorInput, name, address, reason)
+	}
+	if len(address) > MaxValidatorMetadataLength {
+		return invalid(fmt.Sprintf("is longer than %d bytes", MaxValidatorMetadataLength))
+	}
+	parts := strings.Split(address, "/")
+	if len(parts) < 5 || parts[0] != "" {
+		return invalid("is not a multiaddr like /dns/<host>/" + transport + "/<port>")
+	}
+	switch host := parts[2]; parts[1] {
+	case "ip4":
+		if ip := net.ParseIP(host); ip == nil || ip.To4() == nil {
+			return invalid("has an invalid ip4 address")
+		}
+	case "ip6":
+		if ip := net.ParseIP(host); ip == nil || ip.To4() != nil {
+			return invalid("has an invalid ip6 address")
+		}
+	case "dns", "dns4", "dns6":
+		if host == "" {
+			return invalid("has an empty host")
+		}
+	default:
+		return invalid("has no ip4, ip6 or dns host")
+	}
+	if parts[3] != transport {
+		return invalid("is not a " + transport + " address")
+	}
+	if _, err := strconv.ParseUint(parts[4], 10, 16); err != nil {
+		return invalid("has an invalid port")
+	}
+	return nil
+}
+
+// bcsSuiSystemCalls builds a transaction of calls to 0x3::sui_system, build adds them to ptb with
+// the argument of the mutable sui system object.
+func bcsSuiSystemCalls(
+	signer suiAddress,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+	build func(ptb *sui_types.ProgrammableTransactionBuilder, system sui_types.Argument) error,
+) ([]byte, error) {
+	ptb := sui_types.NewProgrammableTransactionBuilder()
+	system, err := ptb.Obj(sui_types.SuiSystemMutObj)
+	if err != nil {
+		return nil, err
+	}
+	if err := build(ptb, system); err != nil {
+		return nil, err
+	}
+	tx := sui_types.NewProgrammable(signer, gas, ptb.Finish(), gasBudget, gasPrice)
+	return bcs.Marshal(tx)
+}
+
+// suiSystemCall adds a call of function with the sui system object and the pure values.
+func suiSystemCall(
+	ptb *sui_types.ProgrammableTransactionBuilder,
+	system sui_types.Argument,
+	function move_types.Identifier,
+	extra []sui_types.Argument,
+	values ...any,
+) error {
+	arguments := append([]sui_types.Argument{system}, extra...)
+	for _, value := range values {
+		argument, err := ptb.Pure(value)
+		if err != nil {
+			return err
+		}
+		arguments = append(arguments, argument)
+	}
+	ptb.Command(
+		sui_types.Command{
+			MoveCall: &sui_types.ProgrammableMoveCall{
+				Package:   *sui_types.SuiSystemAddress,
+				Module:    sui_system_state.SuiSystemModuleName,
+				Function:  function,
+				Arguments: arguments,
+			},
+		},
+	)
+	return nil
+}
+
+// bcsSuiSystemCall builds a transaction of a single call of function, see suiSystemCall.
+func bcsSuiSystemCall(
+	signer suiAddress,
+	function move_types.Identifier,
+	operationCap *sui_types.ObjectRef,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+	values ...any,
+) ([]byte, error) {
+	return bcsSuiSystemCalls(
+		signer, gas, gasBudget, gasPrice, func(ptb *sui_types.ProgrammableTransactionBuilder, system sui_types.Argument) error {
+			var extra []sui_types.Argument
+			if operationCap != nil {
+				capArg, err := ptb.Obj(sui_types.ObjectArg{ImmOrOwnedObject: operationCap})
+				if err != nil {
+					return err
+				}
+				extra = append(extra, capArg)
+			}
+			return suiSystemCall(ptb, system, function, extra, values...)
+		},
+	)
+}
+
+// BCS_RequestAddValidatorCandidate registers the signer as a validator candidate, it must then
+// gather the minimum stake and join the committee with BCS_RequestAddValidator.
+func BCS_RequestAddValidatorCandidate(
+	signer suiAddress,
+	candidate *ValidatorCandidate,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	if err := candidate.Validate(); err != nil {
+		return nil, err
+	}
+	return bcsSuiSystemCall(
+		signer, sui_system_state.RequestAddValidatorCandidateFunName, nil, gas, gasBudget, gasPrice,
+		candidate.ProtocolPubkey, candidate.NetworkPubkey, candidate.WorkerPubkey, candidate.ProofOfPossession,
+		[]byte(candidate.Name), []byte(candidate.Description), []byte(candidate.ImageUrl), []byte(candidate.ProjectUrl),
+		[]byte(candidate.NetAddress), []byte(candidate.P2pAddress),
+		[]byte(candidate.PrimaryAddress), []byte(candidate.WorkerAddress),
+		candidate.GasPrice, candidate.CommissionRate,
+	)
+}
+
+// BCS_RequestRemoveValidatorCandidate withdraws the candidacy of the signer.
+func BCS_RequestRemoveValidatorCandidate(
+	signer suiAddress,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	return bcsSuiSystemCall(
+		signer, sui_system_state.RequestRemoveValidatorCandidateFunName, nil, gas, gasBudget, gasPrice,
+	)
+}
+
+// BCS_RequestAddValidator asks for the candidate signer to join the committee at the next epoch.
+func BCS_RequestAddValidator(signer suiAddress, gas []*sui_types.ObjectRef, gasBudget, gasPrice uint64) ([]byte, error) {
+	return bcsSuiSystemCall(signer, sui_system_state.RequestAddValidatorFunName, nil, gas, gasBudget, gasPrice)
+}
+
+// BCS_RequestRemoveValidator asks for the validator signer to leave the committee at the next epoch.
+func BCS_RequestRemoveValidator(signer suiAddress, gas []*sui_types.ObjectRef, gasBudget, gasPrice uint64) ([]byte, error) {
+	return bcsSuiSystemCall(signer, sui_system_state.RequestRemoveValidatorFunName, nil, gas, gasBudget, gasPrice)
+}
+
+// BCS_RequestSetValidatorGasPrice sets the gas price quote of the validator of operationCap, its
+// UnverifiedValidatorOperationCap, for the next epoch. A candidate sets it right away.
+func BCS_RequestSetValidatorGasPrice(
+	signer suiAddress,
+	operationCap *sui_types.ObjectRef,
+	newGasPrice uint64,
+	candidate bool,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	if err := checkOperationCap(operationCap); err != nil {
+		return nil, err
+	}
+	if err := checkValidatorGasPrice(newGasPrice); err != nil {
+		return nil, err
+	}
+	function := sui_system_state.RequestSetGasPriceFunName
+	if candidate {
+		function = sui_system_state.SetCandidateValidatorGasPriceFunName
+	}
+	return bcsSuiSystemCall(signer, function, operationCap, gas, gasBudget, gasPrice, newGasPrice)
+}
+
+// BCS_RequestSetCommissionRate sets the commission rate of the validator signer for the next epoch,
+// in basis points. A candidate sets it right away.
+func BCS_RequestSetCommissionRate(
+	signer suiAddress,
+	rate uint64,
+	candidate bool,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	if err := checkCommissionRate(rate); err != nil {
+		return nil, err
+	}
+	function := sui_system_state.RequestSetCommissionRateFunName
+	if candidate {
+		function = sui_system_state.SetCandidateValidatorCommissionRateFunName
+	}
+	return bcsSuiSystemCall(signer, function, nil, gas, gasBudget, gasPrice, rate)
+}
+
+// BCS_UpdateValidatorMetadata updates the fields of metadata which are set, in a single
+// transaction. The metadata of a validator is updated right away.
+func BCS_UpdateValidatorMetadata(
+	signer suiAddress,
+	metadata ValidatorMetadata,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	if err := metadata.Validate(); err != nil {
+		return nil, err
+	}
+	updates := []struct {
+		function move_types.Identifier
+		value    string
+	}{
+		{sui_system_state.UpdateValidatorNameFunName, metadata.Name},
+		{sui_system_state.UpdateValidatorDescriptionFunName, metadata.Description},
+		{sui_system_state.UpdateValidatorImageUrlFunName, metadata.ImageUrl},
+		{sui_system_state.UpdateValidatorProjectUrlFunName, metadata.ProjectUrl},
+	}
+	return bcsSuiSystemCalls(
+		signer, gas, gasBudget, gasPrice, func(ptb *sui_types.ProgrammableTransactionBuilder, system sui_types.Argument) error {
+			updated := false
+			for _, update := range updates {
+				if update.value == "" {
+					continue
+				}
+				if err := suiSystemCall(ptb, system, update.function, nil, []byte(update.value)); err != nil {
+					return err
+				}
+				updated = true
+			}
+			if !updated {
+				return fmt.Errorf("%w: no metadata to update", ErrInvalidValidatorInput)
+			}
+			return nil
+		},
+	)
+}
+
+// BCS_UpdateValidatorNetworkAddress updates the TCP network address of the validator signer for
+// the next epoch, or of the candidate signer right away.
+func BCS_UpdateValidatorNetworkAddress(
+	signer suiAddress,
+	address string,
+	candidate bool,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	if err := checkMultiaddr("network address", address, "tcp"); err != nil {
+		return nil, err
+	}
+	function := sui_system_state.UpdateValidatorNextEpochNetworkAddressFunName
+	if candidate {
+		function = sui_system_state.UpdateCandidateValidatorNetworkAddressFunName
+	}
+	return bcsSuiSystemCall(signer, function, nil, gas, gasBudget, gasPrice, []byte(address))
+}
+
+// BCS_UpdateValidatorP2pAddress updates the UDP p2p address of the validator signer for the next
+// epoch, or of the candidate signer right away.
+func BCS_UpdateValidatorP2pAddress(
+	signer suiAddress,
+	address string,
+	candidate bool,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	if err := checkMultiaddr("p2p address", address, "udp"); err != nil {
+		return nil, err
+	}
+	function := sui_system_state.UpdateValidatorNextEpochP2pAddressFunName
+	if candidate {
+		function = sui_system_state.UpdateCandidateValidatorP2pAddressFunName
+	}
+	return bcsSuiSystemCall(signer, function, nil, gas, gasBudget, gasPrice, []byte(address))
+}
+
+// BCS_ReportValidator reports reportee as misbehaving with the operation cap of a validator, or
+// withdraws the report when undo is true. A validator reported by a quorum gets no rewards for
+// the epoch.
+func BCS_ReportValidator(
+	signer suiAddress,
+	operationCap *sui_types.ObjectRef,
+	reportee suiAddress,
+	undo bool,
+	gas []*sui_types.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	if err := checkOperationCap(operationCap); err != nil {
+		return nil, err
+	}
+	function := sui_system_state.ReportValidatorFunName
+	if undo {
+		function = sui_system_state.UndoReportValidatorFunName
+	}
+	return bcsSuiSystemCall(signer, function, operationCap, gas, gasBudget, gasPrice, reportee)
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/move_types"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types/sui_system_state"
+)
+
+func testValidatorCandidate() *ValidatorCandidate {
+	return &ValidatorCandidate{
+		ValidatorMetadata: ValidatorMetadata{
+			Name:       "validator",
+			ImageUrl:   "https://validator.example.com/logo.png",
+			ProjectUrl: "https://validator.example.com",
+		},
+		ProtocolPubkey:    bytes.Repeat([]byte{1}, ValidatorProtocolPubkeyLength),
+		NetworkPubkey:     bytes.Repeat([]byte{2}, ValidatorNetworkPubkeyLength),
+		WorkerPubkey:      bytes.Repeat([]byte{3}, ValidatorWorkerPubkeyLength),
+		ProofOfPossession: bytes.Repeat([]byte{4}, ValidatorProofOfPossessionLength),
+		NetAddress:        "/dns/validator.example.com/tcp/8080/http",
+		P2pAddress:        "/dns/validator.example.com/udp/8084",
+		PrimaryAddress:    "/ip4/10.0.0.1/udp/8081",
+		WorkerAddress:     "/ip6/::1/udp/8082",
+		GasPrice:          1000,
+		CommissionRate:    200,
+	}
+}
+
+func TestValidatorCandidate_Validate(t *testing.T) {
+	require.NoError(t, testValidatorCandidate().Validate())
+
+	invalid := []func(c *ValidatorCandidate){
+		func(c *ValidatorCandidate) { c.Name = "" },
+		func(c *ValidatorCandidate) {
+			c.Description = string(bytes.Repeat([]byte{'a'}, MaxValidatorMetadataLength+1))
+		},
+		func(c *ValidatorCandidate) { c.Name = "validateur é" },
+		func(c *ValidatorCandidate) { c.ProtocolPubkey = c.ProtocolPubkey[1:] },
+		func(c *ValidatorCandidate) { c.ProofOfPossession = nil },
+		func(c *ValidatorCandidate) { c.NetAddress = "/dns/validator.example.com/udp/8080" },
+		func(c *ValidatorCandidate) { c.P2pAddress = "validator.example.com:8084" },
+		func(c *ValidatorCandidate) { c.PrimaryAddress = "/ip4/10.0.0/udp/8081" },
+		func(c *ValidatorCandidate) { c.WorkerAddress = "/dns/validator.example.com/udp/70000" },
+		func(c *ValidatorCandidate) { c.GasPrice = MaxValidatorGasPrice + 1 },
+		func(c *ValidatorCandidate) { c.CommissionRate = MaxValidatorCommissionRate + 1 },
+	}
+	for i, update := range invalid {
+		candidate := testValidatorCandidate()
+		update(candidate)
+		require.ErrorIs(t, candidate.Validate(), ErrInvalidValidatorInput, i)
+	}
+}
+
+func TestBCS_ValidatorOperations(t *testing.T) {
+	_, signer := testAccount(t, 1)
+	_, reportee := testAccount(t, 2)
+	operationCap := &sui_types.ObjectRef{ObjectId: sui_types.ObjectID{1}, Version: 1, Digest: make([]byte, 32)}
+	gas := []*sui_types.ObjectRef{{ObjectId: sui_types.ObjectID{0xff}, Version: 1, Digest: make([]byte, 32)}}
+
+	candidate := testValidatorCandidate()
+	txBytes, err := BCS_RequestAddValidatorCandidate(signer, candidate, gas, 1e7, 1000)
+	require.NoError(t, err)
+	pt := decodeProgrammable(t, txBytes)
+	require.Equal(t, []move_types.Identifier{sui_system_state.RequestAddValidatorCandidateFunName}, moveCalls(pt))
+	// the sui system object and 14 pure arguments
+	require.Len(t, pt.Commands[0].MoveCall.Arguments, 15)
+	name, err := bcs.Marshal([]byte(candidate.Name))
+	require.NoError(t, err)
+	nameInput := *pt.Commands[0].MoveCall.Arguments[5].Input
+	require.Equal(t, name, []byte(*pt.Inputs[nameInput].Pure))
+
+	candidate.CommissionRate = 5000
+	_, err = BCS_RequestAddValidatorCandidate(signer, candidate, gas, 1e7, 1000)
+	require.ErrorIs(t, err, ErrInvalidValidatorInput)
+
+	txBytes, err = BCS_UpdateValidatorMetadata(signer, ValidatorMetadata{Name: "new name", ProjectUrl: "https://new.example.com"}, gas, 1e7, 1000)
+	require.NoError(t, err)
+	require.Equal(
+		t, []move_types.Identifier{
+			sui_system_state.UpdateValidatorNameFunName, sui_system_state.UpdateValidatorProjectUrlFunName,
+		}, moveCalls(decodeProgrammable(t, txBytes)),
+	)
+	_, err = BCS_UpdateValidatorMetadata(signer, ValidatorMetadata{}, gas, 1e7, 1000)
+	require.ErrorIs(t, err, ErrInvalidValidatorInput)
+
+	tests := []struct {
+		build    func() ([]byte, error)
+		function move_types.Identifier
+	}{
+		{func() ([]byte, error) { return BCS_RequestAddValidator(signer, gas, 1e7, 1000) }, sui_system_state.RequestAddValidatorFunName},
+		{func() ([]byte, error) { return BCS_RequestRemoveValidator(signer, gas, 1e7, 1000) }, sui_system_state.RequestRemoveValidatorFunName},
+		{
+			func() ([]byte, error) { return BCS_RequestRemoveValidatorCandidate(signer, gas, 1e7, 1000) },
+			sui_system_state.RequestRemoveValidatorCandidateFunName,
+		},
+		{
+			func() ([]byte, error) {
+				return BCS_RequestSetValidatorGasPrice(signer, operationCap, 900, false, gas, 1e7, 1000)
+			},
+			sui_system_state.RequestSetGasPriceFunName,
+		},
+		{
+			func() ([]byte, error) {
+				return BCS_RequestSetValidatorGasPrice(signer, operationCap, 900, true, gas, 1e7, 1000)
+			},
+			sui_system_state.SetCandidateValidatorGasPriceFunName,
+		},
+		{
+			func() ([]byte, error) { return BCS_RequestSetCommissionRate(signer, 1000, false, gas, 1e7, 1000) },
+			sui_system_state.RequestSetCommissionRateFunName,
+		},
+		{
+			func() ([]byte, error) {
+				return BCS_UpdateValidatorNetworkAddress(signer, "/ip4/10.0.0.2/tcp/8080/http", false, gas, 1e7, 1000)
+			},
+			sui_system_state.UpdateValidatorNextEpochNetworkAddressFunName,
+		},
+		{
+			func() ([]byte, error) {
+				return BCS_UpdateValidatorP2pAddress(signer, "/dns/p2p.example.com/udp/8084", true, gas, 1e7, 1000)
+			},
+			sui_system_state.UpdateCandidateValidatorP2pAddressFunName,
+		},
+		{
+			func() ([]byte, error) {
+				return BCS_ReportValidator(signer, operationCap, reportee, false, gas, 1e7, 1000)
+			},
+			sui_system_state.ReportValidatorFunName,
+		},
+		{
+			func() ([]byte, error) {
+				return BCS_ReportValidator(signer, operationCap, reportee, true, gas, 1e7, 1000)
+			},
+			sui_system_state.UndoReportValidatorFunName,
+		},
+	}
+	for _, tt := range tests {
+		txBytes, err := tt.build()
+		require.NoError(t, err, tt.function)
+		require.Equal(t, []move_types.Identifier{tt.function}, moveCalls(decodeProgrammable(t, txBytes)))
+	}
+
+	_, err = BCS_RequestSetValidatorGasPrice(signer, operationCap, MaxValidatorGasPrice+1, false, gas, 1e7, 1000)
+	require.ErrorIs(t, err, ErrInvalidValidatorInput)
+	_, err = BCS_RequestSetValidatorGasPrice(signer, nil, 900, false, gas, 1e7, 1000)
+	require.ErrorIs(t, err, ErrInvalidValidatorInput)
+	_, err = BCS_ReportValidator(signer, nil, reportee, false, gas, 1e7, 1000)
+	require.ErrorIs(t, err, ErrInvalidValidatorInput)
+	_, err = BCS_ReportValidator(signer, nil, reportee, true, gas, 1e7, 1000)
+	require.ErrorIs(t, err, ErrInvalidValidatorInput)
+	_, err = BCS_RequestSetCommissionRate(signer, MaxValidatorCommissionRate+1, true, gas, 1e7, 1000)
+	require.ErrorIs(t, err, ErrInvalidValidatorInput)
+	_, err = BCS_UpdateValidatorNetworkAddress(signer, "/dns/validator.example.com/udp/8080", false, gas, 1e7, 1000)
+	require.ErrorIs(t, err, ErrInvalidValidatorInput)
+}
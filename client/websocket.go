@@ -0,0 +1,610 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	ErrWebsocketClosed       = errors.New("websocket client closed")
+	ErrWebsocketDisconnected = errors.New("websocket connection lost")
+)
+
+// BackpressurePolicy decides what happens when a subscriber does not drain its channel fast enough.
+type BackpressurePolicy int
+
+const (
+	// BackpressureBlock waits for the subscriber, this stalls every subscription of the client.
+	BackpressureBlock BackpressurePolicy = iota
+	// BackpressureDropNewest discards the incoming notification when the buffer is full.
+	BackpressureDropNewest
+	// BackpressureDropOldest discards the oldest buffered notification to make room.
+	BackpressureDropOldest
+)
+
+type websocketConfig struct {
+	dialer       *websocket.Dialer
+	header       http.Header
+	bufferSize   int
+	backpressure BackpressurePolicy
+	pingInterval time.Duration
+
+	minReconnectDelay    time.Duration
+	maxReconnectDelay    time.Duration
+	maxReconnectAttempts int
+}
+
+type WebsocketOption func(*websocketConfig)
+
+func WithWebsocketDialer(dialer *websocket.Dialer) WebsocketOption {
+	return func(c *websocketConfig) {
+		c.dialer = dialer
+	}
+}
+
+func WithWebsocketHeader(header http.Header) WebsocketOption {
+	return func(c *websocketConfig) {
+		c.header = header
+	}
+}
+
+// WithSubscriptionBuffer sets the channel size of every subscription and the policy applied when it is full.
+func WithSubscriptionBuffer(size int, policy BackpressurePolicy) WebsocketOption {
+	return func(c *websocketConfig) {
+		c.bufferSize = size
+		c.backpressure = policy
+	}
+}
+
+// WithReconnectBackoff sets the exponential reconnect delay, maxAttempts <= 0 retries forever.
+func WithReconnectBackoff(minDelay, maxDelay time.Duration, maxAttempts int) WebsocketOption {
+	return func(c *websocketConfig) {
+		c.minReconnectDelay = minDelay
+		c.maxReconnectDelay = maxDelay
+		c.maxReconnectAttempts = maxAttempts
+	}
+}
+
+// WithPingInterval sets the keepalive ping interval, the connection is considered lost
+// when nothing is read for two intervals. Zero disables keepalive.
+func WithPingInterval(interval time.Duration) WebsocketOption {
+	return func(c *websocketConfig) {
+		c.pingInterval = interval
+	}
+}
+
+type wsPending struct {
+	resp     chan *jsonrpcMessage
+	onResult func(*jsonrpcMessage)
+}
+
+// wsSubscription is the untyped state of a subscription that survives reconnects.
+type wsSubscription struct {
+	id          uint64
+	method      Method
+	unsubscribe Method
+	params      []interface{}
+	serverID    string
+	deliver     func(json.RawMessage)
+	close       func(error)
+}
+
+type wsNotification struct {
+	Subscription json.RawMessage `json:"subscription"`
+	Result       json.RawMessage `json:"result"`
+}
+
+// WebsocketClient speaks JSON-RPC over a websocket, it is needed for the suix_subscribe* methods.
+// The connection is re-established with backoff when it drops, and active subscriptions are
+// subscribed again. Notifications emitted while disconnected are lost.
+type WebsocketClient struct {
+	idCounter  uint32
+	subCounter uint64
+
+	wsUrl  string
+	config websocketConfig
+
+	lock       sync.Mutex
+	conn       *websocket.Conn
+	connLost   chan struct{}
+	pending    map[string]*wsPending
+	subs       map[uint64]*wsSubscription
+	byServerID map[string]*wsSubscription
+
+	writeLock sync.Mutex
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func DialWebsocket(ctx context.Context, wsUrl string, options ...WebsocketOption) (*WebsocketClient, error) {
+	config := websocketConfig{
+		dialer:            websocket.DefaultDialer,
+		bufferSize:        128,
+		backpressure:      BackpressureBlock,
+		pingInterval:      30 * time.Second,
+		minReconnectDelay: 500 * time.Millisecond,
+		maxReconnectDelay: 30 * time.Second,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+	c := &WebsocketClient{
+		wsUrl:      strings.TrimRight(wsUrl, "/"),
+		config:     config,
+		pending:    make(map[string]*wsPending),
+		subs:       make(map[uint64]*wsSubscription),
+		byServerID: make(map[string]*wsSubscription),
+		closed:     make(chan struct{}),
+	}
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	go c.run(conn)
+	return c, nil
+}
+
+// Close closes the connection and every subscription, their Err channel receives ErrWebsocketClosed.
+func (c *WebsocketClient) Close() error {
+	var err error
+	c.closeOnce.Do(
+		func() {
+			close(c.closed)
+			c.lock.Lock()
+			conn := c.conn
+			c.lock.Unlock()
+			if conn != nil {
+				err = conn.Close()
+			}
+		},
+	)
+	return err
+}
+
+// CallContext performs a JSON-RPC call over the websocket connection.
+func (c *WebsocketClient) CallContext(ctx context.Context, result interface{}, method Method, args ...interface{}) error {
+	return c.call(ctx, result, nil, method, args...)
+}
+
+func (c *WebsocketClient) call(
+	ctx context.Context,
+	result interface{},
+	onResult func(*jsonrpcMessage),
+	method Method,
+	args ...interface{},
+) error {
+	id := atomic.AddUint32(&c.idCounter, 1)
+	msg, err := newJsonrpcMessage(strconv.AppendUint(nil, uint64(id), 10), method.String(), args...)
+	if err != nil {
+		return err
+	}
+	pending := &wsPending{resp: make(chan *jsonrpcMessage, 1), onResult: onResult}
+
+	c.lock.Lock()
+	conn, lost := c.conn, c.connLost
+	if conn == nil {
+		c.lock.Unlock()
+		return c.disconnectedErr()
+	}
+	c.pending[string(msg.ID)] = pending
+	c.lock.Unlock()
+	defer func() {
+		c.lock.Lock()
+		delete(c.pending, string(msg.ID))
+		c.lock.Unlock()
+	}()
+
+	c.writeLock.Lock()
+	err = conn.WriteJSON(msg)
+	c.writeLock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-pending.resp:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if len(resp.Result) == 0 {
+			return ErrNoResult
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	case <-lost:
+		return c.disconnectedErr()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *WebsocketClient) disconnectedErr() error {
+	select {
+	case <-c.closed:
+		return ErrWebsocketClosed
+	default:
+		return ErrWebsocketDisconnected
+	}
+}
+
+func (c *WebsocketClient) dial(ctx context.Context) (*websocket.Conn, error) {
+	conn, resp, err := c.config.dialer.DialContext(ctx, c.wsUrl, c.config.header)
+	if err != nil {
+		if resp != nil {
+			return nil, HTTPError{Status: resp.Status, StatusCode: resp.StatusCode}
+		}
+		return nil, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	select {
+	case <-c.closed:
+		// Close ran while dialing, it could not close this connection
+		_ = conn.Close()
+		return nil, ErrWebsocketClosed
+	default:
+	}
+	c.conn = conn
+	c.connLost = make(chan struct{})
+	return conn, nil
+}
+
+// run owns the connection: it reads until the connection breaks, then reconnects and resubscribes.
+func (c *WebsocketClient) run(conn *websocket.Conn) {
+	for {
+		c.readLoop(conn)
+		c.connectionLost()
+
+		select {
+		case <-c.closed:
+			c.shutdown(ErrWebsocketClosed)
+			return
+		default:
+		}
+		var err error
+		conn, err = c.reconnect()
+		if err != nil {
+			c.shutdown(err)
+			return
+		}
+		go c.resubscribe()
+	}
+}
+
+func (c *WebsocketClient) readLoop(conn *websocket.Conn) {
+	c.lock.Lock()
+	lost := c.connLost
+	c.lock.Unlock()
+	if interval := c.config.pingInterval; interval > 0 {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * interval))
+		conn.SetPongHandler(
+			func(string) error {
+				return conn.SetReadDeadline(time.Now().Add(2 * interval))
+			},
+		)
+		go c.keepalive(conn, lost, interval)
+	}
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			_ = conn.Close()
+			return
+		}
+		if interval := c.config.pingInterval; interval > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(2 * interval))
+		}
+		var msg jsonrpcMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue // not a json-rpc message
+		}
+		if msg.Method != "" {
+			c.handleNotification(&msg)
+		} else {
+			c.handleResponse(&msg)
+		}
+	}
+}
+
+func (c *WebsocketClient) keepalive(conn *websocket.Conn, lost chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(interval)); err != nil {
+				_ = conn.Close()
+				return
+			}
+		case <-lost:
+			return
+		}
+	}
+}
+
+func (c *WebsocketClient) handleResponse(msg *jsonrpcMessage) {
+	c.lock.Lock()
+	pending, ok := c.pending[string(msg.ID)]
+	c.lock.Unlock()
+	if !ok {
+		return
+	}
+	if pending.onResult != nil && msg.Error == nil {
+		pending.onResult(msg)
+	}
+	pending.resp <- msg
+}
+
+func (c *WebsocketClient) handleNotification(msg *jsonrpcMessage) {
+	var notification wsNotification
+	if err := json.Unmarshal(msg.Params, &notification); err != nil {
+		return
+	}
+	c.lock.Lock()
+	sub, ok := c.byServerID[string(notification.Subscription)]
+	c.lock.Unlock()
+	if !ok {
+		return
+	}
+	sub.deliver(notification.Result)
+}
+
+func (c *WebsocketClient) connectionLost() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.conn = nil
+	close(c.connLost)
+	c.byServerID = make(map[string]*wsSubscription)
+}
+
+func (c *WebsocketClient) reconnect() (*websocket.Conn, error) {
+	delay := c.config.minReconnectDelay
+	for attempt := 1; ; attempt++ {
+		timer := time.NewTimer(delay)
+		select {
+		case <-c.closed:
+			timer.Stop()
+			return nil, ErrWebsocketClosed
+		case <-timer.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), c.config.dialer.HandshakeTimeout+10*time.Second)
+		conn, err := c.dial(ctx)
+		cancel()
+		if err == nil || errors.Is(err, ErrWebsocketClosed) {
+			return conn, err
+		}
+		if c.config.maxReconnectAttempts > 0 && attempt >= c.config.maxReconnectAttempts {
+			return nil, err
+		}
+		delay *= 2
+		if delay > c.config.maxReconnectDelay {
+			delay = c.config.maxReconnectDelay
+		}
+	}
+}
+
+func (c *WebsocketClient) resubscribe() {
+	c.lock.Lock()
+	subs := make([]*wsSubscription, 0, len(c.subs))
+	for _, sub := range c.subs {
+		subs = append(subs, sub)
+	}
+	c.lock.Unlock()
+	for _, sub := range subs {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err := c.subscribe(ctx, sub)
+		cancel()
+		if errors.Is(err, ErrWebsocketDisconnected) || errors.Is(err, ErrWebsocketClosed) {
+			return // the next connection resubscribes again
+		}
+		if err != nil {
+			c.removeSubscription(sub.id)
+			sub.close(err)
+		}
+	}
+}
+
+func (c *WebsocketClient) shutdown(err error) {
+	c.lock.Lock()
+	subs := c.subs
+	c.subs = make(map[uint64]*wsSubscription)
+	c.lock.Unlock()
+	for _, sub := range subs {
+		sub.close(err)
+	}
+}
+
+// subscribe sends the subscribe request of sub, the server id is bound before any later message is read.
+func (c *WebsocketClient) subscribe(ctx context.Context, sub *wsSubscription) error {
+	return c.call(
+		ctx, nil, func(msg *jsonrpcMessage) {
+			c.lock.Lock()
+			defer c.lock.Unlock()
+			if _, ok := c.subs[sub.id]; !ok {
+				return
+			}
+			sub.serverID = string(msg.Result)
+			c.byServerID[sub.serverID] = sub
+		}, sub.method, sub.params...,
+	)
+}
+
+func (c *WebsocketClient) removeSubscription(id uint64) (*wsSubscription, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	sub, ok := c.subs[id]
+	if !ok {
+		return nil, false
+	}
+	delete(c.subs, id)
+	if c.byServerID[sub.serverID] == sub {
+		delete(c.byServerID, sub.serverID)
+	}
+	return sub, true
+}
+
+// Subscription delivers the notifications of one subscription in order.
+type Subscription[T any] struct {
+	client  *WebsocketClient
+	id      uint64
+	policy  BackpressurePolicy
+	dropped uint64
+
+	ch        chan T
+	errCh     chan error
+	quit      chan struct{}
+	sendLock  sync.Mutex
+	closeOnce sync.Once
+}
+
+// Chan returns the notification channel, it is closed when the subscription ends.
+func (s *Subscription[T]) Chan() <-chan T {
+	return s.ch
+}
+
+// Err receives decoding errors and the reason the subscription ended, it is closed with Chan.
+func (s *Subscription[T]) Err() <-chan error {
+	return s.errCh
+}
+
+// Dropped returns how many notifications were discarded by the backpressure policy.
+func (s *Subscription[T]) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Unsubscribe ends the subscription, the server is told to stop if the connection is up.
+func (s *Subscription[T]) Unsubscribe(ctx context.Context) error {
+	sub, ok := s.client.removeSubscription(s.id)
+	if !ok {
+		return nil
+	}
+	s.close(nil)
+	if sub.serverID == "" {
+		return nil
+	}
+	err := s.client.CallContext(ctx, nil, sub.unsubscribe, json.RawMessage(sub.serverID))
+	if errors.Is(err, ErrWebsocketDisconnected) || errors.Is(err, ErrWebsocketClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Subscription[T]) deliver(value T) {
+	select {
+	case <-s.quit:
+		return
+	default:
+	}
+	s.sendLock.Lock()
+	defer s.sendLock.Unlock()
+	switch s.policy {
+	case BackpressureDropNewest:
+		select {
+		case s.ch <- value:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	case BackpressureDropOldest:
+		for {
+			select {
+			case s.ch <- value:
+				return
+			default:
+			}
+			select {
+			case <-s.ch:
+				atomic.AddUint64(&s.dropped, 1)
+			default:
+			}
+		}
+	default:
+		select {
+		case s.ch <- value:
+		case <-s.quit:
+		}
+	}
+}
+
+func (s *Subscription[T]) report(err error) {
+	select {
+	case s.errCh <- err:
+	default:
+	}
+}
+
+func (s *Subscription[T]) close(err error) {
+	s.closeOnce.Do(
+		func() {
+			close(s.quit)
+			s.sendLock.Lock()
+			defer s.sendLock.Unlock()
+			if err != nil {
+				// make room so the final error is never lost
+				select {
+				case <-s.errCh:
+				default:
+				}
+				s.errCh <- err
+			}
+			close(s.ch)
+			close(s.errCh)
+		},
+	)
+}
+
+// subscribeWith registers a subscription whose notifications are decoded by decode.
+func subscribeWith[T any](
+	ctx context.Context,
+	c *WebsocketClient,
+	method, unsubscribe Method,
+	decode func(json.RawMessage) (T, error),
+	params ...interface{},
+) (*Subscription[T], error) {
+	s := &Subscription[T]{
+		client: c,
+		id:     atomic.AddUint64(&c.subCounter, 1),
+		policy: c.config.backpressure,
+		ch:     make(chan T, c.config.bufferSize),
+		errCh:  make(chan error, 1),
+		quit:   make(chan struct{}),
+	}
+	sub := &wsSubscription{
+		id:          s.id,
+		method:      method,
+		unsubscribe: unsubscribe,
+		params:      params,
+		deliver: func(raw json.RawMessage) {
+			value, err := decode(raw)
+			if err != nil {
+				s.report(err)
+				return
+			}
+			s.deliver(value)
+		},
+		close: s.close,
+	}
+	c.lock.Lock()
+	c.subs[sub.id] = sub
+	c.lock.Unlock()
+	if err := c.subscribe(ctx, sub); err != nil {
+		c.removeSubscription(sub.id)
+		return nil, err
+	}
+	return s, nil
+}
+
+func decodeJson[T any](raw json.RawMessage) (T, error) {
+	var value T
+	err := json.Unmarshal(raw, &value)
+	return value, err
+}
@@ -0,0 +1,338 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+// wsStandIn is a local websocket node which answers the subscribe methods and lets the test push notifications.
+type wsStandIn struct {
+	t      *testing.T
+	server *httptest.Server
+	conns  chan *wsStandInConn
+
+	lock    sync.Mutex
+	nextSub int
+}
+
+type wsStandInConn struct {
+	conn      *websocket.Conn
+	writeLock sync.Mutex
+	requests  chan jsonrpcMessage
+	subs      chan int
+	// done is closed once the connection is closed
+	done chan struct{}
+}
+
+func newWsStandIn(t *testing.T) *wsStandIn {
+	s := &wsStandIn{t: t, conns: make(chan *wsStandInConn, 8)}
+	upgrader := websocket.Upgrader{}
+	s.server = httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				conn, err := upgrader.Upgrade(w, r, nil)
+				if err != nil {
+					return
+				}
+				sc := &wsStandInConn{
+					conn:     conn,
+					requests: make(chan jsonrpcMessage, 16),
+					subs:     make(chan int, 16),
+					done:     make(chan struct{}),
+				}
+				s.conns <- sc
+				s.serve(sc)
+			},
+		),
+	)
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *wsStandIn) url() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+func (s *wsStandIn) serve(sc *wsStandInConn) {
+	defer close(sc.done)
+	defer sc.conn.Close()
+	for {
+		var msg jsonrpcMessage
+		if err := sc.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		var result interface{}
+		switch {
+		case strings.HasPrefix(msg.Method, "suix_subscribe"):
+			s.lock.Lock()
+			s.nextSub++
+			subID := s.nextSub
+			s.lock.Unlock()
+			result = subID
+		case strings.HasPrefix(msg.Method, "suix_unsubscribe"):
+			result = true
+		}
+		sc.requests <- msg
+		data, _ := json.Marshal(result)
+		sc.write(jsonrpcMessage{Version: vsn, ID: msg.ID, Result: data})
+		if subID, ok := result.(int); ok {
+			sc.subs <- subID
+		}
+	}
+}
+
+func (sc *wsStandInConn) write(msg jsonrpcMessage) {
+	sc.writeLock.Lock()
+	defer sc.writeLock.Unlock()
+	_ = sc.conn.WriteJSON(msg)
+}
+
+func (sc *wsStandInConn) notify(method string, subID int, result string) {
+	params := fmt.Sprintf(`{"subscription":%d,"result":%s}`, subID, result)
+	sc.write(jsonrpcMessage{Version: vsn, Method: method, Params: json.RawMessage(params)})
+}
+
+func (s *wsStandIn) nextConn() *wsStandInConn {
+	select {
+	case sc := <-s.conns:
+		return sc
+	case <-time.After(5 * time.Second):
+		s.t.Fatal("no websocket connection")
+		return nil
+	}
+}
+
+func (sc *wsStandInConn) nextSub(t *testing.T) int {
+	select {
+	case id := <-sc.subs:
+		return id
+	case <-time.After(5 * time.Second):
+		t.Fatal("no subscription")
+		return 0
+	}
+}
+
+func (sc *wsStandInConn) nextRequest(t *testing.T) jsonrpcMessage {
+	select {
+	case msg := <-sc.requests:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no request")
+		return jsonrpcMessage{}
+	}
+}
+
+func eventJson(seq int) string {
+	return fmt.Sprintf(
+		`{"id":{"txDigest":"HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn","eventSeq":"%d"},`+
+			`"packageId":"0x2","transactionModule":"m","sender":"0x1","type":"0x2::m::E","bcs":""}`, seq,
+	)
+}
+
+func receiveEvent(t *testing.T, sub *Subscription[types.SuiEvent]) types.SuiEvent {
+	select {
+	case event, ok := <-sub.Chan():
+		require.True(t, ok, "subscription closed")
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+		return types.SuiEvent{}
+	}
+}
+
+func TestWebsocketClient_SubscribeEvent(t *testing.T) {
+	standIn := newWsStandIn(t)
+	cli, err := DialWebsocket(context.Background(), standIn.url())
+	require.NoError(t, err)
+	defer cli.Close()
+	sc := standIn.nextConn()
+
+	sender := Address
+	sub, err := cli.SubscribeEvent(context.Background(), types.EventFilter{Sender: sender})
+	require.NoError(t, err)
+	req := sc.nextRequest(t)
+	require.Equal(t, "suix_subscribeEvent", req.Method)
+	require.Contains(t, string(req.Params), sender.String())
+	subID := sc.nextSub(t)
+
+	for i := 0; i < 3; i++ {
+		sc.notify("suix_subscribeEvent", subID, eventJson(i))
+	}
+	for i := 0; i < 3; i++ {
+		require.Equal(t, uint64(i), receiveEvent(t, sub).Id.EventSeq.Uint64())
+	}
+
+	require.NoError(t, sub.Unsubscribe(context.Background()))
+	req = sc.nextRequest(t)
+	require.Equal(t, "suix_unsubscribeEvent", req.Method)
+	require.Equal(t, fmt.Sprintf("[%d]", subID), string(req.Params))
+	_, ok := <-sub.Chan()
+	require.False(t, ok)
+}
+
+func TestWebsocketClient_SubscribeTransaction(t *testing.T) {
+	standIn := newWsStandIn(t)
+	cli, err := DialWebsocket(context.Background(), standIn.url())
+	require.NoError(t, err)
+	defer cli.Close()
+	sc := standIn.nextConn()
+
+	sub, err := cli.SubscribeTransaction(context.Background(), types.TransactionFilter{FromAddress: Address})
+	require.NoError(t, err)
+	subID := sc.nextSub(t)
+
+	sc.notify(
+		"suix_subscribeTransaction", subID,
+		`{"messageVersion":"v1","status":{"status":"success"},"executedEpoch":"7",`+
+			`"gasUsed":{"computationCost":"1","storageCost":"2","storageRebate":"1","nonRefundableStorageFee":"0"},`+
+			`"transactionDigest":"HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn",`+
+			`"gasObject":{"owner":"Immutable","reference":{"objectId":"0x5","version":1,"digest":"HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn"}}}`,
+	)
+	select {
+	case effects := <-sub.Chan():
+		require.True(t, effects.IsSuccess())
+		require.Equal(t, uint64(7), effects.V1.ExecutedEpoch.Uint64())
+		require.Equal(t, int64(2), effects.GasFee())
+	case <-time.After(5 * time.Second):
+		t.Fatal("no effects")
+	}
+}
+
+func TestWebsocketClient_Reconnect(t *testing.T) {
+	standIn := newWsStandIn(t)
+	cli, err := DialWebsocket(
+		context.Background(), standIn.url(),
+		WithReconnectBackoff(10*time.Millisecond, 50*time.Millisecond, 0),
+	)
+	require.NoError(t, err)
+	defer cli.Close()
+	sc := standIn.nextConn()
+
+	sub, err := cli.SubscribeEvent(context.Background(), types.EventFilter{})
+	require.NoError(t, err)
+	subID := sc.nextSub(t)
+	sc.notify("suix_subscribeEvent", subID, eventJson(1))
+	require.Equal(t, uint64(1), receiveEvent(t, sub).Id.EventSeq.Uint64())
+
+	// drop the connection, the client should come back and subscribe again
+	_ = sc.conn.Close()
+	sc2 := standIn.nextConn()
+	req := sc2.nextRequest(t)
+	require.Equal(t, "suix_subscribeEvent", req.Method)
+	newSubID := sc2.nextSub(t)
+	require.NotEqual(t, subID, newSubID)
+
+	sc2.notify("suix_subscribeEvent", subID, eventJson(99)) // stale id is ignored
+	sc2.notify("suix_subscribeEvent", newSubID, eventJson(2))
+	require.Equal(t, uint64(2), receiveEvent(t, sub).Id.EventSeq.Uint64())
+}
+
+func TestWebsocketClient_Backpressure(t *testing.T) {
+	standIn := newWsStandIn(t)
+	cli, err := DialWebsocket(
+		context.Background(), standIn.url(),
+		WithSubscriptionBuffer(2, BackpressureDropOldest),
+	)
+	require.NoError(t, err)
+	defer cli.Close()
+	sc := standIn.nextConn()
+
+	sub, err := cli.SubscribeEvent(context.Background(), types.EventFilter{})
+	require.NoError(t, err)
+	subID := sc.nextSub(t)
+	for i := 0; i < 5; i++ {
+		sc.notify("suix_subscribeEvent", subID, eventJson(i))
+	}
+	require.Eventually(t, func() bool { return sub.Dropped() == 3 }, 5*time.Second, 5*time.Millisecond)
+	require.Equal(t, uint64(3), receiveEvent(t, sub).Id.EventSeq.Uint64())
+	require.Equal(t, uint64(4), receiveEvent(t, sub).Id.EventSeq.Uint64())
+}
+
+func TestWebsocketClient_Close(t *testing.T) {
+	standIn := newWsStandIn(t)
+	cli, err := DialWebsocket(context.Background(), standIn.url())
+	require.NoError(t, err)
+	sc := standIn.nextConn()
+
+	sub, err := cli.SubscribeEvent(context.Background(), types.EventFilter{})
+	require.NoError(t, err)
+	sc.nextSub(t)
+
+	require.NoError(t, cli.Close())
+	select {
+	case err := <-sub.Err():
+		require.ErrorIs(t, err, ErrWebsocketClosed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not closed")
+	}
+	_, ok := <-sub.Chan()
+	require.False(t, ok)
+
+	_, err = cli.SubscribeEvent(context.Background(), types.EventFilter{})
+	require.ErrorIs(t, err, ErrWebsocketClosed)
+}
+
+func TestWebsocketClient_CloseWhileReconnecting(t *testing.T) {
+	standIn := newWsStandIn(t)
+	// the dials after the first one wait for release
+	dialing, release := make(chan struct{}, 1), make(chan struct{})
+	var dials int
+	var dialsLock sync.Mutex
+	dialer := &websocket.Dialer{
+		NetDialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dialsLock.Lock()
+			dials++
+			first := dials == 1
+			dialsLock.Unlock()
+			if !first {
+				dialing <- struct{}{}
+				<-release
+			}
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}
+	cli, err := DialWebsocket(
+		context.Background(), standIn.url(),
+		WithWebsocketDialer(dialer), WithReconnectBackoff(time.Millisecond, time.Millisecond, 0),
+	)
+	require.NoError(t, err)
+	sc := standIn.nextConn()
+	sub, err := cli.SubscribeEvent(context.Background(), types.EventFilter{})
+	require.NoError(t, err)
+	sc.nextSub(t)
+
+	_ = sc.conn.Close()
+	select {
+	case <-dialing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reconnect")
+	}
+	require.NoError(t, cli.Close())
+	close(release)
+
+	// the connection dialed after Close is closed, and so is the subscription
+	select {
+	case err := <-sub.Err():
+		require.ErrorIs(t, err, ErrWebsocketClosed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not closed")
+	}
+	sc2 := standIn.nextConn()
+	select {
+	case <-sc2.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection dialed after Close is open")
+	}
+}
@@ -0,0 +1,83 @@
+// Command movegen generates Go bindings for a Move package.
+//
+//	movegen -rpc https://fullnode.mainnet.sui.io -package 0x... -go-package mypkg -out mypkg/bindings.go
+//	movegen -modules modules.json -go-package mypkg -out mypkg/bindings.go
+//
+// With -save the normalized modules fetched through -rpc are also written to a json file,
+// which -modules reads later without a node.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/thorli9527/sui-wallet-sdk/client"
+	"github.com/thorli9527/sui-wallet-sdk/movegen"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+func main() {
+	rpcUrl := flag.String("rpc", "", "url of the sui json rpc node")
+	packageHex := flag.String("package", "", "id of the move package")
+	modulesPath := flag.String("modules", "", "json file with the normalized modules, instead of -rpc")
+	savePath := flag.String("save", "", "write the fetched normalized modules to this json file")
+	goPackage := flag.String("go-package", "", "name of the generated go package")
+	outPath := flag.String("out", "", "output file, stdout if empty")
+	flag.Parse()
+
+	if err := run(*rpcUrl, *packageHex, *modulesPath, *savePath, *goPackage, *outPath); err != nil {
+		fmt.Fprintln(os.Stderr, "movegen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(rpcUrl, packageHex, modulesPath, savePath, goPackage, outPath string) error {
+	config := movegen.Config{GoPackage: goPackage}
+	if packageHex != "" {
+		packageId, err := sui_types.NewObjectIdFromHex(packageHex)
+		if err != nil {
+			return err
+		}
+		config.PackageId = packageId
+	}
+
+	var modules movegen.Modules
+	var err error
+	switch {
+	case modulesPath != "":
+		modules, err = movegen.LoadModules(modulesPath)
+	case rpcUrl != "" && config.PackageId != nil:
+		modules, err = fetchModules(rpcUrl, *config.PackageId)
+		if err == nil && savePath != "" {
+			err = movegen.SaveModules(savePath, modules)
+		}
+	default:
+		return fmt.Errorf("either -modules or both -rpc and -package are required")
+	}
+	if err != nil {
+		return err
+	}
+
+	source, err := movegen.Generate(config, modules)
+	if err != nil {
+		return err
+	}
+	if outPath == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	return os.WriteFile(outPath, source, 0o644)
+}
+
+func fetchModules(rpcUrl string, packageId sui_types.ObjectID) (movegen.Modules, error) {
+	cli, err := client.Dial(rpcUrl)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return cli.GetNormalizedMoveModulesByPackage(ctx, packageId)
+}
@@ -5,9 +5,11 @@ go 1.18
 require (
 	github.com/btcsuite/btcutil v1.0.2
 	github.com/fardream/go-bcs v0.2.1
+	github.com/gorilla/websocket v1.5.0
 	github.com/mitchellh/hashstructure/v2 v2.0.2
 	github.com/shopspring/decimal v1.3.1
 	github.com/stretchr/testify v1.8.0
+	github.com/thorli9527/go-aptos v0.0.0-20240412045356-766b4ea7ca7c
 	github.com/tyler-smith/go-bip39 v1.1.0
 	golang.org/x/crypto v0.17.0
 )
@@ -15,7 +17,6 @@ require (
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/thorli9527/go-aptos v0.0.0-20240412045356-766b4ea7ca7c // indirect
 	golang.org/x/sys v0.15.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
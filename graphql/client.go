@@ -0,0 +1,216 @@
+// Package graphql is a client of the Sui GraphQL RPC service, its typed queries return the models
+// of the types package where the GraphQL schema matches them.
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/thorli9527/sui-wallet-sdk/client"
+)
+
+const (
+	MainnetUrl = "https://sui-mainnet.mystenlabs.com/graphql"
+	TestnetUrl = "https://sui-testnet.mystenlabs.com/graphql"
+	DevnetUrl  = "https://sui-devnet.mystenlabs.com/graphql"
+)
+
+var ErrNotFound = errors.New("graphql query found nothing")
+
+// Error is an error of a GraphQL response.
+type Error struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
+
+// Errors are all the errors of a GraphQL response, errors.As finds each Error.
+type Errors []Error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e Errors) Unwrap() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e[0]
+}
+
+type Client struct {
+	url        string
+	httpClient *http.Client
+	header     http.Header
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithHeader sets a header on every request, like the API key of a provider.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Set(key, value)
+	}
+}
+
+func Dial(url string, options ...Option) (*Client, error) {
+	c := &Client{
+		url:        url,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		header:     http.Header{},
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c, nil
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors Errors          `json:"errors,omitempty"`
+}
+
+// Query runs a GraphQL query or mutation and unmarshals its data into result. A response with
+// errors returns them as Errors, even along with data.
+func (c *Client) Query(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
+	return c.do(ctx, request{Query: query, Variables: variables}, result)
+}
+
+func (c *Client) do(ctx context.Context, r request, result interface{}) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = c.header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return client.HTTPError{
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       respBody,
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	var graphqlResp response
+	if err := json.Unmarshal(respBody, &graphqlResp); err != nil {
+		return err
+	}
+	if len(graphqlResp.Errors) > 0 {
+		return graphqlResp.Errors
+	}
+	if result == nil || len(graphqlResp.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(graphqlResp.Data, result)
+}
+
+// PageInfo is the cursor of a GraphQL connection.
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor"`
+}
+
+// Page is a page of a GraphQL connection, pass its EndCursor as the cursor of the next page.
+type Page[T any] struct {
+	Nodes    []T
+	PageInfo PageInfo
+}
+
+// PageFetcher fetches the page after cursor, the first page for a nil cursor.
+type PageFetcher[T any] func(ctx context.Context, cursor *string) (*Page[T], error)
+
+// CollectPages fetches the pages from cursor until the last one, or until maxItems nodes are
+// collected if maxItems > 0.
+func CollectPages[T any](ctx context.Context, cursor *string, maxItems int, fetch PageFetcher[T]) ([]T, error) {
+	var nodes []T
+	for {
+		page, err := fetch(ctx, cursor)
+		if err != nil {
+			return nodes, err
+		}
+		nodes = append(nodes, page.Nodes...)
+		if maxItems > 0 && len(nodes) >= maxItems {
+			return nodes[:maxItems], nil
+		}
+		if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == nil {
+			return nodes, nil
+		}
+		cursor = page.PageInfo.EndCursor
+	}
+}
+
+type connection[T any] struct {
+	PageInfo PageInfo `json:"pageInfo"`
+	Nodes    []T      `json:"nodes"`
+}
+
+// page converts the raw nodes of a connection.
+func page[R any, T any](conn *connection[R], convert func(*R) (T, error)) (*Page[T], error) {
+	if conn == nil {
+		return &Page[T]{}, nil
+	}
+	result := &Page[T]{Nodes: make([]T, len(conn.Nodes)), PageInfo: conn.PageInfo}
+	for i := range conn.Nodes {
+		node, err := convert(&conn.Nodes[i])
+		if err != nil {
+			return nil, err
+		}
+		result.Nodes[i] = node
+	}
+	return result, nil
+}
+
+// pageVariables adds the cursor pagination variables, first is left to the service default if 0.
+func pageVariables(variables map[string]interface{}, cursor *string, first int) map[string]interface{} {
+	if variables == nil {
+		variables = map[string]interface{}{}
+	}
+	if cursor != nil {
+		variables["after"] = *cursor
+	}
+	if first > 0 {
+		variables["first"] = first
+	}
+	return variables
+}
@@ -0,0 +1,322 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/client"
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+// graphqlStandIn serves the recorded response testdata/<operationName>.json, or the response of
+// the override of the operation.
+type graphqlStandIn struct {
+	server    *httptest.Server
+	lock      sync.Mutex
+	variables map[string]map[string]json.RawMessage
+	overrides map[string]func(variables map[string]json.RawMessage) string
+}
+
+func newGraphqlStandIn(t *testing.T) *graphqlStandIn {
+	s := &graphqlStandIn{
+		variables: map[string]map[string]json.RawMessage{},
+		overrides: map[string]func(map[string]json.RawMessage) string{},
+	}
+	s.server = httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Query         string                     `json:"query"`
+					OperationName string                     `json:"operationName"`
+					Variables     map[string]json.RawMessage `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				s.lock.Lock()
+				s.variables[req.OperationName] = req.Variables
+				override := s.overrides[req.OperationName]
+				s.lock.Unlock()
+
+				name := req.OperationName + ".json"
+				if override != nil {
+					name = override(req.Variables)
+				}
+				body, err := os.ReadFile(filepath.Join("testdata", name))
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write(body)
+			},
+		),
+	)
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *graphqlStandIn) override(operation string, respond func(variables map[string]json.RawMessage) string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.overrides[operation] = respond
+}
+
+func (s *graphqlStandIn) lastVariables(operation string) map[string]json.RawMessage {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.variables[operation]
+}
+
+func (s *graphqlStandIn) client(t *testing.T) *Client {
+	c, err := Dial(s.server.URL)
+	require.NoError(t, err)
+	return c
+}
+
+const testOwner = "0x7e875ea78ee09f08d72e2676cf84e0f1c8ac61d94fa339cc8e37cace85bebc6e"
+
+func TestClient_GetObject(t *testing.T) {
+	standIn := newGraphqlStandIn(t)
+	c := standIn.client(t)
+	objectId, err := sui_types.NewObjectIdFromHex("0x5d3f1f3b3a6fba8a1a0a0d9ec53a8a72d3d1e7b9cf2a5b0e1f4c5d6e7f8a9b0c")
+	require.NoError(t, err)
+	version := uint64(37)
+
+	object, err := c.GetObject(context.Background(), *objectId, &version)
+	require.NoError(t, err)
+	require.Equal(t, *objectId, object.ObjectId)
+	require.Equal(t, uint64(37), object.Version)
+	require.Equal(t, uint64(1976000), object.StorageRebate.Uint64())
+	require.Equal(t, OwnerAddress, object.Owner.Kind)
+	require.Equal(t, testOwner, object.Owner.Address.String())
+	require.Equal(t, "2qRaZa8JJMTBxAnVJT7B3bTQmMBDmBRcfH1p8cXfjUeZ", object.PreviousTransaction.String())
+	require.Contains(t, object.Type, "::coin::Coin<")
+	require.JSONEq(t, `{"value": "1000"}`, string(mustField(t, object.Json, "balance")))
+	require.Equal(t, lib.Base64Data{1, 2, 3}, object.Bcs)
+	require.Equal(t, object.Digest, object.Reference().Digest)
+
+	variables := standIn.lastVariables("GetObject")
+	require.JSONEq(t, `37`, string(variables["version"]))
+	require.JSONEq(t, `"`+objectId.String()+`"`, string(variables["address"]))
+}
+
+func TestClient_NotFound(t *testing.T) {
+	standIn := newGraphqlStandIn(t)
+	standIn.override("GetObject", func(map[string]json.RawMessage) string { return "null_object.json" })
+	c := standIn.client(t)
+
+	_, err := c.GetObject(context.Background(), sui_types.ObjectID{}, nil)
+	require.ErrorIs(t, err, ErrNotFound)
+	_, ok := standIn.lastVariables("GetObject")["version"]
+	require.False(t, ok)
+}
+
+func TestClient_GetCoins(t *testing.T) {
+	standIn := newGraphqlStandIn(t)
+	standIn.override(
+		"GetCoins", func(variables map[string]json.RawMessage) string {
+			if _, ok := variables["after"]; ok {
+				return "GetCoins.page2.json"
+			}
+			return "GetCoins.json"
+		},
+	)
+	c := standIn.client(t)
+	owner, err := sui_types.NewAddressFromHex(testOwner)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	page, err := c.GetCoins(ctx, *owner, "0x2::sui::SUI", nil, 1)
+	require.NoError(t, err)
+	require.Len(t, page.Nodes, 1)
+	require.True(t, page.PageInfo.HasNextPage)
+	coin := page.Nodes[0]
+	require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000002::sui::SUI", coin.CoinType)
+	require.Equal(t, uint64(1000), coin.Balance.Uint64())
+	require.Equal(t, uint64(37), coin.Version.Uint64())
+	require.Equal(t, "2qRaZa8JJMTBxAnVJT7B3bTQmMBDmBRcfH1p8cXfjUeZ", coin.PreviousTransaction.String())
+	variables := standIn.lastVariables("GetCoins")
+	require.JSONEq(t, `1`, string(variables["first"]))
+	require.JSONEq(t, `"0x2::sui::SUI"`, string(variables["type"]))
+
+	coins, err := CollectPages(
+		ctx, nil, 0, func(ctx context.Context, cursor *string) (*Page[struct{ Balance uint64 }], error) {
+			page, err := c.GetCoins(ctx, *owner, "", cursor, 1)
+			if err != nil {
+				return nil, err
+			}
+			result := &Page[struct{ Balance uint64 }]{PageInfo: page.PageInfo}
+			for _, coin := range page.Nodes {
+				result.Nodes = append(result.Nodes, struct{ Balance uint64 }{coin.Balance.Uint64()})
+			}
+			return result, nil
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []struct{ Balance uint64 }{{1000}, {2500}}, coins)
+	require.JSONEq(t, `"IAAAAAAAAAA"`, string(standIn.lastVariables("GetCoins")["after"]))
+
+	limited, err := CollectPages(
+		ctx, nil, 1, func(ctx context.Context, cursor *string) (*Page[string], error) {
+			return &Page[string]{Nodes: []string{"a", "b"}, PageInfo: PageInfo{HasNextPage: true}}, nil
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, limited)
+}
+
+func TestClient_GetAllBalances(t *testing.T) {
+	standIn := newGraphqlStandIn(t)
+	c := standIn.client(t)
+	owner, err := sui_types.NewAddressFromHex(testOwner)
+	require.NoError(t, err)
+
+	page, err := c.GetAllBalances(context.Background(), *owner, nil, 0)
+	require.NoError(t, err)
+	require.Len(t, page.Nodes, 1)
+	require.Equal(t, uint64(2), page.Nodes[0].CoinObjectCount)
+	require.Equal(t, "3500", page.Nodes[0].TotalBalance.String())
+	_, ok := standIn.lastVariables("GetAllBalances")["first"]
+	require.False(t, ok)
+}
+
+func TestClient_QueryTransactionBlocks(t *testing.T) {
+	standIn := newGraphqlStandIn(t)
+	c := standIn.client(t)
+	owner, err := sui_types.NewAddressFromHex(testOwner)
+	require.NoError(t, err)
+	cursor := "eyJjIjo5LCJ0Ijo0fQ"
+
+	page, err := c.QueryTransactionBlocks(
+		context.Background(), &TransactionBlockFilter{SignAddress: owner}, &cursor, 10,
+	)
+	require.NoError(t, err)
+	require.Len(t, page.Nodes, 1)
+	tx := page.Nodes[0]
+	require.Equal(t, "HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn", tx.Digest.String())
+	require.Equal(t, testOwner, tx.Sender.String())
+	require.False(t, tx.Effects.IsSuccess())
+	require.Equal(t, "InsufficientGas", *tx.Effects.Errors)
+	require.Equal(t, uint64(28810000), *tx.Effects.Checkpoint)
+	require.Equal(t, uint64(330), *tx.Effects.Epoch)
+	require.Equal(t, uint64(750000), tx.Effects.GasSummary.ComputationCost.Uint64())
+	require.Equal(t, 123000000, tx.Effects.Timestamp.Nanosecond())
+
+	variables := standIn.lastVariables("QueryTransactionBlocks")
+	require.JSONEq(t, `{"signAddress": "`+testOwner+`"}`, string(variables["filter"]))
+	require.JSONEq(t, `"`+cursor+`"`, string(variables["after"]))
+	require.JSONEq(t, `10`, string(variables["first"]))
+}
+
+func TestClient_QueryEvents(t *testing.T) {
+	standIn := newGraphqlStandIn(t)
+	c := standIn.client(t)
+
+	page, err := c.QueryEvents(context.Background(), &EventFilter{EmittingModule: "0x3::validator"}, nil, 0)
+	require.NoError(t, err)
+	require.False(t, page.PageInfo.HasNextPage)
+	require.Nil(t, page.PageInfo.EndCursor)
+	require.Len(t, page.Nodes, 1)
+	event := page.Nodes[0]
+	require.Equal(t, "validator", event.Module)
+	require.Equal(t, *sui_types.SuiSystemAddress, event.PackageId)
+	require.Contains(t, event.Type, "::validator::StakingRequestEvent")
+	require.JSONEq(t, `{"amount": "1000000000"}`, string(event.Json))
+}
+
+func TestClient_GetCheckpointAndEpoch(t *testing.T) {
+	standIn := newGraphqlStandIn(t)
+	c := standIn.client(t)
+	ctx := context.Background()
+	sequenceNumber := uint64(28810000)
+
+	checkpoint, err := c.GetCheckpoint(ctx, &sequenceNumber)
+	require.NoError(t, err)
+	require.Equal(t, sequenceNumber, checkpoint.SequenceNumber)
+	require.Equal(t, uint64(330), checkpoint.Epoch)
+	require.Equal(t, uint64(1523456789), checkpoint.NetworkTotalTransactions)
+	require.Equal(t, uint64(3400), checkpoint.RollingGasSummary.StorageCost.Uint64())
+	require.NotNil(t, checkpoint.PreviousCheckpointDigest)
+	require.JSONEq(t, `{"sequenceNumber": 28810000}`, string(standIn.lastVariables("GetCheckpoint")["id"]))
+
+	epoch, err := c.GetEpoch(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(330), epoch.EpochId)
+	require.Equal(t, uint64(750), epoch.ReferenceGasPrice.Uint64())
+	require.Equal(t, uint64(38), epoch.ProtocolVersion)
+	require.Nil(t, epoch.EndTimestamp)
+	require.Nil(t, epoch.TotalTransactions)
+	require.Equal(t, uint64(8600), *epoch.TotalCheckpoints)
+	require.Empty(t, standIn.lastVariables("GetEpoch"))
+}
+
+func TestClient_ExecuteTransactionBlock(t *testing.T) {
+	standIn := newGraphqlStandIn(t)
+	c := standIn.client(t)
+	ctx := context.Background()
+	txBytes := lib.Base64Data{1, 2, 3}
+	signature := sui_types.Signature{Ed25519SuiSignature: &sui_types.Ed25519SuiSignature{}}
+	signature.Ed25519SuiSignature.Signature[0] = 7
+
+	result, err := c.ExecuteTransactionBlock(ctx, txBytes, []sui_types.Signature{signature})
+	require.NoError(t, err)
+	require.Empty(t, result.Errors)
+	require.Equal(t, "HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn", result.Digest.String())
+	require.True(t, result.Effects.IsSuccess())
+	require.Equal(t, uint64(978120), result.Effects.GasSummary.StorageRebate.Uint64())
+
+	variables := standIn.lastVariables("ExecuteTransactionBlock")
+	require.JSONEq(t, `"AQID"`, string(variables["txBytes"]))
+	var signatures []string
+	require.NoError(t, json.Unmarshal(variables["signatures"], &signatures))
+	require.Equal(
+		t, []string{base64.StdEncoding.EncodeToString(signature.Ed25519SuiSignature.Signature[:])}, signatures,
+	)
+
+	dryRun, err := c.DryRunTransactionBlock(ctx, txBytes)
+	require.NoError(t, err)
+	require.Contains(t, *dryRun.Error, "ObjectNotFound")
+	require.Nil(t, dryRun.Effects)
+}
+
+func TestClient_Errors(t *testing.T) {
+	standIn := newGraphqlStandIn(t)
+	standIn.override("GetEpoch", func(map[string]json.RawMessage) string { return "errors.json" })
+	c := standIn.client(t)
+	ctx := context.Background()
+
+	_, err := c.GetEpoch(ctx, nil)
+	var graphqlErrors Errors
+	require.True(t, errors.As(err, &graphqlErrors))
+	require.Len(t, graphqlErrors, 2)
+	var first Error
+	require.True(t, errors.As(err, &first))
+	require.Equal(t, []interface{}{"epoch", "referenceGasPrice"}, first.Path)
+	require.Equal(t, "INTERNAL_SERVER_ERROR", first.Extensions["code"])
+
+	_, err = c.GetCheckpoint(ctx, nil)
+	require.NoError(t, err)
+	standIn.override("GetCheckpoint", func(map[string]json.RawMessage) string { return "missing.json" })
+	_, err = c.GetCheckpoint(ctx, nil)
+	var httpErr client.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+}
+
+func mustField(t *testing.T, object json.RawMessage, field string) json.RawMessage {
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(object, &fields))
+	return fields[field]
+}
@@ -0,0 +1,392 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+// Owner kinds, the GraphQL type name of the owner of an object.
+const (
+	OwnerAddress   = "AddressOwner"
+	OwnerParent    = "Parent"
+	OwnerShared    = "Shared"
+	OwnerImmutable = "Immutable"
+)
+
+// Transaction statuses of TransactionEffects.
+const (
+	StatusSuccess = "SUCCESS"
+	StatusFailure = "FAILURE"
+)
+
+type ObjectOwner struct {
+	Kind string
+	// Address is the owner of an OwnerAddress or the parent of an OwnerParent object.
+	Address              *sui_types.SuiAddress
+	InitialSharedVersion *uint64
+}
+
+type Object struct {
+	ObjectId            sui_types.ObjectID
+	Version             uint64
+	Digest              sui_types.ObjectDigest
+	Owner               *ObjectOwner
+	PreviousTransaction *sui_types.TransactionDigest
+	StorageRebate       *types.SafeSuiBigInt[uint64]
+	// Type, Json and Bcs are only set for a move object.
+	Type string
+	Json json.RawMessage
+	Bcs  lib.Base64Data
+}
+
+// Reference returns the object ref of the object, to use it as a transaction input.
+func (o *Object) Reference() *sui_types.ObjectRef {
+	return &sui_types.ObjectRef{ObjectId: o.ObjectId, Version: o.Version, Digest: o.Digest}
+}
+
+type TransactionEffects struct {
+	Status string
+	// Errors is the error of a failed transaction.
+	Errors     *string
+	Timestamp  *time.Time
+	Checkpoint *uint64
+	Epoch      *uint64
+	GasSummary *types.GasCostSummary
+}
+
+func (e *TransactionEffects) IsSuccess() bool {
+	return e.Status == StatusSuccess
+}
+
+type TransactionBlock struct {
+	Digest sui_types.TransactionDigest
+	Sender *sui_types.SuiAddress
+	// Bcs is the BCS serialized TransactionData.
+	Bcs     lib.Base64Data
+	Effects *TransactionEffects
+}
+
+type Event struct {
+	PackageId sui_types.ObjectID
+	Module    string
+	Type      string
+	Sender    *sui_types.SuiAddress
+	Timestamp *time.Time
+	Json      json.RawMessage
+	Bcs       lib.Base64Data
+}
+
+type Checkpoint struct {
+	Digest                   sui_types.CheckpointDigest
+	SequenceNumber           uint64
+	Timestamp                time.Time
+	PreviousCheckpointDigest *sui_types.CheckpointDigest
+	NetworkTotalTransactions uint64
+	Epoch                    uint64
+	RollingGasSummary        types.GasCostSummary
+}
+
+type Epoch struct {
+	EpochId           uint64
+	ReferenceGasPrice types.SafeSuiBigInt[uint64]
+	StartTimestamp    time.Time
+	// EndTimestamp is nil for the current epoch.
+	EndTimestamp      *time.Time
+	TotalTransactions *uint64
+	TotalCheckpoints  *uint64
+	ProtocolVersion   uint64
+}
+
+// ExecutionResult is the result of executeTransactionBlock, Errors are set if the transaction was
+// not executed.
+type ExecutionResult struct {
+	Digest  *sui_types.TransactionDigest
+	Errors  []string
+	Effects *TransactionEffects
+}
+
+// DryRunResult is the result of dryRunTransactionBlock, Error is set if the transaction could not
+// be run.
+type DryRunResult struct {
+	Error   *string
+	Effects *TransactionEffects
+}
+
+// TransactionBlockFilter filters QueryTransactionBlocks, a Function is a package, a
+// package::module or a package::module::function.
+type TransactionBlockFilter struct {
+	Function         string                `json:"function,omitempty"`
+	AfterCheckpoint  *uint64               `json:"afterCheckpoint,omitempty"`
+	AtCheckpoint     *uint64               `json:"atCheckpoint,omitempty"`
+	BeforeCheckpoint *uint64               `json:"beforeCheckpoint,omitempty"`
+	SignAddress      *sui_types.SuiAddress `json:"signAddress,omitempty"`
+	RecvAddress      *sui_types.SuiAddress `json:"recvAddress,omitempty"`
+	InputObject      *sui_types.ObjectID   `json:"inputObject,omitempty"`
+	ChangedObject    *sui_types.ObjectID   `json:"changedObject,omitempty"`
+	TransactionIds   []string              `json:"transactionIds,omitempty"`
+}
+
+// EventFilter filters QueryEvents, an EmittingModule is a package or a package::module, an
+// EventType a package, a package::module or a full type.
+type EventFilter struct {
+	Sender            *sui_types.SuiAddress `json:"sender,omitempty"`
+	TransactionDigest string                `json:"transactionDigest,omitempty"`
+	EmittingModule    string                `json:"emittingModule,omitempty"`
+	EventType         string                `json:"eventType,omitempty"`
+}
+
+// The raw shapes of the GraphQL results, converted into the models above.
+
+type rawAddress struct {
+	Address sui_types.SuiAddress `json:"address"`
+}
+
+type rawDigest struct {
+	Digest sui_types.TransactionDigest `json:"digest"`
+}
+
+type rawMoveType struct {
+	Repr string `json:"repr"`
+}
+
+type rawMoveValue struct {
+	Type rawMoveType     `json:"type"`
+	Json json.RawMessage `json:"json"`
+	Bcs  lib.Base64Data  `json:"bcs"`
+}
+
+type rawOwner struct {
+	Typename             string      `json:"__typename"`
+	Owner                *rawAddress `json:"owner"`
+	Parent               *rawAddress `json:"parent"`
+	InitialSharedVersion *uint64     `json:"initialSharedVersion"`
+}
+
+type rawObject struct {
+	Address                  sui_types.ObjectID           `json:"address"`
+	Version                  uint64                       `json:"version"`
+	Digest                   sui_types.ObjectDigest       `json:"digest"`
+	StorageRebate            *types.SafeSuiBigInt[uint64] `json:"storageRebate"`
+	Owner                    *rawOwner                    `json:"owner"`
+	PreviousTransactionBlock *rawDigest                   `json:"previousTransactionBlock"`
+	// Contents of a MoveObject node, or of the AsMoveObject of an Object node.
+	Contents     *rawMoveValue `json:"contents"`
+	AsMoveObject *struct {
+		Contents *rawMoveValue `json:"contents"`
+	} `json:"asMoveObject"`
+}
+
+func (r *rawObject) object() (*Object, error) {
+	object := &Object{
+		ObjectId:      r.Address,
+		Version:       r.Version,
+		Digest:        r.Digest,
+		StorageRebate: r.StorageRebate,
+	}
+	if r.Owner != nil {
+		owner := &ObjectOwner{Kind: r.Owner.Typename, InitialSharedVersion: r.Owner.InitialSharedVersion}
+		if r.Owner.Owner != nil {
+			owner.Address = &r.Owner.Owner.Address
+		}
+		if r.Owner.Parent != nil {
+			owner.Address = &r.Owner.Parent.Address
+		}
+		object.Owner = owner
+	}
+	if r.PreviousTransactionBlock != nil {
+		object.PreviousTransaction = &r.PreviousTransactionBlock.Digest
+	}
+	contents := r.Contents
+	if contents == nil && r.AsMoveObject != nil {
+		contents = r.AsMoveObject.Contents
+	}
+	if contents != nil {
+		object.Type = contents.Type.Repr
+		object.Json = contents.Json
+		object.Bcs = contents.Bcs
+	}
+	return object, nil
+}
+
+type rawCoin struct {
+	rawObject
+	CoinBalance types.SafeSuiBigInt[uint64] `json:"coinBalance"`
+}
+
+func (r *rawCoin) coin() (types.Coin, error) {
+	object, err := r.object()
+	if err != nil {
+		return types.Coin{}, err
+	}
+	coin := types.Coin{
+		CoinType:     coinTypeOf(object.Type),
+		CoinObjectId: object.ObjectId,
+		Version:      types.NewSafeSuiBigInt(object.Version),
+		Digest:       object.Digest,
+		Balance:      r.CoinBalance,
+	}
+	if object.PreviousTransaction != nil {
+		coin.PreviousTransaction = *object.PreviousTransaction
+	}
+	return coin, nil
+}
+
+// coinTypeOf returns T of a 0x2::coin::Coin<T> object type.
+func coinTypeOf(objectType string) string {
+	start := strings.Index(objectType, "<")
+	if start < 0 || !strings.HasSuffix(objectType, ">") {
+		return objectType
+	}
+	return objectType[start+1 : len(objectType)-1]
+}
+
+type rawBalance struct {
+	CoinType        rawMoveType `json:"coinType"`
+	CoinObjectCount uint64      `json:"coinObjectCount"`
+	TotalBalance    string      `json:"totalBalance"`
+}
+
+func (r *rawBalance) balance() (types.Balance, error) {
+	total, err := decimal.NewFromString(r.TotalBalance)
+	if err != nil {
+		return types.Balance{}, fmt.Errorf("total balance %q: %w", r.TotalBalance, err)
+	}
+	return types.Balance{
+		CoinType:        r.CoinType.Repr,
+		CoinObjectCount: r.CoinObjectCount,
+		TotalBalance:    total,
+	}, nil
+}
+
+type rawEffects struct {
+	Status     string     `json:"status"`
+	Errors     *string    `json:"errors"`
+	Timestamp  *time.Time `json:"timestamp"`
+	Checkpoint *struct {
+		SequenceNumber uint64 `json:"sequenceNumber"`
+	} `json:"checkpoint"`
+	Epoch *struct {
+		EpochId uint64 `json:"epochId"`
+	} `json:"epoch"`
+	GasEffects *struct {
+		GasSummary *types.GasCostSummary `json:"gasSummary"`
+	} `json:"gasEffects"`
+	TransactionBlock *rawDigest `json:"transactionBlock"`
+}
+
+func (r *rawEffects) effects() *TransactionEffects {
+	if r == nil {
+		return nil
+	}
+	effects := &TransactionEffects{Status: r.Status, Errors: r.Errors, Timestamp: r.Timestamp}
+	if r.Checkpoint != nil {
+		effects.Checkpoint = &r.Checkpoint.SequenceNumber
+	}
+	if r.Epoch != nil {
+		effects.Epoch = &r.Epoch.EpochId
+	}
+	if r.GasEffects != nil {
+		effects.GasSummary = r.GasEffects.GasSummary
+	}
+	return effects
+}
+
+type rawTransactionBlock struct {
+	Digest  sui_types.TransactionDigest `json:"digest"`
+	Sender  *rawAddress                 `json:"sender"`
+	Bcs     lib.Base64Data              `json:"bcs"`
+	Effects *rawEffects                 `json:"effects"`
+}
+
+func (r *rawTransactionBlock) transactionBlock() (TransactionBlock, error) {
+	tx := TransactionBlock{Digest: r.Digest, Bcs: r.Bcs, Effects: r.Effects.effects()}
+	if r.Sender != nil {
+		tx.Sender = &r.Sender.Address
+	}
+	return tx, nil
+}
+
+type rawEvent struct {
+	SendingModule *struct {
+		Package rawAddress `json:"package"`
+		Name    string     `json:"name"`
+	} `json:"sendingModule"`
+	Type      rawMoveType     `json:"type"`
+	Sender    *rawAddress     `json:"sender"`
+	Timestamp *time.Time      `json:"timestamp"`
+	Json      json.RawMessage `json:"json"`
+	Bcs       lib.Base64Data  `json:"bcs"`
+}
+
+func (r *rawEvent) event() (Event, error) {
+	event := Event{Type: r.Type.Repr, Timestamp: r.Timestamp, Json: r.Json, Bcs: r.Bcs}
+	if r.SendingModule != nil {
+		event.PackageId = r.SendingModule.Package.Address
+		event.Module = r.SendingModule.Name
+	}
+	if r.Sender != nil {
+		event.Sender = &r.Sender.Address
+	}
+	return event, nil
+}
+
+type rawCheckpoint struct {
+	Digest                   sui_types.CheckpointDigest  `json:"digest"`
+	SequenceNumber           uint64                      `json:"sequenceNumber"`
+	Timestamp                time.Time                   `json:"timestamp"`
+	PreviousCheckpointDigest *sui_types.CheckpointDigest `json:"previousCheckpointDigest"`
+	NetworkTotalTransactions uint64                      `json:"networkTotalTransactions"`
+	Epoch                    *struct {
+		EpochId uint64 `json:"epochId"`
+	} `json:"epoch"`
+	RollingGasSummary types.GasCostSummary `json:"rollingGasSummary"`
+}
+
+func (r *rawCheckpoint) checkpoint() (Checkpoint, error) {
+	checkpoint := Checkpoint{
+		Digest:                   r.Digest,
+		SequenceNumber:           r.SequenceNumber,
+		Timestamp:                r.Timestamp,
+		PreviousCheckpointDigest: r.PreviousCheckpointDigest,
+		NetworkTotalTransactions: r.NetworkTotalTransactions,
+		RollingGasSummary:        r.RollingGasSummary,
+	}
+	if r.Epoch != nil {
+		checkpoint.Epoch = r.Epoch.EpochId
+	}
+	return checkpoint, nil
+}
+
+type rawEpoch struct {
+	EpochId           uint64                      `json:"epochId"`
+	ReferenceGasPrice types.SafeSuiBigInt[uint64] `json:"referenceGasPrice"`
+	StartTimestamp    time.Time                   `json:"startTimestamp"`
+	EndTimestamp      *time.Time                  `json:"endTimestamp"`
+	TotalTransactions *uint64                     `json:"totalTransactions"`
+	TotalCheckpoints  *uint64                     `json:"totalCheckpoints"`
+	ProtocolConfigs   *struct {
+		ProtocolVersion uint64 `json:"protocolVersion"`
+	} `json:"protocolConfigs"`
+}
+
+func (r *rawEpoch) epoch() (Epoch, error) {
+	epoch := Epoch{
+		EpochId:           r.EpochId,
+		ReferenceGasPrice: r.ReferenceGasPrice,
+		StartTimestamp:    r.StartTimestamp,
+		EndTimestamp:      r.EndTimestamp,
+		TotalTransactions: r.TotalTransactions,
+		TotalCheckpoints:  r.TotalCheckpoints,
+	}
+	if r.ProtocolConfigs != nil {
+		epoch.ProtocolVersion = r.ProtocolConfigs.ProtocolVersion
+	}
+	return epoch, nil
+}
@@ -0,0 +1,392 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+const (
+	pageInfoFields = `pageInfo { hasNextPage endCursor }`
+	ownerFields    = `owner {
+		__typename
+		... on AddressOwner { owner { address } }
+		... on Parent { parent { address } }
+		... on Shared { initialSharedVersion }
+	}`
+	objectFields = `address version digest storageRebate ` + ownerFields + `
+	previousTransactionBlock { digest }`
+	gasSummaryFields = `{ computationCost storageCost storageRebate nonRefundableStorageFee }`
+	effectsFields    = `status errors timestamp
+	checkpoint { sequenceNumber }
+	epoch { epochId }
+	gasEffects { gasSummary ` + gasSummaryFields + ` }`
+	transactionFields = `digest sender { address } bcs effects { ` + effectsFields + ` }`
+	eventFields       = `sendingModule { package { address } name } type { repr } sender { address } timestamp json bcs`
+	checkpointFields  = `digest sequenceNumber timestamp previousCheckpointDigest networkTotalTransactions
+	epoch { epochId } rollingGasSummary ` + gasSummaryFields
+	epochFields = `epochId referenceGasPrice startTimestamp endTimestamp totalTransactions totalCheckpoints
+	protocolConfigs { protocolVersion }`
+	balanceFields = `coinType { repr } coinObjectCount totalBalance`
+)
+
+const (
+	getObjectQuery = `query GetObject($address: SuiAddress!, $version: UInt53) {
+	object(address: $address, version: $version) { ` + objectFields + ` asMoveObject { contents { type { repr } json bcs } } }
+}`
+	getOwnedObjectsQuery = `query GetOwnedObjects($owner: SuiAddress!, $type: String, $first: Int, $after: String) {
+	address(address: $owner) {
+		objects(filter: { type: $type }, first: $first, after: $after) {
+			` + pageInfoFields + ` nodes { ` + objectFields + ` contents { type { repr } json bcs } }
+		}
+	}
+}`
+	getCoinsQuery = `query GetCoins($owner: SuiAddress!, $type: String, $first: Int, $after: String) {
+	address(address: $owner) {
+		coins(type: $type, first: $first, after: $after) {
+			` + pageInfoFields + ` nodes { ` + objectFields + ` coinBalance contents { type { repr } } }
+		}
+	}
+}`
+	getBalanceQuery = `query GetBalance($owner: SuiAddress!, $type: String) {
+	address(address: $owner) { balance(type: $type) { ` + balanceFields + ` } }
+}`
+	getAllBalancesQuery = `query GetAllBalances($owner: SuiAddress!, $first: Int, $after: String) {
+	address(address: $owner) {
+		balances(first: $first, after: $after) { ` + pageInfoFields + ` nodes { ` + balanceFields + ` } }
+	}
+}`
+	getTransactionBlockQuery = `query GetTransactionBlock($digest: String!) {
+	transactionBlock(digest: $digest) { ` + transactionFields + ` }
+}`
+	queryTransactionBlocksQuery = `query QueryTransactionBlocks($filter: TransactionBlockFilter, $first: Int, $after: String) {
+	transactionBlocks(filter: $filter, first: $first, after: $after) {
+		` + pageInfoFields + ` nodes { ` + transactionFields + ` }
+	}
+}`
+	queryEventsQuery = `query QueryEvents($filter: EventFilter, $first: Int, $after: String) {
+	events(filter: $filter, first: $first, after: $after) { ` + pageInfoFields + ` nodes { ` + eventFields + ` } }
+}`
+	getCheckpointQuery = `query GetCheckpoint($id: CheckpointId) {
+	checkpoint(id: $id) { ` + checkpointFields + ` }
+}`
+	getCheckpointsQuery = `query GetCheckpoints($first: Int, $after: String) {
+	checkpoints(first: $first, after: $after) { ` + pageInfoFields + ` nodes { ` + checkpointFields + ` } }
+}`
+	getEpochQuery = `query GetEpoch($id: UInt53) {
+	epoch(id: $id) { ` + epochFields + ` }
+}`
+	getEpochsQuery = `query GetEpochs($first: Int, $after: String) {
+	epochs(first: $first, after: $after) { ` + pageInfoFields + ` nodes { ` + epochFields + ` } }
+}`
+	executeTransactionBlockMutation = `mutation ExecuteTransactionBlock($txBytes: String!, $signatures: [String!]!) {
+	executeTransactionBlock(txBytes: $txBytes, signatures: $signatures) {
+		errors effects { ` + effectsFields + ` transactionBlock { digest } }
+	}
+}`
+	dryRunTransactionBlockQuery = `query DryRunTransactionBlock($txBytes: String!) {
+	dryRunTransactionBlock(txBytes: $txBytes) { error transaction { effects { ` + effectsFields + ` } } }
+}`
+)
+
+func (c *Client) query(
+	ctx context.Context,
+	operation, query string,
+	variables map[string]interface{},
+	result interface{},
+) error {
+	return c.do(ctx, request{Query: query, OperationName: operation, Variables: variables}, result)
+}
+
+// MARK - Objects
+
+// GetObject returns the object at its latest version, or at version if it is not nil.
+func (c *Client) GetObject(ctx context.Context, objectId sui_types.ObjectID, version *uint64) (*Object, error) {
+	variables := map[string]interface{}{"address": objectId}
+	if version != nil {
+		variables["version"] = *version
+	}
+	var data struct {
+		Object *rawObject `json:"object"`
+	}
+	if err := c.query(ctx, "GetObject", getObjectQuery, variables, &data); err != nil {
+		return nil, err
+	}
+	if data.Object == nil {
+		return nil, ErrNotFound
+	}
+	return data.Object.object()
+}
+
+// GetOwnedObjects returns the move objects of owner, of objectType if it is not empty.
+func (c *Client) GetOwnedObjects(
+	ctx context.Context,
+	owner sui_types.SuiAddress,
+	objectType string,
+	cursor *string,
+	first int,
+) (*Page[Object], error) {
+	variables := pageVariables(map[string]interface{}{"owner": owner}, cursor, first)
+	if objectType != "" {
+		variables["type"] = objectType
+	}
+	var data struct {
+		Address *struct {
+			Objects *connection[rawObject] `json:"objects"`
+		} `json:"address"`
+	}
+	if err := c.query(ctx, "GetOwnedObjects", getOwnedObjectsQuery, variables, &data); err != nil {
+		return nil, err
+	}
+	if data.Address == nil {
+		return &Page[Object]{}, nil
+	}
+	return page(
+		data.Address.Objects, func(r *rawObject) (Object, error) {
+			object, err := r.object()
+			if err != nil {
+				return Object{}, err
+			}
+			return *object, nil
+		},
+	)
+}
+
+// MARK - Coins
+
+// GetCoins returns the coins of owner, of coinType if it is not empty, the coin type is like
+// 0x2::sui::SUI.
+func (c *Client) GetCoins(
+	ctx context.Context,
+	owner sui_types.SuiAddress,
+	coinType string,
+	cursor *string,
+	first int,
+) (*Page[types.Coin], error) {
+	variables := pageVariables(map[string]interface{}{"owner": owner}, cursor, first)
+	if coinType != "" {
+		variables["type"] = coinType
+	}
+	var data struct {
+		Address *struct {
+			Coins *connection[rawCoin] `json:"coins"`
+		} `json:"address"`
+	}
+	if err := c.query(ctx, "GetCoins", getCoinsQuery, variables, &data); err != nil {
+		return nil, err
+	}
+	if data.Address == nil {
+		return &Page[types.Coin]{}, nil
+	}
+	return page(data.Address.Coins, (*rawCoin).coin)
+}
+
+// GetBalance returns the balance of coinType of owner, SUI if it is empty.
+func (c *Client) GetBalance(ctx context.Context, owner sui_types.SuiAddress, coinType string) (*types.Balance, error) {
+	variables := map[string]interface{}{"owner": owner}
+	if coinType != "" {
+		variables["type"] = coinType
+	}
+	var data struct {
+		Address *struct {
+			Balance *rawBalance `json:"balance"`
+		} `json:"address"`
+	}
+	if err := c.query(ctx, "GetBalance", getBalanceQuery, variables, &data); err != nil {
+		return nil, err
+	}
+	if data.Address == nil || data.Address.Balance == nil {
+		return nil, ErrNotFound
+	}
+	balance, err := data.Address.Balance.balance()
+	return &balance, err
+}
+
+func (c *Client) GetAllBalances(
+	ctx context.Context,
+	owner sui_types.SuiAddress,
+	cursor *string,
+	first int,
+) (*Page[types.Balance], error) {
+	variables := pageVariables(map[string]interface{}{"owner": owner}, cursor, first)
+	var data struct {
+		Address *struct {
+			Balances *connection[rawBalance] `json:"balances"`
+		} `json:"address"`
+	}
+	if err := c.query(ctx, "GetAllBalances", getAllBalancesQuery, variables, &data); err != nil {
+		return nil, err
+	}
+	if data.Address == nil {
+		return &Page[types.Balance]{}, nil
+	}
+	return page(data.Address.Balances, (*rawBalance).balance)
+}
+
+// MARK - Transactions & Events
+
+func (c *Client) GetTransactionBlock(
+	ctx context.Context,
+	digest sui_types.TransactionDigest,
+) (*TransactionBlock, error) {
+	var data struct {
+		TransactionBlock *rawTransactionBlock `json:"transactionBlock"`
+	}
+	variables := map[string]interface{}{"digest": digest}
+	if err := c.query(ctx, "GetTransactionBlock", getTransactionBlockQuery, variables, &data); err != nil {
+		return nil, err
+	}
+	if data.TransactionBlock == nil {
+		return nil, ErrNotFound
+	}
+	tx, err := data.TransactionBlock.transactionBlock()
+	return &tx, err
+}
+
+func (c *Client) QueryTransactionBlocks(
+	ctx context.Context,
+	filter *TransactionBlockFilter,
+	cursor *string,
+	first int,
+) (*Page[TransactionBlock], error) {
+	variables := pageVariables(nil, cursor, first)
+	if filter != nil {
+		variables["filter"] = filter
+	}
+	var data struct {
+		TransactionBlocks *connection[rawTransactionBlock] `json:"transactionBlocks"`
+	}
+	if err := c.query(ctx, "QueryTransactionBlocks", queryTransactionBlocksQuery, variables, &data); err != nil {
+		return nil, err
+	}
+	return page(data.TransactionBlocks, (*rawTransactionBlock).transactionBlock)
+}
+
+func (c *Client) QueryEvents(
+	ctx context.Context,
+	filter *EventFilter,
+	cursor *string,
+	first int,
+) (*Page[Event], error) {
+	variables := pageVariables(nil, cursor, first)
+	if filter != nil {
+		variables["filter"] = filter
+	}
+	var data struct {
+		Events *connection[rawEvent] `json:"events"`
+	}
+	if err := c.query(ctx, "QueryEvents", queryEventsQuery, variables, &data); err != nil {
+		return nil, err
+	}
+	return page(data.Events, (*rawEvent).event)
+}
+
+// MARK - Checkpoints & Epochs
+
+// GetCheckpoint returns the checkpoint sequenceNumber, or the latest one if it is nil.
+func (c *Client) GetCheckpoint(ctx context.Context, sequenceNumber *uint64) (*Checkpoint, error) {
+	variables := map[string]interface{}{}
+	if sequenceNumber != nil {
+		variables["id"] = map[string]interface{}{"sequenceNumber": *sequenceNumber}
+	}
+	var data struct {
+		Checkpoint *rawCheckpoint `json:"checkpoint"`
+	}
+	if err := c.query(ctx, "GetCheckpoint", getCheckpointQuery, variables, &data); err != nil {
+		return nil, err
+	}
+	if data.Checkpoint == nil {
+		return nil, ErrNotFound
+	}
+	checkpoint, err := data.Checkpoint.checkpoint()
+	return &checkpoint, err
+}
+
+func (c *Client) GetCheckpoints(ctx context.Context, cursor *string, first int) (*Page[Checkpoint], error) {
+	var data struct {
+		Checkpoints *connection[rawCheckpoint] `json:"checkpoints"`
+	}
+	if err := c.query(ctx, "GetCheckpoints", getCheckpointsQuery, pageVariables(nil, cursor, first), &data); err != nil {
+		return nil, err
+	}
+	return page(data.Checkpoints, (*rawCheckpoint).checkpoint)
+}
+
+// GetEpoch returns the epoch id, or the current one if it is nil.
+func (c *Client) GetEpoch(ctx context.Context, id *uint64) (*Epoch, error) {
+	variables := map[string]interface{}{}
+	if id != nil {
+		variables["id"] = *id
+	}
+	var data struct {
+		Epoch *rawEpoch `json:"epoch"`
+	}
+	if err := c.query(ctx, "GetEpoch", getEpochQuery, variables, &data); err != nil {
+		return nil, err
+	}
+	if data.Epoch == nil {
+		return nil, ErrNotFound
+	}
+	epoch, err := data.Epoch.epoch()
+	return &epoch, err
+}
+
+func (c *Client) GetEpochs(ctx context.Context, cursor *string, first int) (*Page[Epoch], error) {
+	var data struct {
+		Epochs *connection[rawEpoch] `json:"epochs"`
+	}
+	if err := c.query(ctx, "GetEpochs", getEpochsQuery, pageVariables(nil, cursor, first), &data); err != nil {
+		return nil, err
+	}
+	return page(data.Epochs, (*rawEpoch).epoch)
+}
+
+// MARK - Execution
+
+// ExecuteTransactionBlock executes the BCS serialized TransactionData txBytes signed with signatures.
+func (c *Client) ExecuteTransactionBlock(
+	ctx context.Context,
+	txBytes lib.Base64Data,
+	signatures []sui_types.Signature,
+) (*ExecutionResult, error) {
+	variables := map[string]interface{}{"txBytes": txBytes, "signatures": signatures}
+	var data struct {
+		ExecuteTransactionBlock struct {
+			Errors  []string    `json:"errors"`
+			Effects *rawEffects `json:"effects"`
+		} `json:"executeTransactionBlock"`
+	}
+	if err := c.query(ctx, "ExecuteTransactionBlock", executeTransactionBlockMutation, variables, &data); err != nil {
+		return nil, err
+	}
+	raw := data.ExecuteTransactionBlock
+	result := &ExecutionResult{Errors: raw.Errors, Effects: raw.Effects.effects()}
+	if raw.Effects != nil && raw.Effects.TransactionBlock != nil {
+		result.Digest = &raw.Effects.TransactionBlock.Digest
+	}
+	return result, nil
+}
+
+// DryRunTransactionBlock runs the BCS serialized TransactionData txBytes without committing it.
+func (c *Client) DryRunTransactionBlock(ctx context.Context, txBytes lib.Base64Data) (*DryRunResult, error) {
+	variables := map[string]interface{}{"txBytes": txBytes}
+	var data struct {
+		DryRunTransactionBlock struct {
+			Error       *string `json:"error"`
+			Transaction *struct {
+				Effects *rawEffects `json:"effects"`
+			} `json:"transaction"`
+		} `json:"dryRunTransactionBlock"`
+	}
+	if err := c.query(ctx, "DryRunTransactionBlock", dryRunTransactionBlockQuery, variables, &data); err != nil {
+		return nil, err
+	}
+	raw := data.DryRunTransactionBlock
+	result := &DryRunResult{Error: raw.Error}
+	if raw.Transaction != nil {
+		result.Effects = raw.Transaction.Effects.effects()
+	}
+	return result, nil
+}
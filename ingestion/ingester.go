@@ -0,0 +1,293 @@
+// Package ingestion consumes the full contents of checkpoints, their transactions, effects,
+// events and objects, and dispatches them to handlers with at-least-once delivery.
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+// Handler processes the checkpoints in ascending order. A checkpoint is processed again if Process
+// fails, or if the ingester stops before its watermark is saved, so Process must be idempotent.
+type Handler interface {
+	// Name is the key of the watermark of the handler, it must be unique and stable across runs.
+	Name() string
+	Process(ctx context.Context, checkpoint *sui_types.CheckpointData) error
+}
+
+type handlerFunc struct {
+	name    string
+	process func(ctx context.Context, checkpoint *sui_types.CheckpointData) error
+}
+
+func (h handlerFunc) Name() string {
+	return h.name
+}
+
+func (h handlerFunc) Process(ctx context.Context, checkpoint *sui_types.CheckpointData) error {
+	return h.process(ctx, checkpoint)
+}
+
+func NewHandlerFunc(
+	name string,
+	process func(ctx context.Context, checkpoint *sui_types.CheckpointData) error,
+) Handler {
+	return handlerFunc{name: name, process: process}
+}
+
+type ingesterConfig struct {
+	pollInterval  time.Duration
+	minRetryDelay time.Duration
+	maxRetryDelay time.Duration
+	prefetch      int
+	last          *sui_types.CheckpointSequenceNumber
+	onError       func(err error)
+}
+
+type Option func(*ingesterConfig)
+
+// WithPollInterval sets how long the ingester waits for a checkpoint the source does not have yet.
+func WithPollInterval(interval time.Duration) Option {
+	return func(c *ingesterConfig) {
+		c.pollInterval = interval
+	}
+}
+
+// WithRetryDelay sets the exponential backoff of the failed fetches, handler calls and saves.
+func WithRetryDelay(minDelay, maxDelay time.Duration) Option {
+	return func(c *ingesterConfig) {
+		c.minRetryDelay = minDelay
+		c.maxRetryDelay = maxDelay
+	}
+}
+
+// WithPrefetch sets how many checkpoints are fetched ahead of the one being processed.
+func WithPrefetch(checkpoints int) Option {
+	return func(c *ingesterConfig) {
+		c.prefetch = checkpoints
+	}
+}
+
+// WithLastCheckpoint stops Run once the checkpoint last is processed by every handler.
+func WithLastCheckpoint(last sui_types.CheckpointSequenceNumber) Option {
+	return func(c *ingesterConfig) {
+		c.last = &last
+	}
+}
+
+// WithOnError receives the errors the ingester retries, of the source, the handlers and the store.
+func WithOnError(onError func(err error)) Option {
+	return func(c *ingesterConfig) {
+		c.onError = onError
+	}
+}
+
+// Ingester fetches the checkpoints from a Source and dispatches them to its handlers. Every
+// handler has its own watermark, saved to the WatermarkStore once it processed a checkpoint.
+type Ingester struct {
+	source   Source
+	store    WatermarkStore
+	config   ingesterConfig
+	handlers []Handler
+}
+
+func NewIngester(source Source, store WatermarkStore, options ...Option) *Ingester {
+	config := ingesterConfig{
+		pollInterval:  time.Second,
+		minRetryDelay: 100 * time.Millisecond,
+		maxRetryDelay: 10 * time.Second,
+		prefetch:      10,
+		onError:       func(error) {},
+	}
+	for _, option := range options {
+		option(&config)
+	}
+	if config.prefetch < 0 {
+		config.prefetch = 0
+	}
+	return &Ingester{source: source, store: store, config: config}
+}
+
+// Register adds a handler, it must be called before Run.
+func (i *Ingester) Register(handler Handler) {
+	i.handlers = append(i.handlers, handler)
+}
+
+// Run ingests the checkpoints from the lowest watermark of the handlers, a handler without a
+// saved watermark starts at the checkpoint start. It returns once the last checkpoint is
+// processed if WithLastCheckpoint is set, otherwise when ctx is done.
+func (i *Ingester) Run(ctx context.Context, start sui_types.CheckpointSequenceNumber) error {
+	if len(i.handlers) == 0 {
+		return errors.New("no handler registered")
+	}
+	watermarks := make([]sui_types.CheckpointSequenceNumber, len(i.handlers))
+	next := sui_types.CheckpointSequenceNumber(0)
+	for j, handler := range i.handlers {
+		watermark, ok, err := i.store.Load(ctx, handler.Name())
+		if err != nil {
+			return fmt.Errorf("load watermark of %s: %w", handler.Name(), err)
+		}
+		if !ok {
+			watermark = start
+		}
+		watermarks[j] = watermark
+		if j == 0 || watermark < next {
+			next = watermark
+		}
+	}
+	if i.done(next) {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	pending := make(chan chan *sui_types.CheckpointData, i.config.prefetch)
+	go i.prefetch(ctx, next, pending)
+
+	for fetched := range pending {
+		var checkpoint *sui_types.CheckpointData
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case checkpoint = <-fetched:
+		}
+		if checkpoint == nil {
+			return ctx.Err()
+		}
+		if err := i.dispatch(ctx, checkpoint, watermarks); err != nil {
+			return err
+		}
+		if i.done(checkpoint.SequenceNumber() + 1) {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+func (i *Ingester) done(next sui_types.CheckpointSequenceNumber) bool {
+	return i.config.last != nil && next > *i.config.last
+}
+
+// prefetch fetches the checkpoints from next concurrently, their results are queued in order.
+func (i *Ingester) prefetch(
+	ctx context.Context,
+	next sui_types.CheckpointSequenceNumber,
+	pending chan<- chan *sui_types.CheckpointData,
+) {
+	defer close(pending)
+	for sequenceNumber := next; !i.done(sequenceNumber); sequenceNumber++ {
+		fetched := make(chan *sui_types.CheckpointData, 1)
+		go func(sequenceNumber sui_types.CheckpointSequenceNumber) {
+			fetched <- i.fetch(ctx, sequenceNumber)
+		}(sequenceNumber)
+		select {
+		case <-ctx.Done():
+			return
+		case pending <- fetched:
+		}
+	}
+}
+
+// fetch retries until the checkpoint is fetched, it returns nil once ctx is done.
+func (i *Ingester) fetch(ctx context.Context, sequenceNumber sui_types.CheckpointSequenceNumber) *sui_types.CheckpointData {
+	delay := i.config.minRetryDelay
+	for {
+		checkpoint, err := i.source.Fetch(ctx, sequenceNumber)
+		if err == nil && checkpoint.SequenceNumber() != sequenceNumber {
+			err = fmt.Errorf("source returned checkpoint %d", checkpoint.SequenceNumber())
+		}
+		if err == nil {
+			return checkpoint
+		}
+		wait := i.config.pollInterval
+		if !errors.Is(err, ErrCheckpointNotAvailable) {
+			i.config.onError(fmt.Errorf("fetch checkpoint %d: %w", sequenceNumber, err))
+			wait = delay
+			delay = nextDelay(delay, i.config.maxRetryDelay)
+		}
+		if sleepContext(ctx, wait) != nil {
+			return nil
+		}
+	}
+}
+
+// dispatch processes checkpoint with the handlers concurrently, each one is retried until it
+// succeeds and its watermark is saved.
+func (i *Ingester) dispatch(
+	ctx context.Context,
+	checkpoint *sui_types.CheckpointData,
+	watermarks []sui_types.CheckpointSequenceNumber,
+) error {
+	sequenceNumber := checkpoint.SequenceNumber()
+	var wg sync.WaitGroup
+	for j, handler := range i.handlers {
+		if watermarks[j] > sequenceNumber {
+			continue
+		}
+		wg.Add(1)
+		go func(j int, handler Handler) {
+			defer wg.Done()
+			if i.process(ctx, handler, checkpoint) {
+				watermarks[j] = sequenceNumber + 1
+			}
+		}(j, handler)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// process retries handler until it processed checkpoint and saved its watermark, it returns false
+// once ctx is done.
+func (i *Ingester) process(ctx context.Context, handler Handler, checkpoint *sui_types.CheckpointData) bool {
+	sequenceNumber := checkpoint.SequenceNumber()
+	delay := i.config.minRetryDelay
+	processed := false
+	for {
+		var err error
+		if !processed {
+			if err = handler.Process(ctx, checkpoint); err == nil {
+				processed = true
+			} else {
+				err = fmt.Errorf("handler %s checkpoint %d: %w", handler.Name(), sequenceNumber, err)
+			}
+		}
+		if processed {
+			if err = i.store.Save(ctx, handler.Name(), sequenceNumber+1); err == nil {
+				return true
+			}
+			err = fmt.Errorf("save watermark of %s: %w", handler.Name(), err)
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		i.config.onError(err)
+		if sleepContext(ctx, delay) != nil {
+			return false
+		}
+		delay = nextDelay(delay, i.config.maxRetryDelay)
+	}
+}
+
+func nextDelay(delay, maxDelay time.Duration) time.Duration {
+	delay *= 2
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,184 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+func checkpointBlob(t *testing.T, sequenceNumber sui_types.CheckpointSequenceNumber) []byte {
+	checkpoint := sui_types.CheckpointData{
+		CheckpointSummary: sui_types.CertifiedCheckpointSummary{
+			Data: sui_types.CheckpointSummary{SequenceNumber: sequenceNumber, ContentDigest: make([]byte, 32)},
+		},
+		CheckpointContents: sui_types.CheckpointContents{
+			V1: &struct {
+				Transactions   []sui_types.ExecutionDigests
+				UserSignatures [][]lib.Base64Data
+			}{},
+		},
+	}
+	data, err := bcs.Marshal(checkpoint)
+	require.NoError(t, err)
+	return append([]byte{sui_types.BlobEncodingBcs}, data...)
+}
+
+func writeCheckpoints(t *testing.T, dir string, from, to sui_types.CheckpointSequenceNumber) {
+	for sequenceNumber := from; sequenceNumber <= to; sequenceNumber++ {
+		path := filepath.Join(dir, CheckpointFileName(sequenceNumber))
+		require.NoError(t, os.WriteFile(path, checkpointBlob(t, sequenceNumber), 0o644))
+	}
+}
+
+// recordingHandler records the checkpoints it processed, it fails once on failOnce.
+type recordingHandler struct {
+	name      string
+	lock      sync.Mutex
+	processed []sui_types.CheckpointSequenceNumber
+	failOnce  map[sui_types.CheckpointSequenceNumber]bool
+}
+
+func (h *recordingHandler) Name() string {
+	return h.name
+}
+
+func (h *recordingHandler) Process(ctx context.Context, checkpoint *sui_types.CheckpointData) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	sequenceNumber := checkpoint.SequenceNumber()
+	if h.failOnce[sequenceNumber] {
+		delete(h.failOnce, sequenceNumber)
+		return errors.New("database unavailable")
+	}
+	h.processed = append(h.processed, sequenceNumber)
+	return nil
+}
+
+func (h *recordingHandler) sequenceNumbers() []sui_types.CheckpointSequenceNumber {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return append([]sui_types.CheckpointSequenceNumber(nil), h.processed...)
+}
+
+func TestIngester_Run(t *testing.T) {
+	dir := t.TempDir()
+	writeCheckpoints(t, dir, 5, 9)
+	store := NewFileWatermarkStore(filepath.Join(dir, "watermarks.json"))
+	ctx := context.Background()
+	require.NoError(t, store.Save(ctx, "events", 7))
+
+	transactions := &recordingHandler{name: "transactions", failOnce: map[uint64]bool{6: true}}
+	events := &recordingHandler{name: "events"}
+	var errs []error
+	var errsLock sync.Mutex
+	ingester := NewIngester(
+		NewDirectorySource(dir), store,
+		WithLastCheckpoint(9),
+		WithRetryDelay(time.Millisecond, 10*time.Millisecond),
+		WithPrefetch(2),
+		WithOnError(
+			func(err error) {
+				errsLock.Lock()
+				defer errsLock.Unlock()
+				errs = append(errs, err)
+			},
+		),
+	)
+	ingester.Register(transactions)
+	ingester.Register(events)
+	require.NoError(t, ingester.Run(ctx, 5))
+
+	require.Equal(t, []uint64{5, 6, 7, 8, 9}, transactions.sequenceNumbers())
+	require.Equal(t, []uint64{7, 8, 9}, events.sequenceNumbers())
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "handler transactions checkpoint 6: database unavailable")
+	for _, name := range []string{"transactions", "events"} {
+		next, ok, err := store.Load(ctx, name)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, uint64(10), next)
+	}
+
+	// a restart resumes from the watermarks, a new handler starts from start
+	writeCheckpoints(t, dir, 10, 11)
+	late := &recordingHandler{name: "late"}
+	ingester = NewIngester(NewDirectorySource(dir), store, WithLastCheckpoint(11))
+	ingester.Register(transactions)
+	ingester.Register(late)
+	require.NoError(t, ingester.Run(ctx, 9))
+	require.Equal(t, []uint64{5, 6, 7, 8, 9, 10, 11}, transactions.sequenceNumbers())
+	require.Equal(t, []uint64{9, 10, 11}, late.sequenceNumbers())
+}
+
+func TestIngester_WaitsForCheckpoints(t *testing.T) {
+	dir := t.TempDir()
+	writeCheckpoints(t, dir, 0, 0)
+	store := NewMemoryWatermarkStore()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	processed := make(chan uint64, 10)
+	ingester := NewIngester(NewDirectorySource(dir), store, WithPollInterval(5*time.Millisecond))
+	ingester.Register(
+		NewHandlerFunc(
+			"tip", func(ctx context.Context, checkpoint *sui_types.CheckpointData) error {
+				processed <- checkpoint.SequenceNumber()
+				return nil
+			},
+		),
+	)
+	done := make(chan error, 1)
+	go func() {
+		done <- ingester.Run(ctx, 0)
+	}()
+
+	require.Equal(t, uint64(0), <-processed)
+	writeCheckpoints(t, dir, 1, 1)
+	require.Equal(t, uint64(1), <-processed)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	next, ok, err := store.Load(context.Background(), "tip")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(2), next)
+}
+
+func TestRemoteStoreSource(t *testing.T) {
+	blob := checkpointBlob(t, 3)
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/3.chk":
+					_, _ = w.Write(blob)
+				case "/4.chk":
+					w.WriteHeader(http.StatusNotFound)
+				default:
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			},
+		),
+	)
+	defer server.Close()
+	source := NewRemoteStoreSource(server.URL+"/", nil)
+	ctx := context.Background()
+
+	checkpoint, err := source.Fetch(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), checkpoint.SequenceNumber())
+	_, err = source.Fetch(ctx, 4)
+	require.ErrorIs(t, err, ErrCheckpointNotAvailable)
+	_, err = source.Fetch(ctx, 5)
+	require.ErrorContains(t, err, "503")
+}
@@ -0,0 +1,106 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/thorli9527/sui-wallet-sdk/client"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+const (
+	MainnetCheckpointStoreUrl = "https://checkpoints.mainnet.sui.io"
+	TestnetCheckpointStoreUrl = "https://checkpoints.testnet.sui.io"
+)
+
+// ErrCheckpointNotAvailable is returned by a Source for a checkpoint it does not have yet.
+var ErrCheckpointNotAvailable = errors.New("checkpoint not available yet")
+
+// Source returns the full contents of a checkpoint. A streaming API can be consumed by a Source
+// returning the checkpoints it received, and ErrCheckpointNotAvailable for those it did not.
+type Source interface {
+	Fetch(ctx context.Context, sequenceNumber sui_types.CheckpointSequenceNumber) (*sui_types.CheckpointData, error)
+}
+
+// CheckpointFileName is the name of the blob file of a checkpoint, in a directory or a remote store.
+func CheckpointFileName(sequenceNumber sui_types.CheckpointSequenceNumber) string {
+	return fmt.Sprintf("%d.chk", sequenceNumber)
+}
+
+// DirectorySource reads the checkpoint blob files a full node writes to its checkpoint
+// ingestion directory.
+type DirectorySource struct {
+	dir string
+}
+
+func NewDirectorySource(dir string) *DirectorySource {
+	return &DirectorySource{dir: dir}
+}
+
+func (s *DirectorySource) Fetch(
+	ctx context.Context,
+	sequenceNumber sui_types.CheckpointSequenceNumber,
+) (*sui_types.CheckpointData, error) {
+	blob, err := os.ReadFile(filepath.Join(s.dir, CheckpointFileName(sequenceNumber)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrCheckpointNotAvailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sui_types.DecodeCheckpointData(blob)
+}
+
+// RemoteStoreSource downloads the checkpoint blob files of a remote checkpoint store, like
+// MainnetCheckpointStoreUrl.
+type RemoteStoreSource struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewRemoteStoreSource reads the store at url with httpClient, a client with a 30s timeout if it
+// is nil.
+func NewRemoteStoreSource(url string, httpClient *http.Client) *RemoteStoreSource {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &RemoteStoreSource{url: strings.TrimSuffix(url, "/"), httpClient: httpClient}
+}
+
+func (s *RemoteStoreSource) Fetch(
+	ctx context.Context,
+	sequenceNumber sui_types.CheckpointSequenceNumber,
+) (*sui_types.CheckpointData, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+"/"+CheckpointFileName(sequenceNumber), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrCheckpointNotAvailable
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, client.HTTPError{
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       body,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sui_types.DecodeCheckpointData(body)
+}
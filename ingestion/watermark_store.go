@@ -0,0 +1,111 @@
+package ingestion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+// WatermarkStore persists the progress of every handler, the sequence number of the next
+// checkpoint it has to process.
+type WatermarkStore interface {
+	// Load returns the saved watermark of handler, ok is false if nothing was saved yet.
+	Load(ctx context.Context, handler string) (next sui_types.CheckpointSequenceNumber, ok bool, err error)
+	Save(ctx context.Context, handler string, next sui_types.CheckpointSequenceNumber) error
+}
+
+type MemoryWatermarkStore struct {
+	lock       sync.Mutex
+	watermarks map[string]sui_types.CheckpointSequenceNumber
+}
+
+func NewMemoryWatermarkStore() *MemoryWatermarkStore {
+	return &MemoryWatermarkStore{watermarks: map[string]sui_types.CheckpointSequenceNumber{}}
+}
+
+func (s *MemoryWatermarkStore) Load(ctx context.Context, handler string) (sui_types.CheckpointSequenceNumber, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	next, ok := s.watermarks[handler]
+	return next, ok, nil
+}
+
+func (s *MemoryWatermarkStore) Save(ctx context.Context, handler string, next sui_types.CheckpointSequenceNumber) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.watermarks[handler] = next
+	return nil
+}
+
+// FileWatermarkStore keeps the watermarks of all the handlers as json in a file, it is replaced
+// atomically on every save.
+type FileWatermarkStore struct {
+	lock sync.Mutex
+	path string
+}
+
+func NewFileWatermarkStore(path string) *FileWatermarkStore {
+	return &FileWatermarkStore{path: path}
+}
+
+func (s *FileWatermarkStore) Load(ctx context.Context, handler string) (sui_types.CheckpointSequenceNumber, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	watermarks, err := s.read()
+	if err != nil {
+		return 0, false, err
+	}
+	next, ok := watermarks[handler]
+	return next, ok, nil
+}
+
+func (s *FileWatermarkStore) Save(ctx context.Context, handler string, next sui_types.CheckpointSequenceNumber) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	watermarks, err := s.read()
+	if err != nil {
+		return err
+	}
+	watermarks[handler] = next
+	data, err := json.Marshal(watermarks)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *FileWatermarkStore) read() (map[string]sui_types.CheckpointSequenceNumber, error) {
+	watermarks := map[string]sui_types.CheckpointSequenceNumber{}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return watermarks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &watermarks); err != nil {
+		return nil, err
+	}
+	return watermarks, nil
+}
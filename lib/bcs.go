@@ -0,0 +1,238 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fardream/go-bcs/bcs"
+)
+
+var (
+	ErrBcsTrailingBytes = errors.New("bcs: trailing bytes after decoded value")
+
+	uint128Type = reflect.TypeOf(bcs.Uint128{})
+)
+
+// UnmarshalBCS decodes data into v, v must be a non-nil pointer.
+//
+// It follows the same layout conventions as bcs.Marshal, so the sui_types structures
+// can be round-tripped: struct fields are decoded in order, `bcs:"optional"` pointers
+// are prefixed with an option byte, types implementing bcs.Enum are decoded as a
+// ULEB128 variant index followed by the variant's pointer field, and types implementing
+// bcs.Unmarshaler decode themselves. Maps are decoded from a sequence of key value pairs, like
+// a rust BTreeMap. All of data must be consumed.
+func UnmarshalBCS(data []byte, v any) error {
+	r := bytes.NewReader(data)
+	if err := NewBcsDecoder(r).Decode(v); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return ErrBcsTrailingBytes
+	}
+	return nil
+}
+
+type BcsDecoder struct {
+	r io.Reader
+}
+
+func NewBcsDecoder(r io.Reader) *BcsDecoder {
+	return &BcsDecoder{r: r}
+}
+
+func (d *BcsDecoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("bcs: decode target must be a non-nil pointer, got %T", v)
+	}
+	return d.decode(rv.Elem())
+}
+
+func (d *BcsDecoder) decode(v reflect.Value) error {
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(bcs.Unmarshaler); ok {
+			_, err := u.UnmarshalBCS(d.r)
+			return err
+		}
+		if _, ok := v.Addr().Interface().(bcs.Enum); ok && v.Kind() == reflect.Struct {
+			return d.decodeEnum(v)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := d.readByte()
+		if err != nil {
+			return err
+		}
+		switch b {
+		case 0:
+			v.SetBool(false)
+		case 1:
+			v.SetBool(true)
+		default:
+			return fmt.Errorf("bcs: invalid bool value %d", b)
+		}
+		return nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.Read(d.r, binary.LittleEndian, v.Addr().Interface())
+	case reflect.String:
+		data, err := d.readBytes()
+		if err != nil {
+			return err
+		}
+		if !utf8.Valid(data) {
+			return errors.New("bcs: string is not valid utf8")
+		}
+		v.SetString(string(data))
+		return nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decode(v.Elem())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			data, err := d.readBytes()
+			if err != nil {
+				return err
+			}
+			v.SetBytes(data)
+			return nil
+		}
+		length, err := d.readLength()
+		if err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(v.Type(), length, length)
+		for i := 0; i < length; i++ {
+			if err := d.decode(slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	case reflect.Map:
+		// a map is a sequence of key value pairs, like a rust BTreeMap
+		length, err := d.readLength()
+		if err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(v.Type(), length)
+		for i := 0; i < length; i++ {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := d.decode(key); err != nil {
+				return err
+			}
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decode(value); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, value)
+		}
+		v.Set(m)
+		return nil
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := d.decode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		if v.Type() == uint128Type {
+			var parts [2]uint64
+			if err := binary.Read(d.r, binary.LittleEndian, &parts); err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(*bcs.NewUint128FromUint64(parts[0], parts[1])))
+			return nil
+		}
+		return d.decodeStruct(v)
+	default:
+		return fmt.Errorf("bcs: unsupported kind %s", v.Kind())
+	}
+}
+
+func (d *BcsDecoder) decodeStruct(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("bcs")
+		if tag == "-" {
+			continue
+		}
+		if strings.Contains(tag, "optional") {
+			flag, err := d.readByte()
+			if err != nil {
+				return err
+			}
+			switch flag {
+			case 0:
+				v.Field(i).Set(reflect.Zero(field.Type))
+				continue
+			case 1:
+			default:
+				return fmt.Errorf("bcs: invalid option flag %d for field %s", flag, field.Name)
+			}
+		}
+		if err := d.decode(v.Field(i)); err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+	}
+	return nil
+}
+
+func (d *BcsDecoder) decodeEnum(v reflect.Value) error {
+	variant, err := d.readLength()
+	if err != nil {
+		return err
+	}
+	if variant >= v.NumField() {
+		return fmt.Errorf("bcs: variant index %d out of range for %s", variant, v.Type().Name())
+	}
+	field := v.Field(variant)
+	if field.Kind() != reflect.Pointer {
+		return fmt.Errorf("bcs: enum variant %s must be a pointer", v.Type().Field(variant).Name)
+	}
+	field.Set(reflect.New(field.Type().Elem()))
+	return d.decode(field.Elem())
+}
+
+func (d *BcsDecoder) readByte() (byte, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(d.r, b[:]); err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *BcsDecoder) readLength() (int, error) {
+	length, _, err := bcs.ULEB128Decode[uint32](d.r)
+	if err != nil {
+		return 0, err
+	}
+	return int(length), nil
+}
+
+func (d *BcsDecoder) readBytes() ([]byte, error) {
+	length, err := d.readLength()
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(d.r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,73 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+)
+
+type bcsTestEnum struct {
+	Empty *EmptyEnum
+	Num   *uint32
+	Bytes *[]byte
+}
+
+func (b bcsTestEnum) IsBcsEnum() {
+}
+
+type bcsTestStruct struct {
+	Flag     bool
+	Small    uint8
+	Number   uint64
+	Name     string
+	Data     []byte
+	Fixed    [4]uint8
+	List     []uint16
+	Enums    []bcsTestEnum
+	Optional *uint64 `bcs:"optional"`
+	Missing  *string `bcs:"optional"`
+	Big      bcs.Uint128
+	ignored  int
+}
+
+func TestUnmarshalBCS(t *testing.T) {
+	num := uint32(7)
+	opt := uint64(1 << 40)
+	in := bcsTestStruct{
+		Flag:     true,
+		Small:    0xab,
+		Number:   1234567890123,
+		Name:     "sui 水",
+		Data:     []byte{1, 2, 3},
+		Fixed:    [4]uint8{4, 5, 6, 7},
+		List:     []uint16{1, 300, 65535},
+		Enums:    []bcsTestEnum{{Empty: &EmptyEnum{}}, {Num: &num}, {Bytes: &[]byte{9}}},
+		Optional: &opt,
+		Big:      *bcs.NewUint128FromUint64(1, 2),
+	}
+	data, err := bcs.Marshal(in)
+	require.NoError(t, err)
+
+	var out bcsTestStruct
+	err = UnmarshalBCS(data, &out)
+	require.NoError(t, err)
+	require.Equal(t, in, out)
+
+	err = UnmarshalBCS(append(data, 0), &out)
+	require.ErrorIs(t, err, ErrBcsTrailingBytes)
+
+	err = UnmarshalBCS(data[:len(data)-1], &out)
+	require.Error(t, err)
+
+	err = UnmarshalBCS(data, out)
+	require.Error(t, err)
+}
+
+func TestUnmarshalBCS_Map(t *testing.T) {
+	// bcs.Marshal does not encode maps, the bytes are those of a rust BTreeMap<String, Vec<u8>>
+	data := []byte{2, 1, 'a', 2, 1, 2, 1, 'b', 0}
+	var out map[string][]byte
+	require.NoError(t, UnmarshalBCS(data, &out))
+	require.Equal(t, map[string][]byte{"a": {1, 2}, "b": {}}, out)
+}
@@ -0,0 +1,674 @@
+package mocknode
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+	"golang.org/x/crypto/blake2b"
+)
+
+// transaction is an executed transaction.
+type transaction struct {
+	digest         sui_types.TransactionDigest
+	data           *sui_types.TransactionDataV1
+	signatures     []lib.Base64Data
+	effects        wireEffects
+	objectChanges  []wireObjectChange
+	balanceChanges []wireBalanceChange
+	checkpoint     uint64
+	timestampMs    uint64
+	// inputs are the objects the transaction took, changed those it wrote or deleted and
+	// recipients the other addresses which own one of them after it.
+	inputs     []sui_types.ObjectID
+	changed    []sui_types.ObjectID
+	recipients []sui_types.SuiAddress
+}
+
+func executionErrorf(format string, args ...interface{}) *Error {
+	return errorf(CodeTransactionExecution, format, args...)
+}
+
+func objectId(seed []byte, index uint64) sui_types.ObjectID {
+	hash := sui_types.NewDefaultHash()
+	hash.Write(seed)
+	_ = binary.Write(hash, binary.LittleEndian, index)
+	var id sui_types.ObjectID
+	copy(id[:], hash.Sum(nil))
+	return id
+}
+
+func decodeTransaction(txBytes []byte) (*sui_types.TransactionDataV1, error) {
+	var data sui_types.TransactionData
+	if err := lib.UnmarshalBCS(txBytes, &data); err != nil {
+		return nil, fmt.Errorf("invalid transaction bytes: %w", err)
+	}
+	if data.V1 == nil || data.V1.Kind.ProgrammableTransaction == nil {
+		return nil, fmt.Errorf("only programmable transactions are supported")
+	}
+	for _, command := range data.V1.Kind.ProgrammableTransaction.Commands {
+		if command.SplitCoins == nil && command.MergeCoins == nil && command.TransferObjects == nil {
+			return nil, fmt.Errorf("only the SplitCoins, MergeCoins and TransferObjects commands are supported")
+		}
+	}
+	return data.V1, nil
+}
+
+// verifySignatures checks that the sender and the gas owner signed the transaction. The ed25519
+// signatures are verified, the signers of the other schemes are only derived from their keys.
+func verifySignatures(txBytes []byte, data *sui_types.TransactionDataV1, signatures []lib.Base64Data) error {
+	message := blake2b.Sum256(append([]byte{0, 0, 0}, txBytes...))
+	signers := map[sui_types.SuiAddress]bool{}
+	for _, signature := range signatures {
+		var publicKey []byte
+		switch {
+		case len(signature) == 1+ed25519.SignatureSize+ed25519.PublicKeySize && signature[0] == 0:
+			publicKey = signature[1+ed25519.SignatureSize:]
+			if !ed25519.Verify(publicKey, message[:], signature[1:1+ed25519.SignatureSize]) {
+				return executionErrorf("Invalid user signature: Signature is not valid")
+			}
+		case len(signature) == 1+64+33 && (signature[0] == 1 || signature[0] == 2):
+			publicKey = signature[1+64:]
+		default:
+			return executionErrorf("Invalid user signature: unsupported signature %s", signature)
+		}
+		signers[sui_types.SuiAddress(blake2b.Sum256(append([]byte{signature[0]}, publicKey...)))] = true
+	}
+	for _, required := range []sui_types.SuiAddress{data.Sender, data.GasData.Owner} {
+		if !signers[required] {
+			return executionErrorf("Invalid user signature: Required Signature from %s is absent", required)
+		}
+	}
+	return nil
+}
+
+// execution is a transaction being executed on copies of its input objects.
+type execution struct {
+	node   *Node
+	data   *sui_types.TransactionDataV1
+	digest sui_types.TransactionDigest
+	// loaded are the input versions of the objects, in the order of order, the gas coin first.
+	loaded  map[sui_types.ObjectID]*object
+	order   []sui_types.ObjectID
+	gas     sui_types.ObjectID
+	state   map[sui_types.ObjectID]*object
+	created []sui_types.ObjectID
+	results [][]sui_types.ObjectID
+}
+
+// prepare validates the inputs and the gas of the transaction like a validator signing it.
+func (n *Node) prepare(data *sui_types.TransactionDataV1, digest sui_types.TransactionDigest) (*execution, error) {
+	if data.Expiration.Epoch != nil && *data.Expiration.Epoch < n.config.epoch {
+		return nil, executionErrorf("TransactionExpired")
+	}
+	gasData := data.GasData
+	if gasData.Price < n.config.referenceGasPrice {
+		return nil, executionErrorf(
+			"GasPriceUnderRGP { gas_price: %d, reference_gas_price: %d }", gasData.Price, n.config.referenceGasPrice,
+		)
+	}
+	if len(gasData.Payment) == 0 {
+		return nil, executionErrorf("MissingGasPayment")
+	}
+	x := &execution{node: n, data: data, digest: digest, loaded: map[sui_types.ObjectID]*object{}}
+	x.gas = gasData.Payment[0].ObjectId
+	gasBalance := uint64(0)
+	for _, ref := range gasData.Payment {
+		o, err := x.load(ref, gasData.Owner)
+		if err != nil {
+			return nil, err
+		}
+		if o.coinType != types.SUI_COIN_TYPE {
+			return nil, executionErrorf("InvalidGasObject { object_id: %s }", o.id)
+		}
+		gasBalance += o.balance
+	}
+	for _, input := range data.Kind.ProgrammableTransaction.Inputs {
+		if input.Object == nil {
+			continue
+		}
+		if input.Object.ImmOrOwnedObject == nil {
+			return nil, fmt.Errorf("only owned object inputs are supported")
+		}
+		if _, err := x.load(input.Object.ImmOrOwnedObject, data.Sender); err != nil {
+			return nil, err
+		}
+	}
+	minBudget := x.computationCost() + n.config.storageCost*uint64(len(x.order)-len(gasData.Payment)+1)
+	if gasData.Budget < minBudget {
+		return nil, executionErrorf("GasBudgetTooLow { gas_budget: %d, min_budget: %d }", gasData.Budget, minBudget)
+	}
+	if gasBalance < gasData.Budget {
+		return nil, executionErrorf(
+			"GasBalanceTooLow { gas_balance: %d, needed_gas_amount: %d }", gasBalance, gasData.Budget,
+		)
+	}
+	return x, nil
+}
+
+func (x *execution) load(ref *sui_types.ObjectRef, owner sui_types.SuiAddress) (*object, error) {
+	if _, ok := x.loaded[ref.ObjectId]; ok {
+		return nil, executionErrorf("DuplicateObjectRefInput { object_id: %s }", ref.ObjectId)
+	}
+	o := x.node.latest(ref.ObjectId)
+	if o == nil {
+		return nil, executionErrorf(
+			"ObjectNotFound { object_id: %s, version: Some(SequenceNumber(%d)) }", ref.ObjectId, ref.Version,
+		)
+	}
+	if o.version != ref.Version || !bytes.Equal(o.digest, ref.Digest) {
+		return nil, executionErrorf(
+			"ObjectVersionUnavailableForConsumption { provided_obj_ref: (%s, SequenceNumber(%d), o#%s), "+
+				"current_version: SequenceNumber(%d) }", ref.ObjectId, ref.Version, ref.Digest, o.version,
+		)
+	}
+	if o.owner != owner {
+		return nil, executionErrorf(
+			"IncorrectUserSignature { error: \"Object %s is owned by account address %s, but given owner/signer "+
+				"address is %s\" }", o.id, o.owner, owner,
+		)
+	}
+	x.loaded[o.id] = o
+	x.order = append(x.order, o.id)
+	return o, nil
+}
+
+func (x *execution) computationCost() uint64 {
+	return x.data.GasData.Price * x.node.config.computationUnits
+}
+
+// reset puts the inputs back to their input versions and smashes the gas coins into the first one.
+func (x *execution) reset() {
+	x.state = map[sui_types.ObjectID]*object{}
+	for id, o := range x.loaded {
+		x.state[id] = o.clone()
+	}
+	x.created = nil
+	x.results = nil
+	gas := x.state[x.gas]
+	for _, ref := range x.data.GasData.Payment[1:] {
+		gas.balance += x.state[ref.ObjectId].balance
+		x.state[ref.ObjectId].deleted = true
+	}
+}
+
+// run executes the commands and returns the status. A failed command, or a transaction out of
+// gas, reverts all the changes but the smashing of the gas coins.
+func (x *execution) run() types.ExecutionStatus {
+	status := types.ExecutionStatus{Status: types.ExecutionStatusSuccess}
+	x.reset()
+	budget := x.data.GasData.Budget
+	x.state[x.gas].balance -= budget
+	for i, command := range x.data.Kind.ProgrammableTransaction.Commands {
+		if failure := x.command(command); failure != "" {
+			status = types.ExecutionStatus{Status: types.ExecutionStatusFailure, Error: fmt.Sprintf("%s in command %d", failure, i)}
+			x.reset()
+			x.state[x.gas].balance -= budget
+			break
+		}
+	}
+	x.state[x.gas].balance += budget
+	if x.computationCost()+x.storageCost() > budget {
+		status = types.ExecutionStatus{Status: types.ExecutionStatusFailure, Error: "InsufficientGas"}
+		x.reset()
+	}
+	return status
+}
+
+// written returns the ids of the objects the transaction writes, the inputs then the new objects.
+func (x *execution) written() []sui_types.ObjectID {
+	var ids []sui_types.ObjectID
+	for _, id := range append(append([]sui_types.ObjectID{}, x.order...), x.created...) {
+		if !x.state[id].deleted {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (x *execution) storageCost() uint64 {
+	return x.node.config.storageCost * uint64(len(x.written()))
+}
+
+func (x *execution) storageRebate() uint64 {
+	rebate := uint64(0)
+	for _, o := range x.loaded {
+		rebate += o.storageRebate
+	}
+	return rebate
+}
+
+func commandArgumentError(index int, kind string) string {
+	return fmt.Sprintf("CommandArgumentError { arg_idx: %d, kind: %s }", index, kind)
+}
+
+// object resolves the argument index of a command to an object, or returns the failure.
+func (x *execution) object(argument sui_types.Argument, index int) (*object, string) {
+	var id sui_types.ObjectID
+	inputs := x.data.Kind.ProgrammableTransaction.Inputs
+	switch {
+	case argument.GasCoin != nil:
+		id = x.gas
+	case argument.Input != nil:
+		if int(*argument.Input) >= len(inputs) {
+			return nil, commandArgumentError(index, fmt.Sprintf("IndexOutOfBounds { idx: %d }", *argument.Input))
+		}
+		input := inputs[*argument.Input]
+		if input.Object == nil {
+			return nil, commandArgumentError(index, "TypeMismatch")
+		}
+		id = input.Object.ImmOrOwnedObject.ObjectId
+	case argument.Result != nil:
+		if int(*argument.Result) >= len(x.results) {
+			return nil, commandArgumentError(index, fmt.Sprintf("IndexOutOfBounds { idx: %d }", *argument.Result))
+		}
+		result := x.results[*argument.Result]
+		if len(result) != 1 {
+			return nil, commandArgumentError(index, fmt.Sprintf("InvalidResultArity { result_idx: %d }", *argument.Result))
+		}
+		id = result[0]
+	case argument.NestedResult != nil:
+		nested := argument.NestedResult
+		if int(nested.Result1) >= len(x.results) {
+			return nil, commandArgumentError(index, fmt.Sprintf("IndexOutOfBounds { idx: %d }", nested.Result1))
+		}
+		result := x.results[nested.Result1]
+		if int(nested.Result2) >= len(result) {
+			return nil, commandArgumentError(
+				index, fmt.Sprintf(
+					"SecondaryIndexOutOfBounds { result_idx: %d, secondary_idx: %d }", nested.Result1, nested.Result2,
+				),
+			)
+		}
+		id = result[nested.Result2]
+	}
+	o := x.state[id]
+	if o == nil || o.deleted {
+		return nil, commandArgumentError(index, "InvalidValueUsage")
+	}
+	return o, ""
+}
+
+func (x *execution) pure(argument sui_types.Argument, index int, size int) ([]byte, string) {
+	inputs := x.data.Kind.ProgrammableTransaction.Inputs
+	if argument.Input == nil || int(*argument.Input) >= len(inputs) || inputs[*argument.Input].Pure == nil {
+		return nil, commandArgumentError(index, "TypeMismatch")
+	}
+	value := *inputs[*argument.Input].Pure
+	if len(value) != size {
+		return nil, commandArgumentError(index, "InvalidBCSBytes")
+	}
+	return value, ""
+}
+
+func (x *execution) coin(argument sui_types.Argument, index int) (*object, string) {
+	o, failure := x.object(argument, index)
+	if failure != "" {
+		return nil, failure
+	}
+	if o.coinType == "" {
+		return nil, commandArgumentError(index, "TypeMismatch")
+	}
+	return o, ""
+}
+
+// command executes a command, it returns the failure status without the command index.
+func (x *execution) command(command sui_types.Command) string {
+	var result []sui_types.ObjectID
+	switch {
+	case command.SplitCoins != nil:
+		coin, failure := x.coin(command.SplitCoins.Argument, 0)
+		if failure != "" {
+			return failure
+		}
+		amounts := make([]uint64, len(command.SplitCoins.Arguments))
+		total := uint64(0)
+		for i, argument := range command.SplitCoins.Arguments {
+			value, failure := x.pure(argument, i+1, 8)
+			if failure != "" {
+				return failure
+			}
+			amounts[i] = binary.LittleEndian.Uint64(value)
+			if total+amounts[i] < total {
+				return "InsufficientCoinBalance"
+			}
+			total += amounts[i]
+		}
+		if coin.balance < total {
+			return "InsufficientCoinBalance"
+		}
+		for _, amount := range amounts {
+			coin.balance -= amount
+			split := &object{
+				id:         objectId(x.digest, uint64(len(x.created))),
+				owner:      x.data.Sender,
+				objectType: coin.objectType,
+				coinType:   coin.coinType,
+				balance:    amount,
+			}
+			x.state[split.id] = split
+			x.created = append(x.created, split.id)
+			result = append(result, split.id)
+		}
+	case command.MergeCoins != nil:
+		target, failure := x.coin(command.MergeCoins.Argument, 0)
+		if failure != "" {
+			return failure
+		}
+		for i, argument := range command.MergeCoins.Arguments {
+			source, failure := x.coin(argument, i+1)
+			if failure != "" {
+				return failure
+			}
+			switch {
+			case source.coinType != target.coinType:
+				return commandArgumentError(i+1, "TypeMismatch")
+			case source == target:
+				return commandArgumentError(i+1, "InvalidValueUsage")
+			case source.id == x.gas:
+				return commandArgumentError(i+1, "InvalidGasCoinUsage")
+			case target.balance+source.balance < target.balance:
+				return "CoinBalanceOverflow"
+			}
+			target.balance += source.balance
+			source.deleted = true
+		}
+	case command.TransferObjects != nil:
+		arguments := command.TransferObjects.Arguments
+		value, failure := x.pure(command.TransferObjects.Argument, len(arguments), len(sui_types.SuiAddress{}))
+		if failure != "" {
+			return failure
+		}
+		var recipient sui_types.SuiAddress
+		copy(recipient[:], value)
+		for i, argument := range arguments {
+			o, failure := x.object(argument, i)
+			if failure != "" {
+				return failure
+			}
+			o.owner = recipient
+		}
+	}
+	x.results = append(x.results, result)
+	return ""
+}
+
+// finish charges the gas, writes the new versions of the objects and returns the transaction.
+func (x *execution) finish(status types.ExecutionStatus) *transaction {
+	n := x.node
+	computation, storage, rebate := x.computationCost(), x.storageCost(), x.storageRebate()
+	gas := x.state[x.gas]
+	gas.balance = gas.balance + rebate - computation - storage
+
+	lamport := sui_types.SequenceNumber(0)
+	for _, o := range x.loaded {
+		if o.version > lamport {
+			lamport = o.version
+		}
+	}
+	lamport++
+	for _, id := range append(append([]sui_types.ObjectID{}, x.order...), x.created...) {
+		o := x.state[id]
+		o.version = lamport
+		o.previousTransaction = x.digest
+		o.storageRebate = n.config.storageCost
+		o.seal()
+	}
+
+	tx := &transaction{digest: x.digest, data: x.data}
+	effects := wireEffects{
+		MessageVersion: "v1",
+		Status:         status,
+		ExecutedEpoch:  stringNumber(n.config.epoch),
+		GasUsed: types.GasCostSummary{
+			ComputationCost: types.NewSafeSuiBigInt(computation),
+			StorageCost:     types.NewSafeSuiBigInt(storage),
+			StorageRebate:   types.NewSafeSuiBigInt(rebate),
+		},
+		TransactionDigest: x.digest,
+		GasObject:         wireOwnedObjectRef{Owner: addressOwner(gas.owner), Reference: suiObjectRef(gas.ref())},
+		Dependencies:      []sui_types.TransactionDigest{},
+	}
+	dependencies := map[string]bool{}
+	recipients := map[sui_types.SuiAddress]bool{}
+	for _, id := range x.order {
+		input, o := x.loaded[id], x.state[id]
+		tx.inputs = append(tx.inputs, id)
+		tx.changed = append(tx.changed, id)
+		effects.ModifiedAtVersions = append(
+			effects.ModifiedAtVersions,
+			types.SuiTransactionBlockEffectsModifiedAtVersions{ObjectId: id, SequenceNumber: types.NewSafeSuiBigInt(input.version)},
+		)
+		previous := input.previousTransaction.String()
+		if _, ok := n.byDigest[previous]; ok && !dependencies[previous] {
+			dependencies[previous] = true
+			effects.Dependencies = append(effects.Dependencies, input.previousTransaction)
+		}
+		if o.deleted {
+			effects.Deleted = append(effects.Deleted, suiObjectRef(o.ref()))
+			tx.objectChanges = append(
+				tx.objectChanges, wireObjectChange{
+					Type: "deleted", Sender: x.data.Sender, ObjectType: o.objectType, ObjectId: id,
+					Version: stringNumber(lamport),
+				},
+			)
+			continue
+		}
+		effects.Mutated = append(effects.Mutated, wireOwnedObjectRef{Owner: addressOwner(o.owner), Reference: suiObjectRef(o.ref())})
+		tx.objectChanges = append(
+			tx.objectChanges, wireObjectChange{
+				Type: "mutated", Sender: x.data.Sender, Owner: addressOwner(o.owner), ObjectType: o.objectType,
+				ObjectId: id, Version: stringNumber(lamport), PreviousVersion: stringNumber(input.version), Digest: o.digest,
+			},
+		)
+		if o.owner != x.data.Sender && !recipients[o.owner] {
+			recipients[o.owner] = true
+			tx.recipients = append(tx.recipients, o.owner)
+		}
+	}
+	for _, id := range x.created {
+		o := x.state[id]
+		if o.deleted {
+			continue
+		}
+		tx.changed = append(tx.changed, id)
+		effects.Created = append(effects.Created, wireOwnedObjectRef{Owner: addressOwner(o.owner), Reference: suiObjectRef(o.ref())})
+		tx.objectChanges = append(
+			tx.objectChanges, wireObjectChange{
+				Type: "created", Sender: x.data.Sender, Owner: addressOwner(o.owner), ObjectType: o.objectType,
+				ObjectId: id, Version: stringNumber(lamport), Digest: o.digest,
+			},
+		)
+		if o.owner != x.data.Sender && !recipients[o.owner] {
+			recipients[o.owner] = true
+			tx.recipients = append(tx.recipients, o.owner)
+		}
+	}
+	tx.effects = effects
+	tx.balanceChanges = x.balanceChanges()
+	return tx
+}
+
+// balanceChanges sums the coins of every owner and coin type before and after the transaction.
+func (x *execution) balanceChanges() []wireBalanceChange {
+	type key struct {
+		owner    sui_types.SuiAddress
+		coinType string
+	}
+	before, after := map[key]uint64{}, map[key]uint64{}
+	var keys []key
+	add := func(sums map[key]uint64, o *object) {
+		if o.coinType == "" || o.deleted {
+			return
+		}
+		k := key{o.owner, o.coinType}
+		if _, ok := before[k]; !ok {
+			if _, ok := after[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sums[k] += o.balance
+	}
+	for _, id := range x.order {
+		add(before, x.loaded[id])
+	}
+	for _, id := range append(append([]sui_types.ObjectID{}, x.order...), x.created...) {
+		add(after, x.state[id])
+	}
+	var changes []wireBalanceChange
+	for _, k := range keys {
+		var amount string
+		switch {
+		case after[k] > before[k]:
+			amount = stringNumber(after[k] - before[k])
+		case after[k] < before[k]:
+			amount = "-" + stringNumber(before[k]-after[k])
+		default:
+			continue
+		}
+		changes = append(changes, wireBalanceChange{Owner: addressOwner(k.owner), CoinType: k.coinType, Amount: amount})
+	}
+	return changes
+}
+
+// commit stores the new versions of the objects of the transaction in a new checkpoint.
+func (x *execution) commit(tx *transaction) {
+	n := x.node
+	for _, id := range append(append([]sui_types.ObjectID{}, x.order...), x.created...) {
+		o := x.state[id]
+		if o.deleted && n.latest(id) == nil {
+			continue
+		}
+		n.objects[id] = append(n.objects[id], o)
+	}
+	n.transactions = append(n.transactions, tx)
+	n.byDigest[tx.digest.String()] = tx
+	checkpoint := n.appendCheckpoint([]sui_types.TransactionDigest{tx.digest}, tx.effects.GasUsed)
+	tx.checkpoint = checkpoint.SequenceNumber.Uint64()
+	tx.timestampMs = checkpoint.TimestampMs.Uint64()
+}
+
+// execute executes and commits the signed transaction, a transaction executed before returns its
+// first execution.
+func (n *Node) execute(txBytes []byte, signatures []lib.Base64Data) (*transaction, error) {
+	data, err := decodeTransaction(txBytes)
+	if err != nil {
+		return nil, err
+	}
+	digest := sui_types.NewTransactionDigestFromBytes(txBytes)
+	if tx, ok := n.byDigest[digest.String()]; ok {
+		return tx, nil
+	}
+	if err := verifySignatures(txBytes, data, signatures); err != nil {
+		return nil, err
+	}
+	x, err := n.prepare(data, digest)
+	if err != nil {
+		return nil, err
+	}
+	tx := x.finish(x.run())
+	tx.signatures = signatures
+	x.commit(tx)
+	return tx, nil
+}
+
+func (n *Node) executeTransactionBlock(p params) (interface{}, error) {
+	var txBytes lib.Base64Data
+	var signatures []lib.Base64Data
+	var options types.SuiTransactionBlockResponseOptions
+	var requestType types.ExecuteTransactionRequestType
+	if err := p.get(0, &txBytes); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &signatures); err != nil {
+		return nil, err
+	}
+	if err := p.get(2, &options); err != nil {
+		return nil, err
+	}
+	if err := p.get(3, &requestType); err != nil {
+		return nil, err
+	}
+	tx, err := n.execute(txBytes, signatures)
+	if err != nil {
+		return nil, err
+	}
+	resp := n.transactionResponse(tx, options)
+	if requestType == types.TxnRequestTypeWaitForLocalExecution {
+		confirmed := true
+		resp.ConfirmedLocalExecution = &confirmed
+	}
+	return resp, nil
+}
+
+func (n *Node) dryRunTransactionBlock(p params) (interface{}, error) {
+	var txBytes lib.Base64Data
+	if err := p.get(0, &txBytes); err != nil {
+		return nil, err
+	}
+	data, err := decodeTransaction(txBytes)
+	if err != nil {
+		return nil, err
+	}
+	x, err := n.prepare(data, sui_types.NewTransactionDigestFromBytes(txBytes))
+	if err != nil {
+		return nil, err
+	}
+	tx := x.finish(x.run())
+	return wireDryRunResponse{
+		Effects:        tx.effects,
+		Events:         []types.SuiEvent{},
+		ObjectChanges:  nonNil(tx.objectChanges),
+		BalanceChanges: nonNil(tx.balanceChanges),
+		Input:          wireData(data),
+	}, nil
+}
+
+func nonNil[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+	return values
+}
+
+// appendCheckpoint creates the next checkpoint with the transactions.
+func (n *Node) appendCheckpoint(transactions []sui_types.TransactionDigest, gasUsed types.GasCostSummary) types.Checkpoint {
+	checkpoint := types.Checkpoint{
+		Epoch:                 types.NewSafeSuiBigInt(n.config.epoch),
+		SequenceNumber:        types.NewSafeSuiBigInt(uint64(len(n.checkpoints))),
+		TimestampMs:           types.NewSafeSuiBigInt(n.now()),
+		Transactions:          nonNil(transactions),
+		CheckpointCommitments: []types.CheckpointCommitment{},
+		ValidatorSignature:    make([]byte, 48),
+	}
+	rolling := gasUsed
+	total := uint64(len(transactions))
+	hash := sui_types.NewDefaultHash()
+	hash.Write([]byte("CheckpointSummary::"))
+	_ = binary.Write(hash, binary.LittleEndian, checkpoint.SequenceNumber.Uint64())
+	if len(n.checkpoints) > 0 {
+		previous := n.checkpoints[len(n.checkpoints)-1]
+		checkpoint.PreviousDigest = &previous.Digest
+		hash.Write(previous.Digest)
+		total += previous.NetworkTotalTransactions.Uint64()
+		summary := previous.EpochRollingGasCostSummary
+		rolling = types.GasCostSummary{
+			ComputationCost:         types.NewSafeSuiBigInt(summary.ComputationCost.Uint64() + gasUsed.ComputationCost.Uint64()),
+			StorageCost:             types.NewSafeSuiBigInt(summary.StorageCost.Uint64() + gasUsed.StorageCost.Uint64()),
+			StorageRebate:           types.NewSafeSuiBigInt(summary.StorageRebate.Uint64() + gasUsed.StorageRebate.Uint64()),
+			NonRefundableStorageFee: types.NewSafeSuiBigInt(summary.NonRefundableStorageFee.Uint64()),
+		}
+	}
+	for _, digest := range transactions {
+		hash.Write(digest)
+	}
+	checkpoint.Digest = hash.Sum(nil)
+	checkpoint.NetworkTotalTransactions = types.NewSafeSuiBigInt(total)
+	checkpoint.EpochRollingGasCostSummary = rolling
+	n.checkpoints = append(n.checkpoints, checkpoint)
+	return checkpoint
+}
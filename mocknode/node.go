@@ -0,0 +1,296 @@
+// Package mocknode is an in-process Sui JSON-RPC node for hermetic tests. It keeps the objects in
+// memory, executes programmable transactions splitting, merging and transferring coins and
+// objects, and serves the object, coin, balance, transaction, event, checkpoint, stake, validator
+// and system state reads of the client package over them.
+//
+// Methods it does not implement, like the dynamic fields, devInspectTransactionBlock, the Move
+// introspection and the unsafe_ transaction builders, return a method not found error.
+package mocknode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+// JSON-RPC error codes of the node.
+const (
+	CodeParseError           = -32700
+	CodeMethodNotFound       = -32601
+	CodeInvalidParams        = -32602
+	CodeInternal             = -32603
+	CodeTransactionExecution = -32002
+)
+
+// MaxPageSize is the largest page the paged methods return, like a full node.
+const MaxPageSize = 50
+
+type config struct {
+	referenceGasPrice uint64
+	computationUnits  uint64
+	storageCost       uint64
+	epoch             uint64
+	clock             func() time.Time
+}
+
+type Option func(*config)
+
+// WithReferenceGasPrice sets the reference gas price, the lowest gas price a transaction can pay.
+func WithReferenceGasPrice(price uint64) Option {
+	return func(c *config) {
+		c.referenceGasPrice = price
+	}
+}
+
+// WithGasCost sets the gas of every transaction: computationUnits times the gas price, and
+// storageCost for every object it writes, which is rebated once the object changes again.
+func WithGasCost(computationUnits, storageCost uint64) Option {
+	return func(c *config) {
+		c.computationUnits = computationUnits
+		c.storageCost = storageCost
+	}
+}
+
+func WithEpoch(epoch uint64) Option {
+	return func(c *config) {
+		c.epoch = epoch
+	}
+}
+
+// WithClock sets the clock of the transaction and checkpoint timestamps.
+func WithClock(clock func() time.Time) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// Node is a mock Sui full node, it is safe for concurrent use.
+type Node struct {
+	config config
+	server *httptest.Server
+
+	lock         sync.Mutex
+	objects      map[sui_types.ObjectID][]*object
+	transactions []*transaction
+	byDigest     map[string]*transaction
+	events       []types.SuiEvent
+	checkpoints  []types.Checkpoint
+	coinMetadata map[string]types.SuiCoinMetadata
+	validators   []*validator
+	stakes       map[sui_types.ObjectID]*stake
+	// created counts the seeding digests derived by the node.
+	created uint64
+}
+
+// New starts a mock node, Close it once done.
+func New(options ...Option) *Node {
+	n := NewHandler(options...)
+	n.server = httptest.NewServer(n)
+	return n
+}
+
+// NewHandler returns a mock node without starting a server, to serve it with ServeHTTP.
+func NewHandler(options ...Option) *Node {
+	c := config{
+		referenceGasPrice: 1000,
+		computationUnits:  1000,
+		storageCost:       988000,
+		clock:             time.Now,
+	}
+	for _, option := range options {
+		option(&c)
+	}
+	n := &Node{
+		config:   c,
+		objects:  map[sui_types.ObjectID][]*object{},
+		byDigest: map[string]*transaction{},
+		stakes:   map[sui_types.ObjectID]*stake{},
+		coinMetadata: map[string]types.SuiCoinMetadata{
+			types.SUI_COIN_TYPE: {Decimals: 9, Name: "Sui", Symbol: "SUI"},
+		},
+	}
+	n.appendCheckpoint(nil, types.GasCostSummary{})
+	return n
+}
+
+// URL is the url of the started node, to Dial it.
+func (n *Node) URL() string {
+	return n.server.URL
+}
+
+func (n *Node) Close() {
+	if n.server != nil {
+		n.server.Close()
+	}
+}
+
+// SetCoinMetadata sets the metadata getCoinMetadata returns for coinType.
+func (n *Node) SetCoinMetadata(coinType string, metadata types.SuiCoinMetadata) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.coinMetadata[normalizeCoinType(coinType)] = metadata
+}
+
+type request struct {
+	Id     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type response struct {
+	Version string          `json:"jsonrpc"`
+	Id      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error of the node.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func errorf(code int, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// ServeHTTP serves a JSON-RPC request or batch.
+func (n *Node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var requests []request
+		if err := json.Unmarshal(body, &requests); err != nil {
+			_ = json.NewEncoder(w).Encode(response{Version: "2.0", Error: errorf(CodeParseError, "%v", err)})
+			return
+		}
+		responses := make([]response, len(requests))
+		for i := range requests {
+			responses[i] = n.serve(&requests[i])
+		}
+		_ = json.NewEncoder(w).Encode(responses)
+		return
+	}
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		_ = json.NewEncoder(w).Encode(response{Version: "2.0", Error: errorf(CodeParseError, "%v", err)})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(n.serve(&req))
+}
+
+func (n *Node) serve(req *request) response {
+	resp := response{Version: "2.0", Id: req.Id}
+	method, ok := methods[req.Method]
+	if !ok {
+		resp.Error = errorf(CodeMethodNotFound, "Method not found: %s", req.Method)
+		return resp
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	result, err := method(n, params(req.Params))
+	if err != nil {
+		rpcErr, ok := err.(*Error)
+		if !ok {
+			rpcErr = errorf(CodeInvalidParams, "%v", err)
+		}
+		resp.Error = rpcErr
+		return resp
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		resp.Error = errorf(CodeInternal, "%v", err)
+		return resp
+	}
+	resp.Result = data
+	return resp
+}
+
+// params are the positional parameters of a call, the missing trailing ones are null.
+type params []json.RawMessage
+
+// get unmarshals the parameter i into v, a missing or null parameter leaves v unchanged.
+func (p params) get(i int, v interface{}) error {
+	if i >= len(p) || len(p[i]) == 0 || string(p[i]) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(p[i], v); err != nil {
+		return fmt.Errorf("invalid params: parameter %d: %w", i, err)
+	}
+	return nil
+}
+
+type method func(n *Node, p params) (interface{}, error)
+
+var methods = map[string]method{
+	"sui_getObject":                         (*Node).getObject,
+	"sui_multiGetObjects":                   (*Node).multiGetObjects,
+	"sui_tryGetPastObject":                  (*Node).tryGetPastObject,
+	"sui_tryMultiGetPastObjects":            (*Node).tryMultiGetPastObjects,
+	"sui_executeTransactionBlock":           (*Node).executeTransactionBlock,
+	"sui_dryRunTransactionBlock":            (*Node).dryRunTransactionBlock,
+	"sui_getTransactionBlock":               (*Node).getTransactionBlock,
+	"sui_multiGetTransactionBlocks":         (*Node).multiGetTransactionBlocks,
+	"sui_getEvents":                         (*Node).getEvents,
+	"sui_getTotalTransactionBlocks":         (*Node).getTotalTransactionBlocks,
+	"sui_getLatestCheckpointSequenceNumber": (*Node).getLatestCheckpointSequenceNumber,
+	"sui_getCheckpoint":                     (*Node).getCheckpoint,
+	"sui_getCheckpoints":                    (*Node).getCheckpoints,
+	"suix_getOwnedObjects":                  (*Node).getOwnedObjects,
+	"suix_getCoins":                         (*Node).getCoins,
+	"suix_getAllCoins":                      (*Node).getAllCoins,
+	"suix_getBalance":                       (*Node).getBalance,
+	"suix_getAllBalances":                   (*Node).getAllBalances,
+	"suix_getCoinMetadata":                  (*Node).getCoinMetadata,
+	"suix_getReferenceGasPrice":             (*Node).getReferenceGasPrice,
+	"suix_queryTransactionBlocks":           (*Node).queryTransactionBlocks,
+	"suix_queryEvents":                      (*Node).queryEvents,
+	"suix_getStakes":                        (*Node).getStakes,
+	"suix_getStakesByIds":                   (*Node).getStakesByIds,
+	"suix_getValidatorsApy":                 (*Node).getValidatorsApy,
+	"suix_getLatestSuiSystemState":          (*Node).getLatestSuiSystemState,
+	"suix_getCurrentEpoch":                  (*Node).getCurrentEpoch,
+}
+
+// seedDigest is the previous transaction of the seeded objects and events.
+func (n *Node) seedDigest() sui_types.TransactionDigest {
+	n.created++
+	hash := sui_types.NewDefaultHash()
+	hash.Write([]byte("seed"))
+	_ = binary.Write(hash, binary.LittleEndian, n.created)
+	return hash.Sum(nil)
+}
+
+func (n *Node) now() uint64 {
+	return uint64(n.config.clock().UnixMilli())
+}
+
+func pageLimit(limit *uint) int {
+	if limit == nil || *limit == 0 || *limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return int(*limit)
+}
+
+// stringNumber is a u64 the node sends as a string.
+func stringNumber(v uint64) string {
+	return fmt.Sprintf("%d", v)
+}
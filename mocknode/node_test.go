@@ -0,0 +1,321 @@
+package mocknode_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/account"
+	"github.com/thorli9527/sui-wallet-sdk/client"
+	"github.com/thorli9527/sui-wallet-sdk/mocknode"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+const usdcType = "0xa1ec7fc00a6f40db9693ad1415d0c193ad3906494428cf252621037bd7117e29::usdc::USDC"
+
+func testAccount(t *testing.T, seed byte) (*account.Account, sui_types.SuiAddress) {
+	scheme, err := sui_types.NewSignatureScheme(0)
+	require.NoError(t, err)
+	signer := account.NewAccount(scheme, append(make([]byte, 31), seed))
+	address, err := sui_types.NewAddressFromHex(signer.Address)
+	require.NoError(t, err)
+	return signer, *address
+}
+
+func dial(t *testing.T, options ...mocknode.Option) (*mocknode.Node, *client.Client) {
+	node := mocknode.New(options...)
+	t.Cleanup(node.Close)
+	cli, err := client.Dial(node.URL())
+	require.NoError(t, err)
+	return node, cli
+}
+
+func TestNode_Coins(t *testing.T) {
+	node, cli := dial(t)
+	ctx := context.Background()
+	_, owner := testAccount(t, 1)
+	for i := 0; i < 60; i++ {
+		node.Mint(owner, "", 1000)
+	}
+	usdc := node.Mint(owner, usdcType, 5)
+	node.SetCoinMetadata(usdcType, types.SuiCoinMetadata{Decimals: 6, Name: "USD Coin", Symbol: "USDC"})
+
+	var coins []types.Coin
+	var cursor *sui_types.ObjectID
+	for pages := 0; ; pages++ {
+		page, err := cli.GetCoins(ctx, owner, nil, cursor, 25)
+		require.NoError(t, err)
+		coins = append(coins, page.Data...)
+		if !page.HasNextPage {
+			require.Equal(t, 2, pages)
+			break
+		}
+		cursor = page.NextCursor
+	}
+	require.Len(t, coins, 60)
+	require.Equal(t, types.SUI_COIN_TYPE, coins[0].CoinType)
+	require.Equal(t, uint64(1000), coins[0].Balance.Uint64())
+
+	balance, err := cli.GetBalance(ctx, owner, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(60), balance.CoinObjectCount)
+	require.Equal(t, "60000", balance.TotalBalance.String())
+	balances, err := cli.GetAllBalances(ctx, owner)
+	require.NoError(t, err)
+	require.Len(t, balances, 2)
+	require.Equal(t, types.SUI_COIN_TYPE, balances[0].CoinType)
+	allCoins, err := cli.GetAllCoins(ctx, owner, nil, 0)
+	require.NoError(t, err)
+	require.Len(t, allCoins.Data, mocknode.MaxPageSize)
+	require.True(t, allCoins.HasNextPage)
+
+	metadata, err := cli.GetCoinMetadata(ctx, usdcType)
+	require.NoError(t, err)
+	require.Equal(t, uint8(6), metadata.Decimals)
+
+	object, err := cli.GetObject(
+		ctx, usdc.ObjectId, &types.SuiObjectDataOptions{ShowType: true, ShowContent: true, ShowOwner: true},
+	)
+	require.NoError(t, err)
+	require.Equal(t, usdc.Digest.String(), object.Data.Digest.String())
+	require.Equal(t, "0x2::coin::Coin<"+usdcType+">", *object.Data.Type)
+	require.Equal(t, owner, *object.Data.Owner.AddressOwner)
+	require.Equal(t, "5", object.Data.Content.Data.MoveObject.Fields.(map[string]interface{})["balance"])
+
+	owned, err := cli.GetOwnedObjects(
+		ctx, owner, &types.SuiObjectResponseQuery{
+			Filter: &types.SuiObjectDataFilter{StructType: "0x2::coin::Coin<" + usdcType + ">"},
+		}, nil, nil,
+	)
+	require.NoError(t, err)
+	require.Len(t, owned.Data, 1)
+	require.Equal(t, usdc.ObjectId, owned.Data[0].Data.ObjectId)
+
+	missing, err := cli.GetObject(ctx, sui_types.ObjectID{1}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, missing.Error.Data.NotExists)
+}
+
+func transferTransaction(
+	t *testing.T,
+	sender, recipient sui_types.SuiAddress,
+	gas, coin, merged sui_types.ObjectRef,
+	amount uint64,
+) []byte {
+	builder := sui_types.NewProgrammableTransactionBuilder()
+	coinArg, err := builder.Obj(sui_types.ObjectArg{ImmOrOwnedObject: &coin})
+	require.NoError(t, err)
+	mergedArg, err := builder.Obj(sui_types.ObjectArg{ImmOrOwnedObject: &merged})
+	require.NoError(t, err)
+	builder.Command(
+		sui_types.Command{
+			MergeCoins: &struct {
+				Argument  sui_types.Argument
+				Arguments []sui_types.Argument
+			}{Argument: coinArg, Arguments: []sui_types.Argument{mergedArg}},
+		},
+	)
+	amountArg, err := builder.Pure(amount)
+	require.NoError(t, err)
+	recipientArg, err := builder.Pure(recipient)
+	require.NoError(t, err)
+	split := builder.Command(
+		sui_types.Command{
+			SplitCoins: &struct {
+				Argument  sui_types.Argument
+				Arguments []sui_types.Argument
+			}{Argument: coinArg, Arguments: []sui_types.Argument{amountArg}},
+		},
+	)
+	builder.Command(
+		sui_types.Command{
+			TransferObjects: &struct {
+				Arguments []sui_types.Argument
+				Argument  sui_types.Argument
+			}{Arguments: []sui_types.Argument{split}, Argument: recipientArg},
+		},
+	)
+	tx := sui_types.NewProgrammable(sender, []*sui_types.ObjectRef{&gas}, builder.Finish(), 10_000_000, 1000)
+	txBytes, err := bcs.Marshal(tx)
+	require.NoError(t, err)
+	return txBytes
+}
+
+func TestNode_ExecuteTransaction(t *testing.T) {
+	node, cli := dial(t, mocknode.WithGasCost(1000, 100_000))
+	ctx := context.Background()
+	signer, sender := testAccount(t, 1)
+	_, recipient := testAccount(t, 2)
+	gas := node.Mint(sender, "", 1_000_000_000)
+	coin := node.Mint(sender, "", 300)
+	merged := node.Mint(sender, "", 200)
+
+	txBytes := transferTransaction(t, sender, recipient, gas, coin, merged, 450)
+	options := &types.SuiTransactionBlockResponseOptions{ShowObjectChanges: true, ShowBalanceChanges: true}
+	resp, err := cli.SignAndExecuteTransaction(ctx, signer, txBytes, options)
+	require.NoError(t, err)
+	require.Equal(t, sui_types.NewTransactionDigestFromBytes(txBytes).String(), resp.Digest.String())
+	effects := resp.Effects.Data.V1
+	require.Len(t, effects.Created, 1)
+	require.Len(t, effects.Mutated, 2)
+	require.Len(t, effects.Deleted, 1)
+	require.Equal(t, uint64(2), effects.GasObject.Reference.Version)
+	// computation 1000 * 1000, storage of the 3 written objects, rebate of the 3 inputs
+	require.Equal(t, int64(1_000_000), resp.Effects.Data.GasFee())
+	require.Len(t, resp.ObjectChanges, 4)
+	require.Len(t, resp.BalanceChanges, 2)
+	require.NotNil(t, resp.Checkpoint)
+
+	coins, err := cli.GetCoins(ctx, recipient, nil, nil, 0)
+	require.NoError(t, err)
+	require.Len(t, coins.Data, 1)
+	require.Equal(t, uint64(450), coins.Data[0].Balance.Uint64())
+	require.Equal(t, uint64(2), coins.Data[0].Version.Uint64())
+	remaining, err := cli.GetObject(ctx, coin.ObjectId, &types.SuiObjectDataOptions{ShowContent: true})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), remaining.Data.Version.Uint64())
+	require.Equal(t, "50", remaining.Data.Content.Data.MoveObject.Fields.(map[string]interface{})["balance"])
+	deleted, err := cli.GetObject(ctx, merged.ObjectId, nil)
+	require.NoError(t, err)
+	require.NotNil(t, deleted.Error.Data.Deleted)
+	balance, err := cli.GetBalance(ctx, sender, "")
+	require.NoError(t, err)
+	require.Equal(t, "999000050", balance.TotalBalance.String())
+
+	past, err := cli.TryGetPastObject(ctx, coin.ObjectId, 1, &types.SuiObjectDataOptions{ShowContent: true})
+	require.NoError(t, err)
+	require.Equal(t, "300", past.Data.VersionFound.Content.Data.MoveObject.Fields.(map[string]interface{})["balance"])
+	pasts, err := cli.TryMultiGetPastObjects(
+		ctx, []types.SuiGetPastObjectRequest{
+			{ObjectId: coin.ObjectId, Version: types.NewSafeSuiBigInt(sui_types.SequenceNumber(1))},
+			{ObjectId: merged.ObjectId, Version: types.NewSafeSuiBigInt(sui_types.SequenceNumber(100))},
+		}, nil,
+	)
+	require.NoError(t, err)
+	require.Len(t, pasts, 2)
+	require.Equal(t, coin.ObjectId, pasts[0].Data.VersionFound.ObjectId)
+	require.NotNil(t, pasts[1].Data.VersionTooHigh)
+
+	// executing it again returns the same transaction
+	again, err := cli.SignAndExecuteTransaction(ctx, signer, txBytes, options)
+	require.NoError(t, err)
+	require.Equal(t, resp.Digest.String(), again.Digest.String())
+
+	fetched, err := cli.GetTransactionBlock(
+		ctx, resp.Digest, types.SuiTransactionBlockResponseOptions{ShowInput: true, ShowEffects: true},
+	)
+	require.NoError(t, err)
+	require.Equal(t, sender, fetched.Transaction.Data.Data.V1.Sender)
+	for _, filter := range []types.TransactionFilter{
+		{FromAddress: &sender}, {ToAddress: &recipient}, {InputObject: &merged.ObjectId}, {ChangedObject: &gas.ObjectId},
+	} {
+		page, err := cli.QueryTransactionBlocks(ctx, types.SuiTransactionBlockResponseQuery{Filter: &filter}, nil, nil, false)
+		require.NoError(t, err)
+		require.Len(t, page.Data, 1)
+	}
+	total, err := cli.GetTotalTransactionBlocks(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "1", total)
+	latest, err := cli.GetLatestCheckpointSequenceNumber(ctx)
+	require.NoError(t, err)
+	checkpoint, err := cli.GetCheckpoint(ctx, types.CheckpointId(latest))
+	require.NoError(t, err)
+	require.Equal(t, resp.Digest.String(), checkpoint.Transactions[0].String())
+}
+
+func TestNode_ExecutionErrors(t *testing.T) {
+	node, cli := dial(t)
+	ctx := context.Background()
+	signer, sender := testAccount(t, 1)
+	other, _ := testAccount(t, 3)
+	_, recipient := testAccount(t, 2)
+	gas := node.Mint(sender, "", 1_000_000_000)
+	coin := node.Mint(sender, "", 300)
+	merged := node.Mint(sender, "", 200)
+
+	// the signature of another account
+	txBytes := transferTransaction(t, sender, recipient, gas, coin, merged, 1000)
+	_, err := cli.SignAndExecuteTransaction(ctx, other, txBytes, nil)
+	require.ErrorContains(t, err, "Required Signature")
+
+	dryRun, err := cli.DryRunTransaction(ctx, txBytes)
+	require.NoError(t, err)
+	require.Equal(t, types.ExecutionStatusFailure, dryRun.Effects.Data.V1.Status.Status)
+
+	// the split of more than the merged coins fails, only the gas is charged
+	resp, err := cli.SignAndExecuteTransaction(ctx, signer, txBytes, nil)
+	var executionErr *types.ExecutionError
+	require.True(t, errors.As(err, &executionErr))
+	require.Equal(t, types.ExecutionErrorInsufficientCoinBalance, executionErr.Kind)
+	require.Equal(t, 1, *executionErr.Command)
+	require.Len(t, resp.Effects.Data.V1.Deleted, 0)
+	unchanged, err := cli.GetObject(ctx, merged.ObjectId, &types.SuiObjectDataOptions{ShowContent: true})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), unchanged.Data.Version.Uint64())
+	require.Equal(t, "200", unchanged.Data.Content.Data.MoveObject.Fields.(map[string]interface{})["balance"])
+
+	// the references of the failed transaction are stale
+	txBytes = transferTransaction(t, sender, recipient, gas, coin, merged, 100)
+	_, err = cli.SignAndExecuteTransaction(ctx, signer, txBytes, nil)
+	require.ErrorIs(t, err, types.ErrObjectVersionConflict)
+
+	poor := node.Mint(sender, "", 10)
+	tx := sui_types.NewProgrammable(sender, []*sui_types.ObjectRef{&poor}, sui_types.ProgrammableTransaction{}, 10_000_000, 1000)
+	txBytes, err = bcs.Marshal(tx)
+	require.NoError(t, err)
+	_, err = cli.SignAndExecuteTransaction(ctx, signer, txBytes, nil)
+	require.ErrorIs(t, err, types.ErrInsufficientGas)
+
+	var result interface{}
+	err = cli.CallContext(ctx, &result, client.SuiMethod("getNormalizedMoveModule"))
+	var rpcErr *client.RPCError
+	require.True(t, errors.As(err, &rpcErr))
+	require.Equal(t, mocknode.CodeMethodNotFound, rpcErr.Code)
+}
+
+func TestNode_Events(t *testing.T) {
+	node, cli := dial(t)
+	ctx := context.Background()
+	_, sender := testAccount(t, 1)
+	var ids []types.EventId
+	for i := 0; i < 5; i++ {
+		ids = append(ids, node.EmitEvent(sender, "0x5::auction::Bid", map[string]interface{}{"amount": i}))
+	}
+	node.EmitEvent(sender, "0x5::auction::Closed", map[string]interface{}{})
+
+	eventType := "0x5::auction::Bid"
+	limit := uint(2)
+	var bids []types.SuiEvent
+	var cursor *types.EventId
+	for {
+		page, err := cli.QueryEvents(ctx, types.EventFilter{MoveEventType: &eventType}, cursor, &limit, true)
+		require.NoError(t, err)
+		bids = append(bids, page.Data...)
+		if !page.HasNextPage {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	require.Len(t, bids, 5)
+	require.Equal(t, ids[4].TxDigest.String(), bids[0].Id.TxDigest.String())
+
+	events, err := cli.GetEvents(ctx, ids[1].TxDigest)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, float64(1), events[0].ParsedJson.(map[string]interface{})["amount"])
+
+	module := struct {
+		Package sui_types.ObjectID `json:"package"`
+		Module  string             `json:"module"`
+	}{Package: sui_types.ObjectID{31: 5}, Module: "auction"}
+	page, err := cli.QueryEvents(
+		ctx, types.EventFilter{
+			All: &[]types.EventFilter{{Sender: &sender}, {MoveModule: &module}},
+		}, nil, nil, false,
+	)
+	require.NoError(t, err)
+	require.Len(t, page.Data, 6)
+}
@@ -0,0 +1,217 @@
+package mocknode
+
+import (
+	"encoding/binary"
+
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+const stakedSuiType = "0x3::staking_pool::StakedSui"
+
+// epochsPerYear is the number of epochs the APY of a validator is earned over, an epoch is a day.
+const epochsPerYear = 365
+
+// validator is an active validator of the system state.
+type validator struct {
+	address     sui_types.SuiAddress
+	name        string
+	stakingPool sui_types.ObjectID
+	apy         float64
+}
+
+// stake is the staking data of a StakedSui object.
+type stake struct {
+	validator    *validator
+	principal    uint64
+	requestEpoch uint64
+}
+
+// AddValidator adds an active validator named name, earning apy, 0.05 is 5%, and returns its
+// staking pool.
+func (n *Node) AddValidator(address sui_types.SuiAddress, name string, apy float64) sui_types.ObjectID {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.addValidator(address, name, apy).stakingPool
+}
+
+func (n *Node) addValidator(address sui_types.SuiAddress, name string, apy float64) *validator {
+	v := &validator{address: address, name: name, stakingPool: objectId(n.seedDigest(), 0), apy: apy}
+	n.validators = append(n.validators, v)
+	return v
+}
+
+func (n *Node) validator(address sui_types.SuiAddress) *validator {
+	for _, v := range n.validators {
+		if v.address == address {
+			return v
+		}
+	}
+	return nil
+}
+
+// AddStake creates a StakedSui of principal owned by owner, staked with validator at requestEpoch.
+// The stake is pending until the epoch after, then active and earning the APY of the validator
+// every epoch. An unknown validator is added without APY.
+func (n *Node) AddStake(
+	owner, validator sui_types.SuiAddress,
+	principal, requestEpoch uint64,
+) sui_types.ObjectRef {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	v := n.validator(validator)
+	if v == nil {
+		v = n.addValidator(validator, "", 0)
+	}
+	digest := n.seedDigest()
+	id := objectId(digest, 0)
+	// the BCS contents of a StakedSui: its id, pool id, activation epoch and principal
+	contents := append(append([]byte{}, id[:]...), v.stakingPool[:]...)
+	contents = binary.LittleEndian.AppendUint64(contents, requestEpoch+1)
+	contents = binary.LittleEndian.AppendUint64(contents, principal)
+	o := &object{
+		id:         id,
+		version:    sui_types.ObjectStartVersion,
+		owner:      owner,
+		objectType: normalizeType(stakedSuiType),
+		fields: map[string]interface{}{
+			"id":                     map[string]interface{}{"id": id.String()},
+			"pool_id":                v.stakingPool.String(),
+			"stake_activation_epoch": stringNumber(requestEpoch + 1),
+			"principal":              stringNumber(principal),
+		},
+		contents:            contents,
+		previousTransaction: digest,
+		storageRebate:       n.config.storageCost,
+	}
+	n.put(o)
+	n.stakes[id] = &stake{validator: v, principal: principal, requestEpoch: requestEpoch}
+	return o.ref()
+}
+
+// wireStake returns the stake of a StakedSui, unstaked once the object is deleted.
+func (n *Node) wireStake(o *object, s *stake) wireStake {
+	w := wireStake{
+		StakedSuiId:       o.id,
+		StakeRequestEpoch: stringNumber(s.requestEpoch),
+		StakeActiveEpoch:  stringNumber(s.requestEpoch + 1),
+		Principal:         stringNumber(s.principal),
+	}
+	switch epoch := n.config.epoch; {
+	case o.deleted:
+		w.Status = types.StakeStatusUnstaked
+	case epoch <= s.requestEpoch:
+		w.Status = types.StakeStatusPending
+	default:
+		w.Status = types.StakeStatusActive
+		epochs := float64(epoch - s.requestEpoch - 1)
+		w.EstimatedReward = stringNumber(uint64(float64(s.principal) * s.validator.apy * epochs / epochsPerYear))
+	}
+	return w
+}
+
+// delegatedStakes groups the StakedSui objects by validator, in the order of the validators.
+func (n *Node) delegatedStakes(objects []*object) []wireDelegatedStake {
+	var delegated []wireDelegatedStake
+	for _, v := range n.validators {
+		var stakes []wireStake
+		for _, o := range objects {
+			if s := n.stakes[o.id]; s.validator == v {
+				stakes = append(stakes, n.wireStake(o, s))
+			}
+		}
+		if len(stakes) > 0 {
+			delegated = append(
+				delegated, wireDelegatedStake{ValidatorAddress: v.address, StakingPool: v.stakingPool, Stakes: stakes},
+			)
+		}
+	}
+	return nonNil(delegated)
+}
+
+func (n *Node) getStakes(p params) (interface{}, error) {
+	var owner sui_types.SuiAddress
+	if err := p.get(0, &owner); err != nil {
+		return nil, err
+	}
+	return n.delegatedStakes(n.owned(owner, func(o *object) bool { return n.stakes[o.id] != nil })), nil
+}
+
+func (n *Node) getStakesByIds(p params) (interface{}, error) {
+	var ids []sui_types.ObjectID
+	if err := p.get(0, &ids); err != nil {
+		return nil, err
+	}
+	var objects []*object
+	for _, id := range ids {
+		o := n.latest(id)
+		if o == nil || n.stakes[id] == nil {
+			return nil, errorf(CodeInvalidParams, "Could not find StakedSui object %s", id)
+		}
+		objects = append(objects, o)
+	}
+	return n.delegatedStakes(objects), nil
+}
+
+func (n *Node) getValidatorsApy(p params) (interface{}, error) {
+	apys := wireValidatorsApy{Epoch: stringNumber(n.config.epoch), Apys: []wireValidatorApy{}}
+	for _, v := range n.validators {
+		apys.Apys = append(apys.Apys, wireValidatorApy{Address: v.address.String(), Apy: v.apy})
+	}
+	return apys, nil
+}
+
+// validatorSummaries returns the active validators, each with the stakes of its pool.
+func (n *Node) validatorSummaries() (summaries []types.SuiValidatorSummary, totalStake uint64) {
+	summaries = []types.SuiValidatorSummary{}
+	for _, v := range n.validators {
+		var staked uint64
+		for id, s := range n.stakes {
+			if s.validator == v && !n.latest(id).deleted {
+				staked += s.principal
+			}
+		}
+		totalStake += staked
+		summaries = append(
+			summaries, types.SuiValidatorSummary{
+				SuiAddress:            v.address,
+				Name:                  v.name,
+				StakingPoolId:         v.stakingPool,
+				GasPrice:              types.NewSafeSuiBigInt(n.config.referenceGasPrice),
+				NextEpochGasPrice:     types.NewSafeSuiBigInt(n.config.referenceGasPrice),
+				StakingPoolSuiBalance: types.NewSafeSuiBigInt(staked),
+				NextEpochStake:        types.NewSafeSuiBigInt(staked),
+			},
+		)
+	}
+	return summaries, totalStake
+}
+
+func (n *Node) getLatestSuiSystemState(p params) (interface{}, error) {
+	validators, totalStake := n.validatorSummaries()
+	return types.SuiSystemStateSummary{
+		Epoch:                  types.NewSafeSuiBigInt(n.config.epoch),
+		ProtocolVersion:        types.NewSafeSuiBigInt(uint64(1)),
+		SystemStateVersion:     types.NewSafeSuiBigInt(uint64(1)),
+		ReferenceGasPrice:      types.NewSafeSuiBigInt(n.config.referenceGasPrice),
+		EpochStartTimestampMs:  n.checkpoints[0].TimestampMs,
+		EpochDurationMs:        types.NewSafeSuiBigInt(uint64(24 * 60 * 60 * 1000)),
+		TotalStake:             types.NewSafeSuiBigInt(totalStake),
+		ActiveValidators:       validators,
+		PendingRemovals:        []types.SafeSuiBigInt[uint64]{},
+		AtRiskValidators:       []interface{}{},
+		ValidatorReportRecords: []interface{}{},
+	}, nil
+}
+
+func (n *Node) getCurrentEpoch(p params) (interface{}, error) {
+	validators, _ := n.validatorSummaries()
+	referenceGasPrice := n.config.referenceGasPrice
+	return types.EpochInfo{
+		Epoch:                  types.NewSafeSuiBigInt(n.config.epoch),
+		Validators:             validators,
+		EpochTotalTransactions: types.NewSafeSuiBigInt(uint64(len(n.transactions))),
+		EpochStartTimestamp:    n.checkpoints[0].TimestampMs,
+		ReferenceGasPrice:      &referenceGasPrice,
+	}, nil
+}
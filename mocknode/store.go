@@ -0,0 +1,575 @@
+package mocknode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+// object is a version of an object, every object is owned by an address.
+type object struct {
+	id         sui_types.ObjectID
+	version    sui_types.SequenceNumber
+	digest     sui_types.ObjectDigest
+	owner      sui_types.SuiAddress
+	objectType string
+	// coinType is the T of a Coin<T>, empty for the other objects.
+	coinType            string
+	balance             uint64
+	fields              map[string]interface{}
+	contents            []byte
+	previousTransaction sui_types.TransactionDigest
+	storageRebate       uint64
+	deleted             bool
+}
+
+// deletedDigest is the digest of the deleted objects in the effects.
+var deletedDigest = sui_types.ObjectDigest(bytes.Repeat([]byte{99}, 32))
+
+func (o *object) ref() sui_types.ObjectRef {
+	return sui_types.ObjectRef{ObjectId: o.id, Version: o.version, Digest: o.digest}
+}
+
+func (o *object) clone() *object {
+	c := *o
+	return &c
+}
+
+// seal computes the contents of a coin and the digest of the object once it is written.
+func (o *object) seal() {
+	if o.deleted {
+		o.digest = deletedDigest
+		return
+	}
+	if o.coinType != "" {
+		o.contents = make([]byte, len(o.id)+8)
+		copy(o.contents, o.id[:])
+		binary.LittleEndian.PutUint64(o.contents[len(o.id):], o.balance)
+		o.fields = map[string]interface{}{
+			"id":      map[string]interface{}{"id": o.id.String()},
+			"balance": stringNumber(o.balance),
+		}
+	}
+	hash := sui_types.NewDefaultHash()
+	hash.Write([]byte("Object::"))
+	hash.Write([]byte(o.objectType))
+	_ = binary.Write(hash, binary.LittleEndian, o.version)
+	hash.Write(o.contents)
+	hash.Write(o.owner[:])
+	hash.Write(o.previousTransaction)
+	_ = binary.Write(hash, binary.LittleEndian, o.storageRebate)
+	o.digest = hash.Sum(nil)
+}
+
+// latest returns the current version of an object, nil if it never existed.
+func (n *Node) latest(id sui_types.ObjectID) *object {
+	versions := n.objects[id]
+	if len(versions) == 0 {
+		return nil
+	}
+	return versions[len(versions)-1]
+}
+
+func (n *Node) put(o *object) {
+	o.seal()
+	n.objects[o.id] = append(n.objects[o.id], o)
+}
+
+var typeAddress = regexp.MustCompile(`0[xX][0-9a-fA-F]+`)
+
+// normalizeType writes the addresses of a type like the node: the framework addresses short, the
+// others with 64 hex digits.
+func normalizeType(objectType string) string {
+	return typeAddress.ReplaceAllStringFunc(
+		objectType, func(address string) string {
+			parsed, err := sui_types.NewAddressFromHex(address)
+			if err != nil {
+				return address
+			}
+			if short := parsed.ShortString(); len(short) <= 4 {
+				return short
+			}
+			return parsed.String()
+		},
+	)
+}
+
+func coinObjectType(coinType string) string {
+	return "0x2::coin::Coin<" + coinType + ">"
+}
+
+func normalizeCoinType(coinType string) string {
+	if coinType == "" {
+		return types.SUI_COIN_TYPE
+	}
+	return normalizeType(coinType)
+}
+
+// Mint creates a coin of coinType with balance owned by owner, an empty coinType mints SUI.
+func (n *Node) Mint(owner sui_types.SuiAddress, coinType string, balance uint64) sui_types.ObjectRef {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	coinType = normalizeCoinType(coinType)
+	digest := n.seedDigest()
+	o := &object{
+		id:                  objectId(digest, 0),
+		version:             sui_types.ObjectStartVersion,
+		owner:               owner,
+		objectType:          coinObjectType(coinType),
+		coinType:            coinType,
+		balance:             balance,
+		previousTransaction: digest,
+		storageRebate:       n.config.storageCost,
+	}
+	n.put(o)
+	return o.ref()
+}
+
+// AddObject creates an object of objectType owned by owner. Its content has fields and its id, its
+// BCS contents are only its id.
+func (n *Node) AddObject(
+	owner sui_types.SuiAddress,
+	objectType string,
+	fields map[string]interface{},
+) sui_types.ObjectRef {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	digest := n.seedDigest()
+	id := objectId(digest, 0)
+	content := map[string]interface{}{"id": map[string]interface{}{"id": id.String()}}
+	for name, value := range fields {
+		content[name] = value
+	}
+	o := &object{
+		id:                  id,
+		version:             sui_types.ObjectStartVersion,
+		owner:               owner,
+		objectType:          normalizeType(objectType),
+		fields:              content,
+		contents:            append([]byte{}, id[:]...),
+		previousTransaction: digest,
+		storageRebate:       n.config.storageCost,
+	}
+	n.put(o)
+	return o.ref()
+}
+
+// EmitEvent records an event of eventType, like "0x5::auction::Bid", emitted by sender outside of
+// any transaction of the node.
+func (n *Node) EmitEvent(sender sui_types.SuiAddress, eventType string, parsedJson interface{}) types.EventId {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	eventType = normalizeType(eventType)
+	event := types.SuiEvent{
+		Id:         types.EventId{TxDigest: n.seedDigest(), EventSeq: types.NewSafeSuiBigInt(uint64(0))},
+		Sender:     sender,
+		Type:       eventType,
+		ParsedJson: parsedJson,
+	}
+	if parts := strings.SplitN(eventType, "::", 3); len(parts) == 3 {
+		if packageId, err := sui_types.NewObjectIdFromHex(parts[0]); err == nil {
+			event.PackageId = *packageId
+		}
+		event.TransactionModule = parts[1]
+	}
+	timestamp := types.NewSafeSuiBigInt(n.now())
+	event.TimestampMs = &timestamp
+	n.events = append(n.events, event)
+	return event.Id
+}
+
+func (n *Node) objectData(o *object, options *types.SuiObjectDataOptions) *wireObjectData {
+	data := &wireObjectData{ObjectId: o.id, Version: stringNumber(o.version), Digest: o.digest}
+	if options == nil {
+		return data
+	}
+	if options.ShowType {
+		data.Type = o.objectType
+	}
+	if options.ShowOwner {
+		data.Owner = addressOwner(o.owner)
+	}
+	if options.ShowPreviousTransaction {
+		data.PreviousTransaction = o.previousTransaction
+	}
+	if options.ShowStorageRebate {
+		data.StorageRebate = stringNumber(o.storageRebate)
+	}
+	if options.ShowContent {
+		data.Content = &wireParsedData{
+			DataType: "moveObject", Type: o.objectType, HasPublicTransfer: true, Fields: o.fields,
+		}
+	}
+	if options.ShowBcs {
+		data.Bcs = &wireRawData{
+			DataType: "moveObject", Type: o.objectType, HasPublicTransfer: true, Version: o.version,
+			BcsBytes: o.contents,
+		}
+	}
+	return data
+}
+
+func (n *Node) objectResponse(id sui_types.ObjectID, options *types.SuiObjectDataOptions) wireObjectResponse {
+	o := n.latest(id)
+	switch {
+	case o == nil:
+		return wireObjectResponse{Error: &wireObjectError{Code: "notExists", ObjectId: id}}
+	case o.deleted:
+		return wireObjectResponse{
+			Error: &wireObjectError{Code: "deleted", ObjectId: id, Version: &o.version, Digest: o.digest},
+		}
+	default:
+		return wireObjectResponse{Data: n.objectData(o, options)}
+	}
+}
+
+func (n *Node) getObject(p params) (interface{}, error) {
+	var id sui_types.ObjectID
+	var options *types.SuiObjectDataOptions
+	if err := p.get(0, &id); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &options); err != nil {
+		return nil, err
+	}
+	return n.objectResponse(id, options), nil
+}
+
+func (n *Node) multiGetObjects(p params) (interface{}, error) {
+	var ids []sui_types.ObjectID
+	var options *types.SuiObjectDataOptions
+	if err := p.get(0, &ids); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &options); err != nil {
+		return nil, err
+	}
+	if len(ids) > MaxPageSize {
+		return nil, fmt.Errorf("too many object ids, the limit is %d", MaxPageSize)
+	}
+	responses := make([]wireObjectResponse, len(ids))
+	for i, id := range ids {
+		responses[i] = n.objectResponse(id, options)
+	}
+	return responses, nil
+}
+
+func (n *Node) tryGetPastObject(p params) (interface{}, error) {
+	var id sui_types.ObjectID
+	var version types.SafeSuiBigInt[sui_types.SequenceNumber]
+	var options *types.SuiObjectDataOptions
+	if err := p.get(0, &id); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &version); err != nil {
+		return nil, err
+	}
+	if err := p.get(2, &options); err != nil {
+		return nil, err
+	}
+	return n.pastObject(id, version.Uint64(), options), nil
+}
+
+func (n *Node) tryMultiGetPastObjects(p params) (interface{}, error) {
+	var requests []types.SuiGetPastObjectRequest
+	var options *types.SuiObjectDataOptions
+	if err := p.get(0, &requests); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &options); err != nil {
+		return nil, err
+	}
+	objects := make([]wirePastObject, len(requests))
+	for i, request := range requests {
+		objects[i] = n.pastObject(request.ObjectId, request.Version.Uint64(), options)
+	}
+	return objects, nil
+}
+
+func (n *Node) pastObject(
+	id sui_types.ObjectID,
+	version sui_types.SequenceNumber,
+	options *types.SuiObjectDataOptions,
+) wirePastObject {
+	latest := n.latest(id)
+	if latest == nil {
+		return wirePastObject{Status: "ObjectNotExists", Details: id}
+	}
+	if version > latest.version {
+		return wirePastObject{
+			Status: "VersionTooHigh",
+			Details: map[string]interface{}{
+				"object_id": id, "asked_version": version, "latest_version": latest.version,
+			},
+		}
+	}
+	for _, o := range n.objects[id] {
+		if o.version != version {
+			continue
+		}
+		if o.deleted {
+			return wirePastObject{
+				Status:  "ObjectDeleted",
+				Details: types.SuiObjectRef{Digest: o.digest, ObjectId: id.String(), Version: o.version},
+			}
+		}
+		return wirePastObject{Status: "VersionFound", Details: n.objectData(o, options)}
+	}
+	// in the shape of types.SuiPastObject
+	return wirePastObject{Status: "VersionNotFound", Details: map[string]interface{}{"ObjectId": version}}
+}
+
+// owned returns the current objects of owner sorted by id.
+func (n *Node) owned(owner sui_types.SuiAddress, keep func(o *object) bool) []*object {
+	var objects []*object
+	for id := range n.objects {
+		o := n.latest(id)
+		if !o.deleted && o.owner == owner && keep(o) {
+			objects = append(objects, o)
+		}
+	}
+	sort.Slice(
+		objects, func(i, j int) bool {
+			return bytes.Compare(objects[i].id[:], objects[j].id[:]) < 0
+		},
+	)
+	return objects
+}
+
+// pageObjects returns the objects after the one with the id cursor.
+func pageObjects(objects []*object, cursor *sui_types.ObjectID, limit *uint) (page []*object, next *sui_types.ObjectID, more bool) {
+	start := 0
+	if cursor != nil {
+		for i, o := range objects {
+			if o.id == *cursor {
+				start = i + 1
+				break
+			}
+		}
+	}
+	end := start + pageLimit(limit)
+	if end >= len(objects) {
+		end = len(objects)
+	} else {
+		more = true
+	}
+	page = objects[start:end]
+	if len(page) > 0 {
+		next = &page[len(page)-1].id
+	}
+	return page, next, more
+}
+
+func matchesObjectFilter(o *object, filter *types.SuiObjectDataFilter) bool {
+	if filter == nil {
+		return true
+	}
+	if filter.StructType != "" {
+		structType := normalizeType(filter.StructType)
+		if o.objectType != structType && !strings.HasPrefix(o.objectType, structType+"<") {
+			return false
+		}
+	}
+	if filter.Package != nil && !strings.HasPrefix(o.objectType, normalizeType(filter.Package.String())+"::") {
+		return false
+	}
+	if filter.MoveModule != nil {
+		prefix := normalizeType(filter.MoveModule.Package.String()) + "::" + filter.MoveModule.Module + "::"
+		if !strings.HasPrefix(o.objectType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// objectCursor is the cursor of getOwnedObjects, an object id or a types.CheckpointedObjectId.
+type objectCursor struct {
+	id *sui_types.ObjectID
+}
+
+func (c *objectCursor) UnmarshalJSON(data []byte) error {
+	var id sui_types.ObjectID
+	if err := json.Unmarshal(data, &id); err == nil {
+		c.id = &id
+		return nil
+	}
+	var checkpointed types.CheckpointedObjectId
+	if err := json.Unmarshal(data, &checkpointed); err != nil {
+		return err
+	}
+	c.id = &checkpointed.ObjectId
+	return nil
+}
+
+func (n *Node) getOwnedObjects(p params) (interface{}, error) {
+	var owner sui_types.SuiAddress
+	var query types.SuiObjectResponseQuery
+	var cursor objectCursor
+	var limit *uint
+	if err := p.get(0, &owner); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &query); err != nil {
+		return nil, err
+	}
+	if err := p.get(2, &cursor); err != nil {
+		return nil, err
+	}
+	if err := p.get(3, &limit); err != nil {
+		return nil, err
+	}
+	objects := n.owned(
+		owner, func(o *object) bool {
+			return matchesObjectFilter(o, query.Filter)
+		},
+	)
+	page, next, more := pageObjects(objects, cursor.id, limit)
+	data := make([]wireObjectResponse, len(page))
+	for i, o := range page {
+		data[i] = wireObjectResponse{Data: n.objectData(o, query.Options)}
+	}
+	return wirePage{Data: data, NextCursor: next, HasNextPage: more}, nil
+}
+
+func coin(o *object) types.Coin {
+	return types.Coin{
+		CoinType:            o.coinType,
+		CoinObjectId:        o.id,
+		Version:             types.NewSafeSuiBigInt(o.version),
+		Digest:              o.digest,
+		Balance:             types.NewSafeSuiBigInt(o.balance),
+		PreviousTransaction: o.previousTransaction,
+	}
+}
+
+func coinPage(objects []*object, cursor *sui_types.ObjectID, limit *uint) types.CoinPage {
+	page, next, more := pageObjects(objects, cursor, limit)
+	coins := make([]types.Coin, len(page))
+	for i, o := range page {
+		coins[i] = coin(o)
+	}
+	return types.CoinPage{Data: coins, NextCursor: next, HasNextPage: more}
+}
+
+func (n *Node) getCoins(p params) (interface{}, error) {
+	var owner sui_types.SuiAddress
+	var coinType string
+	var cursor *sui_types.ObjectID
+	var limit *uint
+	if err := p.get(0, &owner); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &coinType); err != nil {
+		return nil, err
+	}
+	if err := p.get(2, &cursor); err != nil {
+		return nil, err
+	}
+	if err := p.get(3, &limit); err != nil {
+		return nil, err
+	}
+	coinType = normalizeCoinType(coinType)
+	objects := n.owned(
+		owner, func(o *object) bool {
+			return o.coinType == coinType
+		},
+	)
+	return coinPage(objects, cursor, limit), nil
+}
+
+func (n *Node) getAllCoins(p params) (interface{}, error) {
+	var owner sui_types.SuiAddress
+	var cursor *sui_types.ObjectID
+	var limit *uint
+	if err := p.get(0, &owner); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &cursor); err != nil {
+		return nil, err
+	}
+	if err := p.get(2, &limit); err != nil {
+		return nil, err
+	}
+	objects := n.owned(
+		owner, func(o *object) bool {
+			return o.coinType != ""
+		},
+	)
+	return coinPage(objects, cursor, limit), nil
+}
+
+// balances returns the balances of owner sorted by coin type.
+func (n *Node) balances(owner sui_types.SuiAddress) []wireBalance {
+	byType := map[string]*wireBalance{}
+	var coinTypes []string
+	for _, o := range n.owned(
+		owner, func(o *object) bool {
+			return o.coinType != ""
+		},
+	) {
+		balance, ok := byType[o.coinType]
+		if !ok {
+			balance = &wireBalance{CoinType: o.coinType, LockedBalance: map[string]string{}}
+			byType[o.coinType] = balance
+			coinTypes = append(coinTypes, o.coinType)
+		}
+		balance.CoinObjectCount++
+		balance.total += o.balance
+	}
+	sort.Strings(coinTypes)
+	balances := make([]wireBalance, len(coinTypes))
+	for i, coinType := range coinTypes {
+		balances[i] = *byType[coinType]
+		balances[i].TotalBalance = stringNumber(balances[i].total)
+	}
+	return balances
+}
+
+func (n *Node) getBalance(p params) (interface{}, error) {
+	var owner sui_types.SuiAddress
+	var coinType string
+	if err := p.get(0, &owner); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &coinType); err != nil {
+		return nil, err
+	}
+	coinType = normalizeCoinType(coinType)
+	for _, balance := range n.balances(owner) {
+		if balance.CoinType == coinType {
+			return balance, nil
+		}
+	}
+	return wireBalance{CoinType: coinType, TotalBalance: "0", LockedBalance: map[string]string{}}, nil
+}
+
+func (n *Node) getAllBalances(p params) (interface{}, error) {
+	var owner sui_types.SuiAddress
+	if err := p.get(0, &owner); err != nil {
+		return nil, err
+	}
+	return n.balances(owner), nil
+}
+
+func (n *Node) getCoinMetadata(p params) (interface{}, error) {
+	var coinType string
+	if err := p.get(0, &coinType); err != nil {
+		return nil, err
+	}
+	metadata, ok := n.coinMetadata[normalizeCoinType(coinType)]
+	if !ok {
+		return nil, nil
+	}
+	return metadata, nil
+}
+
+func (n *Node) getReferenceGasPrice(p params) (interface{}, error) {
+	return stringNumber(n.config.referenceGasPrice), nil
+}
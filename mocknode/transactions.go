@@ -0,0 +1,377 @@
+package mocknode
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+// pageAfter returns the page of items after the index cursor, -1 starts with the first item.
+func pageAfter[T any](items []T, cursor int, limit *uint) (page []T, more bool) {
+	start := cursor + 1
+	end := start + pageLimit(limit)
+	if end >= len(items) {
+		return items[start:], false
+	}
+	return items[start:end], true
+}
+
+func reversed[T any](items []T) []T {
+	reversed := make([]T, len(items))
+	for i, item := range items {
+		reversed[len(items)-1-i] = item
+	}
+	return reversed
+}
+
+func (n *Node) transactionResponse(tx *transaction, options types.SuiTransactionBlockResponseOptions) wireTransactionResponse {
+	resp := wireTransactionResponse{
+		Digest:      tx.digest,
+		TimestampMs: stringNumber(tx.timestampMs),
+		Checkpoint:  stringNumber(tx.checkpoint),
+	}
+	if options.ShowInput {
+		resp.Transaction = &wireTransaction{Data: wireData(tx.data), TxSignatures: nonNil(tx.signatures)}
+	}
+	if options.ShowEffects {
+		effects := tx.effects
+		resp.Effects = &effects
+	}
+	if options.ShowEvents {
+		resp.Events = n.transactionEvents(tx.digest)
+	}
+	if options.ShowObjectChanges {
+		resp.ObjectChanges = tx.objectChanges
+	}
+	if options.ShowBalanceChanges {
+		resp.BalanceChanges = tx.balanceChanges
+	}
+	return resp
+}
+
+func (n *Node) getTransactionBlock(p params) (interface{}, error) {
+	var digest sui_types.TransactionDigest
+	var options types.SuiTransactionBlockResponseOptions
+	if err := p.get(0, &digest); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &options); err != nil {
+		return nil, err
+	}
+	tx, ok := n.byDigest[digest.String()]
+	if !ok {
+		return nil, errorf(CodeInvalidParams, "Could not find the referenced transaction [TransactionDigest(%s)].", digest)
+	}
+	return n.transactionResponse(tx, options), nil
+}
+
+func (n *Node) multiGetTransactionBlocks(p params) (interface{}, error) {
+	var digests []sui_types.TransactionDigest
+	var options types.SuiTransactionBlockResponseOptions
+	if err := p.get(0, &digests); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &options); err != nil {
+		return nil, err
+	}
+	if len(digests) > MaxPageSize {
+		return nil, fmt.Errorf("too many transaction digests, the limit is %d", MaxPageSize)
+	}
+	var responses []wireTransactionResponse
+	for _, digest := range digests {
+		if tx, ok := n.byDigest[digest.String()]; ok {
+			responses = append(responses, n.transactionResponse(tx, options))
+		}
+	}
+	return nonNil(responses), nil
+}
+
+func containsObject(ids []sui_types.ObjectID, id sui_types.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAddress(addresses []sui_types.SuiAddress, address sui_types.SuiAddress) bool {
+	for _, candidate := range addresses {
+		if candidate == address {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTransactionFilter matches the filters of the node, no transaction calls a Move function.
+func matchesTransactionFilter(tx *transaction, filter *types.TransactionFilter) bool {
+	switch {
+	case filter == nil:
+		return true
+	case filter.Checkpoint != nil:
+		return tx.checkpoint == *filter.Checkpoint
+	case filter.MoveFunction != nil:
+		return false
+	case filter.InputObject != nil:
+		return containsObject(tx.inputs, *filter.InputObject)
+	case filter.ChangedObject != nil:
+		return containsObject(tx.changed, *filter.ChangedObject)
+	case filter.FromAddress != nil:
+		return tx.data.Sender == *filter.FromAddress
+	case filter.ToAddress != nil:
+		return containsAddress(tx.recipients, *filter.ToAddress)
+	case filter.FromAndToAddress != nil:
+		from, to := filter.FromAndToAddress.From, filter.FromAndToAddress.To
+		return from != nil && to != nil && tx.data.Sender == *from && containsAddress(tx.recipients, *to)
+	case filter.TransactionKind != nil:
+		return *filter.TransactionKind == types.SuiTransactionBlockKindProgrammableTransaction
+	default:
+		return true
+	}
+}
+
+func (n *Node) queryTransactionBlocks(p params) (interface{}, error) {
+	var query types.SuiTransactionBlockResponseQuery
+	var cursor *sui_types.TransactionDigest
+	var limit *uint
+	var descending bool
+	if err := p.get(0, &query); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &cursor); err != nil {
+		return nil, err
+	}
+	if err := p.get(2, &limit); err != nil {
+		return nil, err
+	}
+	if err := p.get(3, &descending); err != nil {
+		return nil, err
+	}
+	var matched []*transaction
+	for _, tx := range n.transactions {
+		if matchesTransactionFilter(tx, query.Filter) {
+			matched = append(matched, tx)
+		}
+	}
+	if descending {
+		matched = reversed(matched)
+	}
+	after := -1
+	if cursor != nil {
+		for i, tx := range matched {
+			if tx.digest.String() == cursor.String() {
+				after = i
+				break
+			}
+		}
+	}
+	page, more := pageAfter(matched, after, limit)
+	var options types.SuiTransactionBlockResponseOptions
+	if query.Options != nil {
+		options = *query.Options
+	}
+	data := make([]wireTransactionResponse, len(page))
+	for i, tx := range page {
+		data[i] = n.transactionResponse(tx, options)
+	}
+	var next interface{}
+	if len(page) > 0 {
+		next = page[len(page)-1].digest
+	}
+	return wirePage{Data: data, NextCursor: next, HasNextPage: more}, nil
+}
+
+func (n *Node) getTotalTransactionBlocks(p params) (interface{}, error) {
+	return stringNumber(uint64(len(n.transactions))), nil
+}
+
+func (n *Node) transactionEvents(digest sui_types.TransactionDigest) []types.SuiEvent {
+	var events []types.SuiEvent
+	for _, event := range n.events {
+		if event.Id.TxDigest.String() == digest.String() {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func (n *Node) getEvents(p params) (interface{}, error) {
+	var digest sui_types.TransactionDigest
+	if err := p.get(0, &digest); err != nil {
+		return nil, err
+	}
+	return nonNil(n.transactionEvents(digest)), nil
+}
+
+// eventField returns the value at the path, like "/bidder", of the parsed json of an event.
+func eventField(event types.SuiEvent, path string) (interface{}, bool) {
+	var value interface{}
+	data, err := json.Marshal(event.ParsedJson)
+	if err != nil || json.Unmarshal(data, &value) != nil {
+		return nil, false
+	}
+	for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = fields[name]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func matchesEventFilter(event types.SuiEvent, filter types.EventFilter) bool {
+	switch {
+	case filter.Sender != nil:
+		return event.Sender == *filter.Sender
+	case filter.Transaction != nil:
+		return event.Id.TxDigest.String() == filter.Transaction.String()
+	case filter.Package != nil:
+		return event.PackageId == *filter.Package
+	case filter.MoveModule != nil:
+		return event.PackageId == filter.MoveModule.Package && event.TransactionModule == filter.MoveModule.Module
+	case filter.MoveEventType != nil:
+		return event.Type == normalizeType(*filter.MoveEventType)
+	case filter.MoveEventField != nil:
+		value, ok := eventField(event, filter.MoveEventField.Path)
+		if !ok {
+			return false
+		}
+		var expected interface{}
+		data, err := json.Marshal(filter.MoveEventField.Value)
+		if err != nil || json.Unmarshal(data, &expected) != nil {
+			return false
+		}
+		return reflect.DeepEqual(value, expected)
+	case filter.TimeRange != nil:
+		timestamp := uint64(0)
+		if event.TimestampMs != nil {
+			timestamp = event.TimestampMs.Uint64()
+		}
+		return filter.TimeRange.StartTime.Uint64() <= timestamp && timestamp < filter.TimeRange.EndTime.Uint64()
+	case filter.All != nil:
+		for _, sub := range *filter.All {
+			if !matchesEventFilter(event, sub) {
+				return false
+			}
+		}
+		return true
+	case filter.Any != nil:
+		for _, sub := range *filter.Any {
+			if matchesEventFilter(event, sub) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func (n *Node) queryEvents(p params) (interface{}, error) {
+	var filter types.EventFilter
+	var cursor *types.EventId
+	var limit *uint
+	var descending bool
+	if err := p.get(0, &filter); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &cursor); err != nil {
+		return nil, err
+	}
+	if err := p.get(2, &limit); err != nil {
+		return nil, err
+	}
+	if err := p.get(3, &descending); err != nil {
+		return nil, err
+	}
+	var matched []types.SuiEvent
+	for _, event := range n.events {
+		if matchesEventFilter(event, filter) {
+			matched = append(matched, event)
+		}
+	}
+	if descending {
+		matched = reversed(matched)
+	}
+	after := -1
+	if cursor != nil {
+		for i, event := range matched {
+			if event.Id.TxDigest.String() == cursor.TxDigest.String() && event.Id.EventSeq == cursor.EventSeq {
+				after = i
+				break
+			}
+		}
+	}
+	page, more := pageAfter(matched, after, limit)
+	var next interface{}
+	if len(page) > 0 {
+		next = page[len(page)-1].Id
+	}
+	return wirePage{Data: nonNil(page), NextCursor: next, HasNextPage: more}, nil
+}
+
+func (n *Node) getLatestCheckpointSequenceNumber(p params) (interface{}, error) {
+	return stringNumber(uint64(len(n.checkpoints) - 1)), nil
+}
+
+func (n *Node) getCheckpoint(p params) (interface{}, error) {
+	var id string
+	if err := p.get(0, &id); err != nil {
+		return nil, err
+	}
+	if sequenceNumber, err := strconv.ParseUint(id, 10, 64); err == nil {
+		if sequenceNumber < uint64(len(n.checkpoints)) {
+			return n.checkpoints[sequenceNumber], nil
+		}
+	} else {
+		for _, checkpoint := range n.checkpoints {
+			if checkpoint.Digest.String() == id {
+				return checkpoint, nil
+			}
+		}
+	}
+	return nil, errorf(CodeInvalidParams, "Checkpoint %s not found", id)
+}
+
+func (n *Node) getCheckpoints(p params) (interface{}, error) {
+	var cursor *types.SafeSuiBigInt[types.CheckpointSequenceNumber]
+	var limit *uint
+	var descending bool
+	if err := p.get(0, &cursor); err != nil {
+		return nil, err
+	}
+	if err := p.get(1, &limit); err != nil {
+		return nil, err
+	}
+	if err := p.get(2, &descending); err != nil {
+		return nil, err
+	}
+	checkpoints := n.checkpoints
+	if descending {
+		checkpoints = reversed(checkpoints)
+	}
+	after := -1
+	if cursor != nil {
+		for i, checkpoint := range checkpoints {
+			if checkpoint.SequenceNumber.Uint64() == cursor.Uint64() {
+				after = i
+				break
+			}
+		}
+	}
+	page, more := pageAfter(checkpoints, after, limit)
+	var next interface{}
+	if len(page) > 0 {
+		next = page[len(page)-1].SequenceNumber
+	}
+	return wirePage{Data: nonNil(page), NextCursor: next, HasNextPage: more}, nil
+}
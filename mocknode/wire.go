@@ -0,0 +1,270 @@
+package mocknode
+
+import (
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+// The json the node sends where the models of the types package cannot be marshaled, the tagged
+// enums of lib.TagJson only unmarshal.
+
+func addressOwner(owner sui_types.SuiAddress) *types.ObjectOwnerInternal {
+	return &types.ObjectOwnerInternal{AddressOwner: &owner}
+}
+
+type wireParsedData struct {
+	DataType          string                 `json:"dataType"`
+	Type              string                 `json:"type"`
+	HasPublicTransfer bool                   `json:"hasPublicTransfer"`
+	Fields            map[string]interface{} `json:"fields"`
+}
+
+type wireRawData struct {
+	DataType          string                   `json:"dataType"`
+	Type              string                   `json:"type"`
+	HasPublicTransfer bool                     `json:"hasPublicTransfer"`
+	Version           sui_types.SequenceNumber `json:"version"`
+	BcsBytes          lib.Base64Data           `json:"bcsBytes"`
+}
+
+type wireObjectData struct {
+	ObjectId            sui_types.ObjectID          `json:"objectId"`
+	Version             string                      `json:"version"`
+	Digest              sui_types.ObjectDigest      `json:"digest"`
+	Type                string                      `json:"type,omitempty"`
+	Owner               *types.ObjectOwnerInternal  `json:"owner,omitempty"`
+	PreviousTransaction sui_types.TransactionDigest `json:"previousTransaction,omitempty"`
+	StorageRebate       string                      `json:"storageRebate,omitempty"`
+	Content             *wireParsedData             `json:"content,omitempty"`
+	Bcs                 *wireRawData                `json:"bcs,omitempty"`
+}
+
+type wireObjectError struct {
+	Code     string                    `json:"code"`
+	ObjectId sui_types.ObjectID        `json:"object_id"`
+	Version  *sui_types.SequenceNumber `json:"version,omitempty"`
+	Digest   sui_types.ObjectDigest    `json:"digest,omitempty"`
+}
+
+type wireObjectResponse struct {
+	Data  *wireObjectData  `json:"data,omitempty"`
+	Error *wireObjectError `json:"error,omitempty"`
+}
+
+type wirePastObject struct {
+	Status  string      `json:"status"`
+	Details interface{} `json:"details"`
+}
+
+type wirePage struct {
+	Data        interface{} `json:"data"`
+	NextCursor  interface{} `json:"nextCursor"`
+	HasNextPage bool        `json:"hasNextPage"`
+}
+
+type wireBalance struct {
+	CoinType        string            `json:"coinType"`
+	CoinObjectCount uint64            `json:"coinObjectCount"`
+	TotalBalance    string            `json:"totalBalance"`
+	LockedBalance   map[string]string `json:"lockedBalance"`
+	total           uint64
+}
+
+type wireStake struct {
+	StakedSuiId       sui_types.ObjectID `json:"stakedSuiId"`
+	StakeRequestEpoch string             `json:"stakeRequestEpoch"`
+	StakeActiveEpoch  string             `json:"stakeActiveEpoch"`
+	Principal         string             `json:"principal"`
+	Status            string             `json:"status"`
+	EstimatedReward   string             `json:"estimatedReward,omitempty"`
+}
+
+type wireDelegatedStake struct {
+	ValidatorAddress sui_types.SuiAddress `json:"validatorAddress"`
+	StakingPool      sui_types.ObjectID   `json:"stakingPool"`
+	Stakes           []wireStake          `json:"stakes"`
+}
+
+type wireValidatorApy struct {
+	Address string  `json:"address"`
+	Apy     float64 `json:"apy"`
+}
+
+type wireValidatorsApy struct {
+	Epoch string             `json:"epoch"`
+	Apys  []wireValidatorApy `json:"apys"`
+}
+
+type wireOwnedObjectRef struct {
+	Owner     *types.ObjectOwnerInternal `json:"owner"`
+	Reference types.SuiObjectRef         `json:"reference"`
+}
+
+type wireEffects struct {
+	MessageVersion     string                                               `json:"messageVersion"`
+	Status             types.ExecutionStatus                                `json:"status"`
+	ExecutedEpoch      string                                               `json:"executedEpoch"`
+	GasUsed            types.GasCostSummary                                 `json:"gasUsed"`
+	ModifiedAtVersions []types.SuiTransactionBlockEffectsModifiedAtVersions `json:"modifiedAtVersions,omitempty"`
+	TransactionDigest  sui_types.TransactionDigest                          `json:"transactionDigest"`
+	Created            []wireOwnedObjectRef                                 `json:"created,omitempty"`
+	Mutated            []wireOwnedObjectRef                                 `json:"mutated,omitempty"`
+	Deleted            []types.SuiObjectRef                                 `json:"deleted,omitempty"`
+	GasObject          wireOwnedObjectRef                                   `json:"gasObject"`
+	Dependencies       []sui_types.TransactionDigest                        `json:"dependencies"`
+}
+
+type wireObjectChange struct {
+	Type            string                     `json:"type"`
+	Sender          sui_types.SuiAddress       `json:"sender"`
+	Owner           *types.ObjectOwnerInternal `json:"owner,omitempty"`
+	ObjectType      string                     `json:"objectType"`
+	ObjectId        sui_types.ObjectID         `json:"objectId"`
+	Version         string                     `json:"version"`
+	PreviousVersion string                     `json:"previousVersion,omitempty"`
+	Digest          sui_types.ObjectDigest     `json:"digest,omitempty"`
+}
+
+type wireBalanceChange struct {
+	Owner    *types.ObjectOwnerInternal `json:"owner"`
+	CoinType string                     `json:"coinType"`
+	Amount   string                     `json:"amount"`
+}
+
+type wireGasData struct {
+	Payment []types.SuiObjectRef `json:"payment"`
+	Owner   sui_types.SuiAddress `json:"owner"`
+	Price   string               `json:"price"`
+	Budget  string               `json:"budget"`
+}
+
+type wireProgrammableTransaction struct {
+	Kind         string        `json:"kind"`
+	Inputs       []interface{} `json:"inputs"`
+	Transactions []interface{} `json:"transactions"`
+}
+
+type wireTransactionData struct {
+	MessageVersion string                      `json:"messageVersion"`
+	Transaction    wireProgrammableTransaction `json:"transaction"`
+	Sender         sui_types.SuiAddress        `json:"sender"`
+	GasData        wireGasData                 `json:"gasData"`
+}
+
+type wireTransaction struct {
+	Data         wireTransactionData `json:"data"`
+	TxSignatures []lib.Base64Data    `json:"txSignatures"`
+}
+
+type wireTransactionResponse struct {
+	Digest                  sui_types.TransactionDigest `json:"digest"`
+	Transaction             *wireTransaction            `json:"transaction,omitempty"`
+	RawTransaction          lib.Base64Data              `json:"rawTransaction,omitempty"`
+	Effects                 *wireEffects                `json:"effects,omitempty"`
+	Events                  []types.SuiEvent            `json:"events,omitempty"`
+	TimestampMs             string                      `json:"timestampMs,omitempty"`
+	Checkpoint              string                      `json:"checkpoint,omitempty"`
+	ConfirmedLocalExecution *bool                       `json:"confirmedLocalExecution,omitempty"`
+	ObjectChanges           []wireObjectChange          `json:"objectChanges,omitempty"`
+	BalanceChanges          []wireBalanceChange         `json:"balanceChanges,omitempty"`
+}
+
+type wireDryRunResponse struct {
+	Effects        wireEffects         `json:"effects"`
+	Events         []types.SuiEvent    `json:"events"`
+	ObjectChanges  []wireObjectChange  `json:"objectChanges"`
+	BalanceChanges []wireBalanceChange `json:"balanceChanges"`
+	Input          wireTransactionData `json:"input"`
+}
+
+func wireArgument(argument sui_types.Argument) interface{} {
+	switch {
+	case argument.GasCoin != nil:
+		return "GasCoin"
+	case argument.Input != nil:
+		return map[string]uint16{"Input": *argument.Input}
+	case argument.Result != nil:
+		return map[string]uint16{"Result": *argument.Result}
+	case argument.NestedResult != nil:
+		return map[string][]uint16{"NestedResult": {argument.NestedResult.Result1, argument.NestedResult.Result2}}
+	default:
+		return nil
+	}
+}
+
+func wireArguments(arguments []sui_types.Argument) []interface{} {
+	wired := make([]interface{}, len(arguments))
+	for i, argument := range arguments {
+		wired[i] = wireArgument(argument)
+	}
+	return wired
+}
+
+func wireCommand(command sui_types.Command) interface{} {
+	switch {
+	case command.SplitCoins != nil:
+		return map[string][]interface{}{
+			"SplitCoins": {wireArgument(command.SplitCoins.Argument), wireArguments(command.SplitCoins.Arguments)},
+		}
+	case command.MergeCoins != nil:
+		return map[string][]interface{}{
+			"MergeCoins": {wireArgument(command.MergeCoins.Argument), wireArguments(command.MergeCoins.Arguments)},
+		}
+	case command.TransferObjects != nil:
+		return map[string][]interface{}{
+			"TransferObjects": {
+				wireArguments(command.TransferObjects.Arguments), wireArgument(command.TransferObjects.Argument),
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+func wireInput(input sui_types.CallArg) interface{} {
+	if input.Pure != nil {
+		return map[string]interface{}{"type": "pure", "value": lib.Base64Data(*input.Pure)}
+	}
+	ref := input.Object.ImmOrOwnedObject
+	return map[string]interface{}{
+		"type":       "object",
+		"objectType": "immOrOwnedObject",
+		"objectId":   ref.ObjectId,
+		"version":    stringNumber(ref.Version),
+		"digest":     ref.Digest,
+	}
+}
+
+func suiObjectRef(ref sui_types.ObjectRef) types.SuiObjectRef {
+	return types.SuiObjectRef{Digest: ref.Digest, ObjectId: ref.ObjectId.String(), Version: ref.Version}
+}
+
+func wireData(data *sui_types.TransactionDataV1) wireTransactionData {
+	pt := data.Kind.ProgrammableTransaction
+	wired := wireTransactionData{
+		MessageVersion: "v1",
+		Transaction: wireProgrammableTransaction{
+			Kind:         "ProgrammableTransaction",
+			Inputs:       make([]interface{}, len(pt.Inputs)),
+			Transactions: make([]interface{}, len(pt.Commands)),
+		},
+		Sender: data.Sender,
+		GasData: wireGasData{
+			Payment: make([]types.SuiObjectRef, len(data.GasData.Payment)),
+			Owner:   data.GasData.Owner,
+			Price:   stringNumber(data.GasData.Price),
+			Budget:  stringNumber(data.GasData.Budget),
+		},
+	}
+	for i, input := range pt.Inputs {
+		wired.Transaction.Inputs[i] = wireInput(input)
+	}
+	for i, command := range pt.Commands {
+		wired.Transaction.Transactions[i] = wireCommand(command)
+	}
+	for i, ref := range data.GasData.Payment {
+		wired.GasData.Payment[i] = suiObjectRef(*ref)
+	}
+	return wired
+}
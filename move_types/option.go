@@ -0,0 +1,26 @@
+package move_types
+
+// Option mirrors `0x1::option::Option<T>`, which Move stores as a vector of zero or one element.
+type Option[T any] struct {
+	Vec []T
+}
+
+func Some[T any](value T) Option[T] {
+	return Option[T]{Vec: []T{value}}
+}
+
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+func (o Option[T]) IsSome() bool {
+	return len(o.Vec) > 0
+}
+
+// Get returns the contained value, ok is false when the option is none.
+func (o Option[T]) Get() (value T, ok bool) {
+	if len(o.Vec) == 0 {
+		return value, false
+	}
+	return o.Vec[0], true
+}
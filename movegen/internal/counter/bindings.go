@@ -0,0 +1,199 @@
+// Code generated by movegen. DO NOT EDIT.
+
+package counter
+
+import (
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/move_types"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+// PackageAddress is the address of the package in the type of its structs.
+const PackageAddress = "0x00000000000000000000000000000000000000000000000000000000000000c0"
+
+// PackageId is the package the move calls are sent to, set it after an upgrade.
+var PackageId = mustObjectId("0x00000000000000000000000000000000000000000000000000000000000000c0")
+
+func mustObjectId(hex string) sui_types.ObjectID {
+	id, err := sui_types.NewObjectIdFromHex(hex)
+	if err != nil {
+		panic(err)
+	}
+	return *id
+}
+
+// CounterCounter mirrors `0xc0::counter::Counter`.
+type CounterCounter struct {
+	Id    sui_types.UID                  `json:"id"`
+	Value uint64                         `json:"value"`
+	Owner sui_types.SuiAddress           `json:"owner"`
+	Label string                         `json:"label"`
+	Limit move_types.Option[bcs.Uint128] `json:"limit"`
+}
+
+const CounterCounterType = "0xc0::counter::Counter"
+
+func DecodeCounterCounterBcs(data []byte) (*CounterCounter, error) {
+	var v CounterCounter
+	return &v, lib.UnmarshalBCS(data, &v)
+}
+
+// DecodeCounterCounterJson decodes the json fields of the struct, like the ParsedJson of an event.
+func DecodeCounterCounterJson(value any) (*CounterCounter, error) {
+	var v CounterCounter
+	return &v, types.DecodeMoveJson(value, &v)
+}
+
+// CounterForeign is not generated, a field of `0xc0::counter::Foreign` has no Go type.
+
+// CounterIncremented mirrors `0xc0::counter::Incremented`.
+type CounterIncremented struct {
+	CounterId sui_types.ID `json:"counter_id"`
+	By        uint64       `json:"by"`
+	History   []uint8      `json:"history"`
+}
+
+const CounterIncrementedType = "0xc0::counter::Incremented"
+
+func DecodeCounterIncrementedBcs(data []byte) (*CounterIncremented, error) {
+	var v CounterIncremented
+	return &v, lib.UnmarshalBCS(data, &v)
+}
+
+// DecodeCounterIncrementedJson decodes the json fields of the struct, like the ParsedJson of an event.
+func DecodeCounterIncrementedJson(value any) (*CounterIncremented, error) {
+	var v CounterIncremented
+	return &v, types.DecodeMoveJson(value, &v)
+}
+
+func DecodeCounterIncrementedEvent(event *types.SuiEvent) (*CounterIncremented, error) {
+	return types.DecodeMoveEvent[CounterIncremented](event)
+}
+
+// CounterCounterCall adds a call to `0xc0::counter::counter` to ptb.
+func CounterCounterCall(
+	ptb *sui_types.ProgrammableTransactionBuilder,
+	arg0 sui_types.Argument,
+) (sui_types.Argument, error) {
+	return ptb.Command(
+		sui_types.Command{
+			MoveCall: &sui_types.ProgrammableMoveCall{
+				Package:   PackageId,
+				Module:    "counter",
+				Function:  "counter",
+				Arguments: []sui_types.Argument{arg0},
+			},
+		},
+	), nil
+}
+
+// CounterCreate adds a call to `0xc0::counter::create` to ptb.
+func CounterCreate(
+	ptb *sui_types.ProgrammableTransactionBuilder,
+	arg0 string,
+	arg1 move_types.Option[uint64],
+	arg2 []sui_types.SuiAddress,
+) (sui_types.Argument, error) {
+	arg0Input, err := ptb.Pure(arg0)
+	if err != nil {
+		return sui_types.Argument{}, err
+	}
+	arg1Input, err := ptb.Pure(arg1)
+	if err != nil {
+		return sui_types.Argument{}, err
+	}
+	arg2Input, err := ptb.Pure(arg2)
+	if err != nil {
+		return sui_types.Argument{}, err
+	}
+	return ptb.Command(
+		sui_types.Command{
+			MoveCall: &sui_types.ProgrammableMoveCall{
+				Package:   PackageId,
+				Module:    "counter",
+				Function:  "create",
+				Arguments: []sui_types.Argument{arg0Input, arg1Input, arg2Input},
+			},
+		},
+	), nil
+}
+
+// CounterIncrement adds a call to `0xc0::counter::increment` to ptb.
+func CounterIncrement(
+	ptb *sui_types.ProgrammableTransactionBuilder,
+	arg0 sui_types.Argument,
+	arg1 uint64,
+) (sui_types.Argument, error) {
+	arg1Input, err := ptb.Pure(arg1)
+	if err != nil {
+		return sui_types.Argument{}, err
+	}
+	return ptb.Command(
+		sui_types.Command{
+			MoveCall: &sui_types.ProgrammableMoveCall{
+				Package:   PackageId,
+				Module:    "counter",
+				Function:  "increment",
+				Arguments: []sui_types.Argument{arg0, arg1Input},
+			},
+		},
+	), nil
+}
+
+// PoolHolder is not generated, a field of `0xc0::pool::Holder` has no Go type.
+
+// PoolPool mirrors `0xc0::pool::Pool`.
+type PoolPool[T1 any] struct {
+	Id       sui_types.UID                                          `json:"id"`
+	Balance  sui_types.Balance                                      `json:"balance"`
+	Items    []T1                                                   `json:"items"`
+	Counters sui_types.VecMap[sui_types.SuiAddress, CounterCounter] `json:"counters"`
+}
+
+const PoolPoolType = "0xc0::pool::Pool"
+
+func DecodePoolPoolBcs[T1 any](data []byte) (*PoolPool[T1], error) {
+	var v PoolPool[T1]
+	return &v, lib.UnmarshalBCS(data, &v)
+}
+
+// DecodePoolPoolJson decodes the json fields of the struct, like the ParsedJson of an event.
+func DecodePoolPoolJson[T1 any](value any) (*PoolPool[T1], error) {
+	var v PoolPool[T1]
+	return &v, types.DecodeMoveJson(value, &v)
+}
+
+// PoolDeposit adds a call to `0xc0::pool::deposit` to ptb.
+func PoolDeposit(
+	ptb *sui_types.ProgrammableTransactionBuilder,
+	t0 move_types.TypeTag,
+	arg0 sui_types.Argument,
+	arg1 sui_types.Argument,
+	arg2 bcs.Uint128,
+) (sui_types.Argument, error) {
+	arg2Input, err := ptb.Pure(arg2)
+	if err != nil {
+		return sui_types.Argument{}, err
+	}
+	return ptb.Command(
+		sui_types.Command{
+			MoveCall: &sui_types.ProgrammableMoveCall{
+				Package:       PackageId,
+				Module:        "pool",
+				Function:      "deposit",
+				TypeArguments: []move_types.TypeTag{t0},
+				Arguments:     []sui_types.Argument{arg0, arg1, arg2Input},
+			},
+		},
+	), nil
+}
+
+// RegisterStructs adds the object structs of the package to registry.
+func RegisterStructs(registry *types.MoveStructRegistry) error {
+	if err := types.RegisterMoveStruct[CounterCounter](registry, CounterCounterType); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,542 @@
+// Package movegen generates Go bindings for the modules of a Move package from their
+// normalized form, see Client.GetNormalizedMoveModulesByPackage.
+//
+// For every public or entry function it emits a function adding the move call to a
+// sui_types.ProgrammableTransactionBuilder, pure arguments are BCS encoded and object
+// arguments are passed as sui_types.Argument. It is named after the module and the function, with
+// a Call suffix when a generated struct has that name. For every struct whose fields can be mapped
+// it emits a Go struct with the BCS layout and json tags of the move struct, along with
+// BCS, json and event decoders. Structs of different modules which get the same Go name, like
+// coin_x::Y and coin::XY, fail the generation.
+package movegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+type Modules = map[string]types.SuiMoveNormalizedModule
+
+type Config struct {
+	// GoPackage is the name of the generated Go package.
+	GoPackage string
+	// PackageId is the package the calls are sent to, it defaults to the address of the modules.
+	PackageId *sui_types.ObjectID
+}
+
+// LoadModules reads modules saved as the json result of getNormalizedMoveModulesByPackage.
+func LoadModules(path string) (Modules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var modules Modules
+	return modules, json.Unmarshal(data, &modules)
+}
+
+// SaveModules writes modules in the format read by LoadModules.
+func SaveModules(path string, modules Modules) error {
+	data, err := json.MarshalIndent(modules, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Generate returns the gofmt-ed source of the bindings of modules.
+func Generate(config Config, modules Modules) ([]byte, error) {
+	if len(modules) == 0 {
+		return nil, errors.New("no modules to generate")
+	}
+	if config.GoPackage == "" {
+		return nil, errors.New("no go package name")
+	}
+	g := &generator{
+		modules:  modules,
+		imports:  make(map[string]bool),
+		structs:  make(map[string]bool),
+		declared: make(map[string]bool),
+	}
+	for _, name := range sortedKeys(modules) {
+		address, err := sui_types.NewAddressFromHex(modules[name].Address)
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", name, err)
+		}
+		if g.address == nil {
+			g.address = address
+		} else if *g.address != *address {
+			return nil, fmt.Errorf("module %s is not in package %s", name, g.address)
+		}
+	}
+	packageId := g.address
+	if config.PackageId != nil {
+		packageId = config.PackageId
+	}
+	g.resolveStructs()
+	if err := g.declareStructs(); err != nil {
+		return nil, err
+	}
+
+	for _, moduleName := range sortedKeys(modules) {
+		module := modules[moduleName]
+		for _, structName := range sortedKeys(module.Structs) {
+			g.genStruct(moduleName, structName, module.Structs[structName])
+		}
+		for _, functionName := range sortedKeys(module.ExposedFunctions) {
+			g.genFunction(moduleName, functionName, module.ExposedFunctions[functionName])
+		}
+	}
+	g.genRegister()
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by movegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", config.GoPackage)
+	g.imports[suiTypesImport] = true
+	out.WriteString("import (\n")
+	for _, path := range sortedKeys(g.imports) {
+		fmt.Fprintf(&out, "\t%q\n", path)
+	}
+	out.WriteString(")\n\n")
+	fmt.Fprintf(&out, "// PackageAddress is the address of the package in the type of its structs.\n")
+	fmt.Fprintf(&out, "const PackageAddress = %q\n\n", g.address.String())
+	fmt.Fprintf(&out, "// PackageId is the package the move calls are sent to, set it after an upgrade.\n")
+	fmt.Fprintf(&out, "var PackageId = mustObjectId(%q)\n\n", packageId.String())
+	out.WriteString("func mustObjectId(hex string) sui_types.ObjectID {\n")
+	out.WriteString("\tid, err := sui_types.NewObjectIdFromHex(hex)\n")
+	out.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n\treturn *id\n}\n")
+	out.Write(g.body.Bytes())
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return source, nil
+}
+
+const (
+	bcsImport       = "github.com/fardream/go-bcs/bcs"
+	libImport       = "github.com/thorli9527/sui-wallet-sdk/lib"
+	moveTypesImport = "github.com/thorli9527/sui-wallet-sdk/move_types"
+	suiTypesImport  = "github.com/thorli9527/sui-wallet-sdk/sui_types"
+	typesImport     = "github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+type generator struct {
+	modules Modules
+	address *sui_types.SuiAddress
+	imports map[string]bool
+	// structs has the "module::Struct" of the package structs which have a Go type
+	structs map[string]bool
+	objects []string
+	// declared has the Go identifiers taken in the generated package
+	declared map[string]bool
+	body     bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.body, format, args...)
+}
+
+// resolveStructs finds the package structs whose fields all map to Go types, a struct
+// depending on one which does not map is left out as well.
+func (g *generator) resolveStructs() {
+	for moduleName, module := range g.modules {
+		for structName := range module.Structs {
+			g.structs[moduleName+"::"+structName] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for moduleName, module := range g.modules {
+			for structName, normalized := range module.Structs {
+				key := moduleName + "::" + structName
+				if !g.structs[key] {
+					continue
+				}
+				for _, field := range normalized.Fields {
+					if _, err := g.fieldType(field.Type, false); err != nil {
+						g.structs[key] = false
+						changed = true
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+// declareStructs takes the identifiers of the package and of the generated structs, before the
+// functions are named. Structs of different modules can get the same Go name, like coin_x::Y and
+// coin::XY, which is an error.
+func (g *generator) declareStructs() error {
+	for _, name := range []string{"PackageAddress", "PackageId", "mustObjectId", "RegisterStructs"} {
+		g.declared[name] = true
+	}
+	// declaredBy has the move struct of the identifiers declared for a struct
+	declaredBy := make(map[string]string)
+	for _, moduleName := range sortedKeys(g.modules) {
+		for _, structName := range sortedKeys(g.modules[moduleName].Structs) {
+			moveType := moduleName + "::" + structName
+			if !g.structs[moveType] {
+				continue
+			}
+			goName := g.goStructName(moduleName, structName)
+			names := []string{goName, goName + "Type"}
+			for _, decoder := range []string{"Bcs", "Json", "Event"} {
+				names = append(names, "Decode"+goName+decoder)
+			}
+			for _, name := range names {
+				if other, ok := declaredBy[name]; ok {
+					return fmt.Errorf("structs %s and %s both declare %s", other, moveType, name)
+				}
+				if g.declared[name] {
+					return fmt.Errorf("struct %s declares %s, which is taken by the package", moveType, name)
+				}
+				g.declared[name] = true
+				declaredBy[name] = moveType
+			}
+		}
+	}
+	return nil
+}
+
+// goFunctionName names the builder of a function, suffixed with Call while the name is taken, like
+// by the struct Counter of the module counter for its function counter.
+func (g *generator) goFunctionName(moduleName, functionName string) string {
+	goName := exportedName(moduleName) + exportedName(functionName)
+	for g.declared[goName] {
+		goName += "Call"
+	}
+	g.declared[goName] = true
+	return goName
+}
+
+func (g *generator) goStructName(moduleName, structName string) string {
+	return exportedName(moduleName) + exportedName(structName)
+}
+
+func (g *generator) moveStructType(moduleName, structName string) string {
+	return fmt.Sprintf("%s::%s::%s", g.address.ShortString(), moduleName, structName)
+}
+
+func (g *generator) genStruct(moduleName, structName string, normalized types.SuiMoveNormalizedStruct) {
+	goName := g.goStructName(moduleName, structName)
+	moveType := g.moveStructType(moduleName, structName)
+	if !g.structs[moduleName+"::"+structName] {
+		g.printf("\n// %s is not generated, a field of `%s` has no Go type.\n", goName, moveType)
+		return
+	}
+	var typeParams, typeArgs []string
+	for i, param := range normalized.TypeParameters {
+		if !param.IsPhantom {
+			typeParams = append(typeParams, fmt.Sprintf("T%d any", i))
+			typeArgs = append(typeArgs, fmt.Sprintf("T%d", i))
+		}
+	}
+	declParams, useArgs := "", ""
+	if len(typeParams) > 0 {
+		declParams = "[" + strings.Join(typeParams, ", ") + "]"
+		useArgs = "[" + strings.Join(typeArgs, ", ") + "]"
+	}
+
+	g.printf("\n// %s mirrors `%s`.\n", goName, moveType)
+	g.printf("type %s%s struct {\n", goName, declParams)
+	for _, field := range normalized.Fields {
+		goType, _ := g.fieldType(field.Type, true)
+		g.printf("\t%s %s `json:\"%s\"`\n", exportedName(field.Name), goType, field.Name)
+	}
+	g.printf("}\n\n")
+	g.printf("const %sType = %q\n", goName, moveType)
+
+	g.imports[libImport] = true
+	g.imports[typesImport] = true
+	g.printf("\nfunc Decode%sBcs%s(data []byte) (*%s%s, error) {\n", goName, declParams, goName, useArgs)
+	g.printf("\tvar v %s%s\n\treturn &v, lib.UnmarshalBCS(data, &v)\n}\n", goName, useArgs)
+	g.printf("\n// Decode%sJson decodes the json fields of the struct, like the ParsedJson of an event.\n", goName)
+	g.printf("func Decode%sJson%s(value any) (*%s%s, error) {\n", goName, declParams, goName, useArgs)
+	g.printf("\tvar v %s%s\n\treturn &v, types.DecodeMoveJson(value, &v)\n}\n", goName, useArgs)
+
+	abilities := normalized.Abilities
+	if abilities.Has(types.SuiMoveAbilityKey) {
+		if len(typeParams) == 0 {
+			g.objects = append(g.objects, goName)
+		}
+		return
+	}
+	if abilities.Has(types.SuiMoveAbilityCopy) && abilities.Has(types.SuiMoveAbilityDrop) {
+		g.printf("\nfunc Decode%sEvent%s(event *types.SuiEvent) (*%s%s, error) {\n", goName, declParams, goName, useArgs)
+		g.printf("\treturn types.DecodeMoveEvent[%s%s](event)\n}\n", goName, useArgs)
+	}
+}
+
+func (g *generator) genRegister() {
+	if len(g.objects) == 0 {
+		return
+	}
+	g.imports[typesImport] = true
+	g.printf("\n// RegisterStructs adds the object structs of the package to registry.\n")
+	g.printf("func RegisterStructs(registry *types.MoveStructRegistry) error {\n")
+	for _, goName := range g.objects {
+		g.printf("\tif err := types.RegisterMoveStruct[%s](registry, %sType); err != nil {\n", goName, goName)
+		g.printf("\t\treturn err\n\t}\n")
+	}
+	g.printf("\treturn nil\n}\n")
+}
+
+type callParam struct {
+	name   string
+	goType string
+	pure   bool
+}
+
+func (g *generator) genFunction(moduleName, functionName string, function types.SuiMoveNormalizedFunction) {
+	if function.Visibility != types.SuiMoveVisibilityPublic && !function.IsEntry {
+		return
+	}
+	goName := g.goFunctionName(moduleName, functionName)
+	var params []callParam
+	for i, param := range function.Parameters {
+		if isTxContext(param) {
+			continue
+		}
+		name := fmt.Sprintf("arg%d", i)
+		if goType, ok := g.pureType(param); ok {
+			params = append(params, callParam{name: name, goType: goType, pure: true})
+		} else {
+			params = append(params, callParam{name: name, goType: "sui_types.Argument"})
+		}
+	}
+
+	g.printf("\n// %s adds a call to `%s::%s::%s` to ptb.\n", goName, g.address.ShortString(), moduleName, functionName)
+	g.printf("func %s(\n\tptb *sui_types.ProgrammableTransactionBuilder,\n", goName)
+	var typeArgs []string
+	for i := range function.TypeParameters {
+		g.imports[moveTypesImport] = true
+		g.printf("\tt%d move_types.TypeTag,\n", i)
+		typeArgs = append(typeArgs, fmt.Sprintf("t%d", i))
+	}
+	for _, param := range params {
+		g.printf("\t%s %s,\n", param.name, param.goType)
+	}
+	g.printf(") (sui_types.Argument, error) {\n")
+	var arguments []string
+	for _, param := range params {
+		if !param.pure {
+			arguments = append(arguments, param.name)
+			continue
+		}
+		g.printf("\t%sInput, err := ptb.Pure(%s)\n", param.name, param.name)
+		g.printf("\tif err != nil {\n\t\treturn sui_types.Argument{}, err\n\t}\n")
+		arguments = append(arguments, param.name+"Input")
+	}
+	g.printf("\treturn ptb.Command(\n\t\tsui_types.Command{\n\t\t\tMoveCall: &sui_types.ProgrammableMoveCall{\n")
+	g.printf("\t\t\t\tPackage:  PackageId,\n\t\t\t\tModule:   %q,\n\t\t\t\tFunction: %q,\n", moduleName, functionName)
+	if len(typeArgs) > 0 {
+		g.printf("\t\t\t\tTypeArguments: []move_types.TypeTag{%s},\n", strings.Join(typeArgs, ", "))
+	}
+	if len(arguments) > 0 {
+		g.printf("\t\t\t\tArguments: []sui_types.Argument{%s},\n", strings.Join(arguments, ", "))
+	}
+	g.printf("\t\t\t},\n\t\t},\n\t), nil\n}\n")
+}
+
+// pureType returns the Go type of a parameter which is passed as a pure BCS value.
+func (g *generator) pureType(t types.SuiMoveNormalizedType) (string, bool) {
+	switch {
+	case t.Primitive != "":
+		goType, ok := g.primitiveType(t.Primitive)
+		return goType, ok && t.Primitive != types.SuiMoveNormalizedTypeSigner
+	case t.Vector != nil:
+		elem, ok := g.pureType(*t.Vector)
+		return "[]" + elem, ok
+	case t.Struct != nil:
+		switch structKey(t.Struct) {
+		case "0x1::string::String", "0x1::ascii::String":
+			return "string", true
+		case "0x2::object::ID":
+			return "sui_types.ObjectID", true
+		case "0x1::option::Option":
+			if len(t.Struct.TypeArguments) != 1 {
+				return "", false
+			}
+			elem, ok := g.pureType(t.Struct.TypeArguments[0])
+			if ok {
+				g.imports[moveTypesImport] = true
+			}
+			return "move_types.Option[" + elem + "]", ok
+		}
+	}
+	return "", false
+}
+
+func (g *generator) primitiveType(primitive string) (string, bool) {
+	switch primitive {
+	case types.SuiMoveNormalizedTypeBool:
+		return "bool", true
+	case types.SuiMoveNormalizedTypeU8:
+		return "uint8", true
+	case types.SuiMoveNormalizedTypeU16:
+		return "uint16", true
+	case types.SuiMoveNormalizedTypeU32:
+		return "uint32", true
+	case types.SuiMoveNormalizedTypeU64:
+		return "uint64", true
+	case types.SuiMoveNormalizedTypeU128:
+		g.imports[bcsImport] = true
+		return "bcs.Uint128", true
+	case types.SuiMoveNormalizedTypeU256:
+		// little endian, like the BCS encoding
+		return "[32]uint8", true
+	case types.SuiMoveNormalizedTypeAddress, types.SuiMoveNormalizedTypeSigner:
+		return "sui_types.SuiAddress", true
+	}
+	return "", false
+}
+
+// fieldType returns the Go type of a struct field, imports are recorded only when emit is set.
+func (g *generator) fieldType(t types.SuiMoveNormalizedType, emit bool) (string, error) {
+	switch {
+	case t.Primitive != "":
+		imports := g.imports
+		if !emit {
+			g.imports = make(map[string]bool)
+		}
+		goType, ok := g.primitiveType(t.Primitive)
+		g.imports = imports
+		if !ok {
+			return "", fmt.Errorf("unknown primitive %s", t.Primitive)
+		}
+		return goType, nil
+	case t.Vector != nil:
+		elem, err := g.fieldType(*t.Vector, emit)
+		return "[]" + elem, err
+	case t.TypeParameter != nil:
+		return fmt.Sprintf("T%d", *t.TypeParameter), nil
+	case t.Struct != nil:
+		return g.structFieldType(t.Struct, emit)
+	}
+	return "", fmt.Errorf("no field type for %s", t.String())
+}
+
+func (g *generator) structFieldType(s *types.SuiMoveNormalizedStructType, emit bool) (string, error) {
+	typeArg := func(i int) (string, error) {
+		if i >= len(s.TypeArguments) {
+			return "", fmt.Errorf("missing type argument of %s", structKey(s))
+		}
+		return g.fieldType(s.TypeArguments[i], emit)
+	}
+	switch structKey(s) {
+	case "0x1::string::String", "0x1::ascii::String":
+		return "string", nil
+	case "0x2::object::UID":
+		return "sui_types.UID", nil
+	case "0x2::object::ID":
+		return "sui_types.ID", nil
+	case "0x2::balance::Balance":
+		return "sui_types.Balance", nil
+	case "0x2::balance::Supply":
+		return "sui_types.Supply", nil
+	case "0x2::coin::Coin":
+		return "sui_types.Coin", nil
+	case "0x2::coin::TreasuryCap":
+		return "sui_types.TreasuryCap", nil
+	case "0x2::url::Url":
+		return "sui_types.Url", nil
+	case "0x1::option::Option":
+		elem, err := typeArg(0)
+		if err == nil && emit {
+			g.imports[moveTypesImport] = true
+		}
+		return "move_types.Option[" + elem + "]", err
+	case "0x2::vec_map::VecMap":
+		key, err := typeArg(0)
+		if err != nil {
+			return "", err
+		}
+		value, err := typeArg(1)
+		return "sui_types.VecMap[" + key + ", " + value + "]", err
+	}
+
+	address, err := sui_types.NewAddressFromHex(s.Address)
+	if err != nil || *address != *g.address {
+		return "", fmt.Errorf("struct %s is not in the package", structKey(s))
+	}
+	module, ok := g.modules[s.Module]
+	if !ok {
+		return "", fmt.Errorf("no module %s", s.Module)
+	}
+	normalized, ok := module.Structs[s.Name]
+	if !ok || !g.structs[s.Module+"::"+s.Name] {
+		return "", fmt.Errorf("no Go type for struct %s", structKey(s))
+	}
+	var args []string
+	for i, param := range normalized.TypeParameters {
+		if param.IsPhantom {
+			continue
+		}
+		arg, err := typeArg(i)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, arg)
+	}
+	goType := g.goStructName(s.Module, s.Name)
+	if len(args) > 0 {
+		goType += "[" + strings.Join(args, ", ") + "]"
+	}
+	return goType, nil
+}
+
+func isTxContext(t types.SuiMoveNormalizedType) bool {
+	inner := t.Reference
+	if inner == nil {
+		inner = t.MutableReference
+	}
+	return inner != nil && inner.Struct != nil && structKey(inner.Struct) == "0x2::tx_context::TxContext"
+}
+
+// structKey returns address::module::name with a short address, without type arguments.
+func structKey(s *types.SuiMoveNormalizedStructType) string {
+	address := s.Address
+	if parsed, err := sui_types.NewAddressFromHex(address); err == nil {
+		address = parsed.ShortString()
+	}
+	return fmt.Sprintf("%s::%s::%s", address, s.Module, s.Name)
+}
+
+// exportedName converts a move identifier like `mint_to` into `MintTo`.
+func exportedName(name string) string {
+	var out []rune
+	upper := true
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,127 @@
+package movegen_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/move_types"
+	"github.com/thorli9527/sui-wallet-sdk/movegen"
+	"github.com/thorli9527/sui-wallet-sdk/movegen/internal/counter"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+	"github.com/thorli9527/sui-wallet-sdk/types"
+)
+
+// internal/counter is generated from testdata/counter.json, regenerate it with
+// go run ./cmd/movegen -modules movegen/testdata/counter.json -go-package counter -out movegen/internal/counter/bindings.go
+func TestGenerate_UpToDate(t *testing.T) {
+	modules, err := movegen.LoadModules("testdata/counter.json")
+	require.NoError(t, err)
+	source, err := movegen.Generate(movegen.Config{GoPackage: "counter"}, modules)
+	require.NoError(t, err)
+	generated, err := os.ReadFile("internal/counter/bindings.go")
+	require.NoError(t, err)
+	require.Equal(t, string(generated), string(source))
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	modules, err := movegen.LoadModules("testdata/counter.json")
+	require.NoError(t, err)
+	_, err = movegen.Generate(movegen.Config{}, modules)
+	require.Error(t, err)
+
+	other := modules["pool"]
+	other.Address = "0xc1"
+	modules["pool"] = other
+	_, err = movegen.Generate(movegen.Config{GoPackage: "counter"}, modules)
+	require.Error(t, err)
+
+	// counter_incremented::X and counter::IncrementedX are both CounterIncrementedX in Go
+	modules, err = movegen.LoadModules("testdata/counter.json")
+	require.NoError(t, err)
+	module := modules["counter"]
+	incremented := module.Structs["Incremented"]
+	module.Structs["IncrementedX"] = incremented
+	modules["counter_incremented"] = types.SuiMoveNormalizedModule{
+		FileFormatVersion: module.FileFormatVersion,
+		Address:           module.Address,
+		Name:              "counter_incremented",
+		Structs:           map[string]types.SuiMoveNormalizedStruct{"X": incremented},
+	}
+	_, err = movegen.Generate(movegen.Config{GoPackage: "counter"}, modules)
+	require.ErrorContains(t, err, "structs counter::IncrementedX and counter_incremented::X both declare CounterIncrementedX")
+}
+
+func TestGeneratedCalls(t *testing.T) {
+	ptb := sui_types.NewProgrammableTransactionBuilder()
+	counterArg, err := ptb.Obj(sui_types.ObjectArg{ImmOrOwnedObject: &sui_types.ObjectRef{}})
+	require.NoError(t, err)
+	_, err = counter.CounterIncrement(ptb, counterArg, 7)
+	require.NoError(t, err)
+
+	sui := move_types.TypeTag{Struct: &move_types.StructTag{Address: move_types.AccountAddress{31: 2}, Module: "sui", Name: "SUI"}}
+	coinArg := sui_types.Argument{GasCoin: &lib.EmptyEnum{}}
+	result, err := counter.PoolDeposit(ptb, sui, counterArg, coinArg, *bcs.NewUint128FromUint64(5, 0))
+	require.NoError(t, err)
+	require.Equal(t, uint16(1), *result.Result)
+
+	// the function counter of the module counter is named after the struct Counter, its builder is suffixed
+	_, err = counter.CounterCounterCall(ptb, counterArg)
+	require.NoError(t, err)
+
+	tx := ptb.Finish()
+	require.Len(t, tx.Commands, 3)
+	increment := tx.Commands[0].MoveCall
+	require.Equal(t, counter.PackageId, increment.Package)
+	require.Equal(t, move_types.Identifier("increment"), increment.Function)
+	require.Len(t, increment.Arguments, 2)
+	require.Equal(t, []byte{7, 0, 0, 0, 0, 0, 0, 0}, *tx.Inputs[*increment.Arguments[1].Input].Pure)
+	deposit := tx.Commands[1].MoveCall
+	require.Equal(t, []move_types.TypeTag{sui}, deposit.TypeArguments)
+	require.Equal(t, coinArg, deposit.Arguments[1])
+	require.Equal(t, move_types.Identifier("counter"), tx.Commands[2].MoveCall.Function)
+}
+
+func TestGeneratedDecoders(t *testing.T) {
+	id, _ := sui_types.NewObjectIdFromHex("0x5")
+	owner, _ := sui_types.NewAddressFromHex("0x1")
+	want := counter.CounterCounter{
+		Id:    sui_types.UID{Id: sui_types.ID{Bytes: *id}},
+		Value: 7,
+		Owner: *owner,
+		Label: "hi",
+		Limit: move_types.Some(*bcs.NewUint128FromUint64(100, 0)),
+	}
+	data, err := bcs.Marshal(want)
+	require.NoError(t, err)
+	decoded, err := counter.DecodeCounterCounterBcs(data)
+	require.NoError(t, err)
+	require.Equal(t, want, *decoded)
+
+	var parsed any
+	require.NoError(t, json.Unmarshal([]byte(`{"id":{"id":"0x5"},"value":"7","owner":"0x1","label":"hi","limit":"100"}`), &parsed))
+	decoded, err = counter.DecodeCounterCounterJson(parsed)
+	require.NoError(t, err)
+	require.Equal(t, want, *decoded)
+
+	incremented := counter.CounterIncremented{CounterId: sui_types.ID{Bytes: *id}, By: 3, History: []byte{1, 2}}
+	data, err = bcs.Marshal(incremented)
+	require.NoError(t, err)
+	event, err := counter.DecodeCounterIncrementedEvent(&types.SuiEvent{Bcs: base58.Encode(data)})
+	require.NoError(t, err)
+	require.Equal(t, incremented, *event)
+	event, err = counter.DecodeCounterIncrementedEvent(
+		&types.SuiEvent{ParsedJson: map[string]any{"counter_id": "0x5", "by": "3", "history": []any{1.0, 2.0}}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, incremented, *event)
+
+	registry := types.NewMoveStructRegistry()
+	require.NoError(t, counter.RegisterStructs(registry))
+	_, ok := registry.Lookup("0x00000000000000000000000000000000000000000000000000000000000000c0::counter::Counter")
+	require.True(t, ok)
+}
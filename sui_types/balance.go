@@ -0,0 +1,19 @@
+package sui_types
+
+import "github.com/thorli9527/sui-wallet-sdk/move_types"
+
+const (
+	BalanceModuleName = move_types.Identifier("balance")
+	BalanceStructName = move_types.Identifier("Balance")
+	SupplyStructName  = move_types.Identifier("Supply")
+)
+
+// Balance mirrors `0x2::balance::Balance<T>`, the phantom type parameter is not part of the layout.
+type Balance struct {
+	Value uint64
+}
+
+// Supply mirrors `0x2::balance::Supply<T>`.
+type Supply struct {
+	Value uint64
+}
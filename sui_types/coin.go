@@ -0,0 +1,45 @@
+package sui_types
+
+import "github.com/thorli9527/sui-wallet-sdk/move_types"
+
+const (
+	CoinModuleName         = move_types.Identifier("coin")
+	CoinStructName         = move_types.Identifier("Coin")
+	TreasuryCapStructName  = move_types.Identifier("TreasuryCap")
+	CoinMetadataStructName = move_types.Identifier("CoinMetadata")
+	CoinFromBalanceFunName = move_types.Identifier("from_balance")
+
+	SuiModuleName = move_types.Identifier("sui")
+	SuiStructName = move_types.Identifier("SUI")
+)
+
+// Coin mirrors `0x2::coin::Coin<T>`.
+type Coin struct {
+	Id      UID
+	Balance Balance
+}
+
+func (c Coin) Value() uint64 {
+	return c.Balance.Value
+}
+
+// TreasuryCap mirrors `0x2::coin::TreasuryCap<T>`.
+type TreasuryCap struct {
+	Id          UID
+	TotalSupply Supply
+}
+
+// CoinMetadata mirrors `0x2::coin::CoinMetadata<T>`.
+type CoinMetadata struct {
+	Id          UID
+	Decimals    uint8
+	Name        string
+	Symbol      string
+	Description string
+	IconUrl     move_types.Option[Url]
+}
+
+// Url mirrors `0x2::url::Url`.
+type Url struct {
+	Url string
+}
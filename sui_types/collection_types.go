@@ -0,0 +1,34 @@
+package sui_types
+
+import "github.com/thorli9527/sui-wallet-sdk/move_types"
+
+const (
+	VecMapModuleName = move_types.Identifier("vec_map")
+	VecMapStructName = move_types.Identifier("VecMap")
+)
+
+// VecMap mirrors `0x2::vec_map::VecMap<K, V>`, which is stored as a vector of entries.
+type VecMap[K any, V any] struct {
+	Contents []Entry[K, V]
+}
+
+// Entry mirrors `0x2::vec_map::Entry<K, V>`.
+type Entry[K any, V any] struct {
+	Key   K
+	Value V
+}
+
+func (m VecMap[K, V]) Len() int {
+	return len(m.Contents)
+}
+
+// Get returns the value of the first entry whose key satisfies match.
+func (m VecMap[K, V]) Get(match func(K) bool) (V, bool) {
+	for _, entry := range m.Contents {
+		if match(entry.Key) {
+			return entry.Value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
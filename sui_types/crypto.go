@@ -33,8 +33,8 @@ type Signable interface {
 }
 
 type BcsSignableKind interface {
-	TransactionData | Object
-	//Not Implement | Committee | CheckpointSummary | CheckpointContents | TransactionEffects| TransactionEvents
+	TransactionData | Object | CheckpointSummary | TransactionEffects
+	//Not Implement | Committee | CheckpointContents | TransactionEvents
 	//| SenderSignedData  | Accumulator | Foo
 }
 type BcsSignable[K BcsSignableKind] struct {
@@ -93,6 +93,9 @@ func (s *Signature) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
+	if len(signature) == 0 {
+		return errors.New("empty signature")
+	}
 	switch signature[0] {
 	case 0:
 		if len(signature) != ed25519.PublicKeySize+ed25519.SignatureSize+1 {
@@ -103,6 +106,16 @@ func (s *Signature) UnmarshalJSON(data []byte) error {
 		s.Ed25519SuiSignature = &Ed25519SuiSignature{
 			Signature: signatureBytes,
 		}
+	case 1:
+		if len(signature) != secp256SuiSignatureLength {
+			return errors.New("invalid secp256k1 signature")
+		}
+		s.Secp256k1SuiSignature = &Secp256k1SuiSignature{Signature: signature}
+	case 2:
+		if len(signature) != secp256SuiSignatureLength {
+			return errors.New("invalid secp256r1 signature")
+		}
+		s.Secp256r1SuiSignature = &Secp256r1SuiSignature{Signature: signature}
 	default:
 		return errors.New("unsupport signature")
 	}
@@ -162,6 +175,9 @@ func NewSignatureScheme(flag byte) (SignatureScheme, error) {
 	}
 }
 
+// flag + 64 bytes signature + 33 bytes compressed public key
+const secp256SuiSignatureLength = 1 + 64 + 33
+
 type Secp256k1SuiSignature struct {
 	Signature []byte //secp256k1.pubKey + Secp256k1Signature + 1
 }
@@ -0,0 +1,336 @@
+package sui_types
+
+import (
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/move_types"
+)
+
+type GasCostSummary struct {
+	ComputationCost         uint64
+	StorageCost             uint64
+	StorageRebate           uint64
+	NonRefundableStorageFee uint64
+}
+
+type TransactionEffects struct {
+	V1 *TransactionEffectsV1
+	V2 *TransactionEffectsV2
+}
+
+func (t TransactionEffects) IsBcsEnum() {
+}
+
+func (t TransactionEffects) Digest() TransactionEffectsDigest {
+	return UseDefaultHash(BcsSignable[TransactionEffects]{Data: t})
+}
+
+func (t TransactionEffects) Status() ExecutionStatus {
+	if t.V1 != nil {
+		return t.V1.Status
+	}
+	return t.V2.Status
+}
+
+func (t TransactionEffects) TransactionDigest() TransactionDigest {
+	if t.V1 != nil {
+		return t.V1.TransactionDigest
+	}
+	return t.V2.TransactionDigest
+}
+
+func (t TransactionEffects) GasUsed() GasCostSummary {
+	if t.V1 != nil {
+		return t.V1.GasUsed
+	}
+	return t.V2.GasUsed
+}
+
+func (t TransactionEffects) ExecutedEpoch() EpochId {
+	if t.V1 != nil {
+		return t.V1.ExecutedEpoch
+	}
+	return t.V2.ExecutedEpoch
+}
+
+type OwnedObjectRef struct {
+	Reference ObjectRef
+	Owner     Owner
+}
+
+type TransactionEffectsV1 struct {
+	Status             ExecutionStatus
+	ExecutedEpoch      EpochId
+	GasUsed            GasCostSummary
+	ModifiedAtVersions []struct {
+		ObjectId ObjectID
+		Version  SequenceNumber
+	}
+	SharedObjects        []ObjectRef
+	TransactionDigest    TransactionDigest
+	Created              []OwnedObjectRef
+	Mutated              []OwnedObjectRef
+	Unwrapped            []OwnedObjectRef
+	Deleted              []ObjectRef
+	UnwrappedThenDeleted []ObjectRef
+	Wrapped              []ObjectRef
+	GasObject            OwnedObjectRef
+	EventsDigest         *TransactionEventsDigest `bcs:"optional"`
+	Dependencies         []TransactionDigest
+}
+
+type TransactionEffectsV2 struct {
+	Status            ExecutionStatus
+	ExecutedEpoch     EpochId
+	GasUsed           GasCostSummary
+	TransactionDigest TransactionDigest
+	// GasObjectIndex is the index of the gas object in ChangedObjects, nil for a system transaction.
+	GasObjectIndex         *uint32                  `bcs:"optional"`
+	EventsDigest           *TransactionEventsDigest `bcs:"optional"`
+	Dependencies           []TransactionDigest
+	LamportVersion         SequenceNumber
+	ChangedObjects         []ChangedObject
+	UnchangedSharedObjects []UnchangedSharedObject
+	AuxDataDigest          *Digest `bcs:"optional"`
+}
+
+type ChangedObject struct {
+	ObjectId ObjectID
+	Change   EffectsObjectChange
+}
+
+type EffectsObjectChange struct {
+	InputState  ObjectIn
+	OutputState ObjectOut
+	IdOperation IDOperation
+}
+
+// ObjectIn is the state of a changed object before the transaction.
+type ObjectIn struct {
+	NotExist *lib.EmptyEnum
+	Exist    *struct {
+		Version SequenceNumber
+		Digest  ObjectDigest
+		Owner   Owner
+	}
+}
+
+func (o ObjectIn) IsBcsEnum() {
+}
+
+// ObjectOut is the state of a changed object after the transaction.
+type ObjectOut struct {
+	NotExist    *lib.EmptyEnum
+	ObjectWrite *struct {
+		Digest ObjectDigest
+		Owner  Owner
+	}
+	PackageWrite *struct {
+		Version SequenceNumber
+		Digest  ObjectDigest
+	}
+}
+
+func (o ObjectOut) IsBcsEnum() {
+}
+
+type IDOperation struct {
+	None    *lib.EmptyEnum
+	Created *lib.EmptyEnum
+	Deleted *lib.EmptyEnum
+}
+
+func (i IDOperation) IsBcsEnum() {
+}
+
+type UnchangedSharedObject struct {
+	ObjectId ObjectID
+	Kind     UnchangedSharedKind
+}
+
+type UnchangedSharedKind struct {
+	ReadOnlyRoot *struct {
+		Version SequenceNumber
+		Digest  ObjectDigest
+	}
+	MutateDeleted  *SequenceNumber
+	ReadDeleted    *SequenceNumber
+	Cancelled      *SequenceNumber
+	PerEpochConfig *lib.EmptyEnum
+}
+
+func (u UnchangedSharedKind) IsBcsEnum() {
+}
+
+type ExecutionStatus struct {
+	Success *lib.EmptyEnum
+	Failure *struct {
+		Error ExecutionFailureStatus
+		// Command is the index of the command that failed.
+		Command *uint64 `bcs:"optional"`
+	}
+}
+
+func (e ExecutionStatus) IsBcsEnum() {
+}
+
+func (e ExecutionStatus) IsSuccess() bool {
+	return e.Success != nil
+}
+
+type MoveLocation struct {
+	ModuleAddress move_types.AccountAddress
+	ModuleName    move_types.Identifier
+	Function      uint16
+	Instruction   uint16
+	FunctionName  *string `bcs:"optional"`
+}
+
+type ExecutionFailureStatus struct {
+	InsufficientGas        *lib.EmptyEnum
+	InvalidGasObject       *lib.EmptyEnum
+	InvariantViolation     *lib.EmptyEnum
+	FeatureNotYetSupported *lib.EmptyEnum
+	MoveObjectTooBig       *struct {
+		ObjectSize    uint64
+		MaxObjectSize uint64
+	}
+	MovePackageTooBig *struct {
+		ObjectSize    uint64
+		MaxObjectSize uint64
+	}
+	CircularObjectOwnership *struct {
+		Object ObjectID
+	}
+	InsufficientCoinBalance    *lib.EmptyEnum
+	CoinBalanceOverflow        *lib.EmptyEnum
+	PublishErrorNonZeroAddress *lib.EmptyEnum
+	SuiMoveVerificationError   *lib.EmptyEnum
+	MovePrimitiveRuntimeError  *struct {
+		Location *MoveLocation `bcs:"optional"`
+	}
+	MoveAbort *struct {
+		Location MoveLocation
+		Code     uint64
+	}
+	VMVerificationOrDeserializationError *lib.EmptyEnum
+	VMInvariantViolation                 *lib.EmptyEnum
+	FunctionNotFound                     *lib.EmptyEnum
+	ArityMismatch                        *lib.EmptyEnum
+	TypeArityMismatch                    *lib.EmptyEnum
+	NonEntryFunctionInvoked              *lib.EmptyEnum
+	CommandArgumentError                 *struct {
+		ArgIdx uint16
+		Kind   CommandArgumentError
+	}
+	TypeArgumentError *struct {
+		ArgumentIdx uint16
+		Kind        TypeArgumentError
+	}
+	UnusedValueWithoutDrop *struct {
+		ResultIdx    uint16
+		SecondaryIdx uint16
+	}
+	InvalidPublicFunctionReturnType *struct {
+		Idx uint16
+	}
+	InvalidTransferObject *lib.EmptyEnum
+	EffectsTooLarge       *struct {
+		CurrentSize uint64
+		MaxSize     uint64
+	}
+	PublishUpgradeMissingDependency   *lib.EmptyEnum
+	PublishUpgradeDependencyDowngrade *lib.EmptyEnum
+	PackageUpgradeError               *struct {
+		UpgradeError PackageUpgradeError
+	}
+	WrittenObjectsTooLarge *struct {
+		CurrentSize uint64
+		MaxSize     uint64
+	}
+	CertificateDenied                             *lib.EmptyEnum
+	SuiMoveVerificationTimedout                   *lib.EmptyEnum
+	SharedObjectOperationNotAllowed               *lib.EmptyEnum
+	InputObjectDeleted                            *lib.EmptyEnum
+	ExecutionCancelledDueToSharedObjectCongestion *struct {
+		CongestedObjects []ObjectID
+	}
+	AddressDeniedForCoin *struct {
+		Address  SuiAddress
+		CoinType string
+	}
+	CoinTypeGlobalPause *struct {
+		CoinType string
+	}
+	ExecutionCancelledDueToRandomnessUnavailable *lib.EmptyEnum
+}
+
+func (e ExecutionFailureStatus) IsBcsEnum() {
+}
+
+type CommandArgumentError struct {
+	TypeMismatch                          *lib.EmptyEnum
+	InvalidBCSBytes                       *lib.EmptyEnum
+	InvalidUsageOfPureArg                 *lib.EmptyEnum
+	InvalidArgumentToPrivateEntryFunction *lib.EmptyEnum
+	IndexOutOfBounds                      *struct {
+		Idx uint16
+	}
+	SecondaryIndexOutOfBounds *struct {
+		ResultIdx    uint16
+		SecondaryIdx uint16
+	}
+	InvalidResultArity *struct {
+		ResultIdx uint16
+	}
+	InvalidGasCoinUsage             *lib.EmptyEnum
+	InvalidValueUsage               *lib.EmptyEnum
+	InvalidObjectByValue            *lib.EmptyEnum
+	InvalidObjectByMutRef           *lib.EmptyEnum
+	SharedObjectOperationNotAllowed *lib.EmptyEnum
+}
+
+func (c CommandArgumentError) IsBcsEnum() {
+}
+
+type TypeArgumentError struct {
+	TypeNotFound           *lib.EmptyEnum
+	ConstraintNotSatisfied *lib.EmptyEnum
+}
+
+func (t TypeArgumentError) IsBcsEnum() {
+}
+
+type PackageUpgradeError struct {
+	UnableToFetchPackage *struct {
+		PackageId ObjectID
+	}
+	NotAPackage *struct {
+		ObjectId ObjectID
+	}
+	IncompatibleUpgrade *lib.EmptyEnum
+	DigestDoesNotMatch  *struct {
+		Digest []uint8
+	}
+	UnknownUpgradePolicy *struct {
+		Policy uint8
+	}
+	PackageIDDoesNotMatch *struct {
+		PackageId ObjectID
+		TicketId  ObjectID
+	}
+}
+
+func (p PackageUpgradeError) IsBcsEnum() {
+}
+
+type Event struct {
+	PackageId         ObjectID
+	TransactionModule move_types.Identifier
+	Sender            SuiAddress
+	Type              move_types.StructTag
+	Contents          []uint8
+}
+
+type TransactionEvents struct {
+	Data []Event
+}
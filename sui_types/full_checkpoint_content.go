@@ -0,0 +1,71 @@
+package sui_types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+)
+
+// BlobEncodingBcs is the first byte of a checkpoint blob file holding BCS contents.
+const BlobEncodingBcs = 1
+
+var ErrUnknownBlobEncoding = errors.New("unknown checkpoint blob encoding")
+
+type SenderSignedTransaction struct {
+	IntentMessage IntentMessage[TransactionData]
+	// TxSignatures are the serialized signatures of the transaction.
+	TxSignatures []lib.Base64Data
+}
+
+// SenderSignedData always holds exactly one transaction.
+type SenderSignedData []SenderSignedTransaction
+
+type Transaction struct {
+	Data SenderSignedData
+	// AuthSignature is an empty struct, it takes no bytes.
+	AuthSignature struct{}
+}
+
+// TransactionData returns the data of the transaction, nil if it is malformed.
+func (t Transaction) TransactionData() *TransactionData {
+	if len(t.Data) == 0 {
+		return nil
+	}
+	return &t.Data[0].IntentMessage.Value
+}
+
+type CheckpointTransaction struct {
+	Transaction   Transaction
+	Effects       TransactionEffects
+	Events        *TransactionEvents `bcs:"optional"`
+	InputObjects  []Object
+	OutputObjects []Object
+}
+
+// CheckpointData is the full contents of a checkpoint, as written to the checkpoint blob files of
+// a full node and the remote checkpoint store.
+type CheckpointData struct {
+	CheckpointSummary  CertifiedCheckpointSummary
+	CheckpointContents CheckpointContents
+	Transactions       []CheckpointTransaction
+}
+
+func (c CheckpointData) SequenceNumber() CheckpointSequenceNumber {
+	return c.CheckpointSummary.Data.SequenceNumber
+}
+
+// DecodeCheckpointData decodes a checkpoint blob, its encoding byte followed by its contents.
+func DecodeCheckpointData(blob []byte) (*CheckpointData, error) {
+	if len(blob) == 0 {
+		return nil, errors.New("empty checkpoint blob")
+	}
+	if blob[0] != BlobEncodingBcs {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownBlobEncoding, blob[0])
+	}
+	var data CheckpointData
+	if err := lib.UnmarshalBCS(blob[1:], &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
@@ -0,0 +1,184 @@
+package sui_types
+
+import (
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/move_types"
+)
+
+func testDigest(b byte) Digest {
+	digest := make(Digest, 32)
+	digest[0] = b
+	return digest
+}
+
+func TestDecodeCheckpointData(t *testing.T) {
+	sender, err := NewAddressFromHex("0x7e875ea78ee09f08d72e2676cf84e0f1c8ac61d94fa339cc8e37cace85bebc6e")
+	require.NoError(t, err)
+	coinId, err := NewObjectIdFromHex("0xabc")
+	require.NoError(t, err)
+	input := uint16(0)
+	command := uint64(1)
+	functionName := "withdraw"
+	txData := TransactionData{
+		V1: &TransactionDataV1{
+			Kind: TransactionKind{
+				ProgrammableTransaction: &ProgrammableTransaction{
+					Inputs: []CallArg{
+						{Object: &ObjectArg{Receiving: &ObjectRef{ObjectId: *coinId, Version: 3, Digest: testDigest(3)}}},
+					},
+					Commands: []Command{{MergeCoins: &struct {
+						Argument  Argument
+						Arguments []Argument
+					}{Argument: Argument{GasCoin: &lib.EmptyEnum{}}, Arguments: []Argument{{Input: &input}}}}},
+				},
+			},
+			Sender:     *sender,
+			GasData:    GasData{Payment: []*ObjectRef{}, Owner: *sender, Price: 750, Budget: 10000000},
+			Expiration: TransactionExpiration{None: &lib.EmptyEnum{}},
+		},
+	}
+	effects := TransactionEffects{
+		V2: &TransactionEffectsV2{
+			Status: ExecutionStatus{
+				Failure: &struct {
+					Error   ExecutionFailureStatus
+					Command *uint64 `bcs:"optional"`
+				}{
+					Error: ExecutionFailureStatus{
+						MoveAbort: &struct {
+							Location MoveLocation
+							Code     uint64
+						}{
+							Location: MoveLocation{
+								ModuleAddress: *SuiSystemAddress,
+								ModuleName:    "vault",
+								Function:      2,
+								Instruction:   14,
+								FunctionName:  &functionName,
+							},
+							Code: 7,
+						},
+					},
+					Command: &command,
+				},
+			},
+			ExecutedEpoch:     330,
+			GasUsed:           GasCostSummary{ComputationCost: 750000, StorageCost: 988000},
+			TransactionDigest: txData.Digest(),
+			Dependencies:      []TransactionDigest{testDigest(4)},
+			LamportVersion:    4,
+			ChangedObjects: []ChangedObject{
+				{
+					ObjectId: *coinId,
+					Change: EffectsObjectChange{
+						InputState: ObjectIn{
+							Exist: &struct {
+								Version SequenceNumber
+								Digest  ObjectDigest
+								Owner   Owner
+							}{Version: 3, Digest: testDigest(3), Owner: Owner{AddressOwner: sender}},
+						},
+						OutputState: ObjectOut{
+							ObjectWrite: &struct {
+								Digest ObjectDigest
+								Owner  Owner
+							}{Digest: testDigest(5), Owner: Owner{AddressOwner: sender}},
+						},
+						IdOperation: IDOperation{None: &lib.EmptyEnum{}},
+					},
+				},
+			},
+			UnchangedSharedObjects: []UnchangedSharedObject{
+				{ObjectId: *SuiSystemAddress, Kind: UnchangedSharedKind{Cancelled: &command}},
+			},
+		},
+	}
+	checkpoint := CheckpointData{
+		CheckpointSummary: CertifiedCheckpointSummary{
+			Data: CheckpointSummary{
+				Epoch:               330,
+				SequenceNumber:      28810000,
+				ContentDigest:       testDigest(1),
+				PreviousDigest:      &[]Digest{testDigest(2)}[0],
+				TimestampMs:         1709281800123,
+				VersionSpecificData: []byte{},
+				CheckpointCommitments: []CheckpointCommitment{
+					{ECMHLiveObjectSetDigest: &[]Digest{testDigest(6)}[0]},
+				},
+			},
+			AuthSignature: AuthorityQuorumSignInfo{Epoch: 330, Signature: make([]byte, 48), SignersMap: []byte{1, 2}},
+		},
+		CheckpointContents: CheckpointContents{
+			V1: &struct {
+				Transactions   []ExecutionDigests
+				UserSignatures [][]lib.Base64Data
+			}{
+				Transactions:   []ExecutionDigests{{Transaction: txData.Digest(), Effects: effects.Digest()}},
+				UserSignatures: [][]lib.Base64Data{{make([]byte, 97)}},
+			},
+		},
+		Transactions: []CheckpointTransaction{
+			{
+				Transaction: Transaction{
+					Data: SenderSignedData{
+						{
+							IntentMessage: NewIntentMessage(DefaultIntent(), txData),
+							TxSignatures:  []lib.Base64Data{make([]byte, 97)},
+						},
+					},
+				},
+				Effects: effects,
+				Events: &TransactionEvents{
+					Data: []Event{
+						{
+							PackageId:         *SuiSystemAddress,
+							TransactionModule: "vault",
+							Sender:            *sender,
+							Type:              move_types.StructTag{Address: *SuiSystemAddress, Module: "vault", Name: "Withdrawn", TypeParams: []move_types.TypeTag{}},
+							Contents:          []byte{1, 2, 3},
+						},
+					},
+				},
+				InputObjects: []Object{},
+				OutputObjects: []Object{
+					{
+						Data: Data{
+							Move: &MoveObject{
+								Type:     MoveObjectType{GasCoin: &lib.EmptyEnum{}},
+								Version:  4,
+								Contents: append(coinId[:], 1, 0, 0, 0, 0, 0, 0, 0),
+							},
+						},
+						Owner:               Owner{AddressOwner: sender},
+						PreviousTransaction: txData.Digest(),
+						StorageRebate:       988000,
+					},
+				},
+			},
+		},
+	}
+	data, err := bcs.Marshal(checkpoint)
+	require.NoError(t, err)
+
+	decoded, err := DecodeCheckpointData(append([]byte{BlobEncodingBcs}, data...))
+	require.NoError(t, err)
+	require.Equal(t, checkpoint, *decoded)
+	require.Equal(t, uint64(28810000), decoded.SequenceNumber())
+	tx := decoded.Transactions[0]
+	require.Equal(t, txData.Digest(), tx.Transaction.TransactionData().Digest())
+	require.Equal(t, tx.Effects.TransactionDigest(), tx.Transaction.TransactionData().Digest())
+	require.Equal(t, decoded.CheckpointContents.V1.Transactions[0].Effects, tx.Effects.Digest())
+	require.False(t, tx.Effects.Status().IsSuccess())
+	require.Equal(t, uint64(7), tx.Effects.Status().Failure.Error.MoveAbort.Code)
+	require.Equal(t, uint64(750000), tx.Effects.GasUsed().ComputationCost)
+	require.Len(t, decoded.CheckpointSummary.Data.Digest(), 32)
+
+	_, err = DecodeCheckpointData(append([]byte{2}, data...))
+	require.ErrorIs(t, err, ErrUnknownBlobEncoding)
+	_, err = DecodeCheckpointData(append([]byte{BlobEncodingBcs}, data[:len(data)-1]...))
+	require.Error(t, err)
+}
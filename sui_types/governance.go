@@ -9,4 +9,17 @@ const (
 	AddStakeMulCoinFunName = move_types.Identifier("request_add_stake_mul_coin")
 	AddStakeFunName        = move_types.Identifier("request_add_stake")
 	WithdrawStakeFunName   = move_types.Identifier("request_withdraw_stake")
+
+	WithdrawStakeNonEntryFunName = move_types.Identifier("request_withdraw_stake_non_entry")
+	SplitStakedSuiFunName        = move_types.Identifier("split_staked_sui")
+	SplitFunName                 = move_types.Identifier("split")
+	JoinStakedSuiFunName         = move_types.Identifier("join_staked_sui")
 )
+
+// StakedSui mirrors `0x3::staking_pool::StakedSui`.
+type StakedSui struct {
+	Id                   UID
+	PoolId               ID
+	StakeActivationEpoch EpochId
+	Principal            Balance
+}
@@ -0,0 +1,23 @@
+package sui_types
+
+import "github.com/thorli9527/sui-wallet-sdk/move_types"
+
+const (
+	ObjectModuleName = move_types.Identifier("object")
+	UIDStructName    = move_types.Identifier("UID")
+	IDStructName     = move_types.Identifier("ID")
+)
+
+// UID mirrors `0x2::object::UID`, the unique id field of every Sui object.
+type UID struct {
+	Id ID
+}
+
+func (u UID) ObjectID() ObjectID {
+	return u.Id.Bytes
+}
+
+// ID mirrors `0x2::object::ID`.
+type ID struct {
+	Bytes ObjectID
+}
@@ -14,6 +14,19 @@ func (t TransactionData) IsBcsEnum() {
 
 }
 
+// Digest returns the digest of the transaction, the node computes the same one.
+func (t TransactionData) Digest() TransactionDigest {
+	return UseDefaultHash(BcsSignable[TransactionData]{Data: t})
+}
+
+// NewTransactionDigestFromBytes returns the digest of a BCS serialized TransactionData.
+func NewTransactionDigestFromBytes(txBytes []byte) TransactionDigest {
+	hash := NewDefaultHash()
+	hash.Write([]byte("TransactionData::"))
+	hash.Write(txBytes)
+	return hash.Sum(nil)
+}
+
 type TransactionDataV1 struct {
 	Kind       TransactionKind
 	Sender     SuiAddress
@@ -37,10 +50,15 @@ type GasData struct {
 }
 
 type TransactionKind struct {
-	ProgrammableTransaction *ProgrammableTransaction
-	ChangeEpoch             *ChangeEpoch
-	Genesis                 *GenesisTransaction
-	ConsensusCommitPrologue *ConsensusCommitPrologue
+	ProgrammableTransaction   *ProgrammableTransaction
+	ChangeEpoch               *ChangeEpoch
+	Genesis                   *GenesisTransaction
+	ConsensusCommitPrologue   *ConsensusCommitPrologue
+	AuthenticatorStateUpdate  *AuthenticatorStateUpdate
+	EndOfEpochTransaction     *[]EndOfEpochTransactionKind
+	RandomnessStateUpdate     *RandomnessStateUpdate
+	ConsensusCommitPrologueV2 *ConsensusCommitPrologueV2
+	ConsensusCommitPrologueV3 *ConsensusCommitPrologueV3
 }
 
 func (t TransactionKind) IsBcsEnum() {
@@ -52,6 +70,79 @@ type ConsensusCommitPrologue struct {
 	CommitTimestampMs CheckpointTimestamp
 }
 
+type ConsensusCommitPrologueV2 struct {
+	Epoch                 uint64
+	Round                 uint64
+	CommitTimestampMs     CheckpointTimestamp
+	ConsensusCommitDigest Digest
+}
+
+type ConsensusCommitPrologueV3 struct {
+	Epoch                                 uint64
+	Round                                 uint64
+	SubDagIndex                           *uint64 `bcs:"optional"`
+	CommitTimestampMs                     CheckpointTimestamp
+	ConsensusCommitDigest                 Digest
+	ConsensusDeterminedVersionAssignments ConsensusDeterminedVersionAssignments
+}
+
+type ConsensusDeterminedVersionAssignments struct {
+	CancelledTransactions *[]struct {
+		Digest   TransactionDigest
+		Versions []struct {
+			ObjectId ObjectID
+			Version  SequenceNumber
+		}
+	}
+}
+
+func (c ConsensusDeterminedVersionAssignments) IsBcsEnum() {
+}
+
+type AuthenticatorStateUpdate struct {
+	Epoch                                uint64
+	Round                                uint64
+	NewActiveJwks                        []ActiveJwk
+	AuthenticatorObjInitialSharedVersion SequenceNumber
+}
+
+type ActiveJwk struct {
+	JwkId struct {
+		Iss string
+		Kid string
+	}
+	Jwk struct {
+		Kty string
+		E   string
+		N   string
+		Alg string
+	}
+	Epoch uint64
+}
+
+type RandomnessStateUpdate struct {
+	Epoch                             uint64
+	RandomnessRound                   uint64
+	RandomBytes                       []uint8
+	RandomnessObjInitialSharedVersion SequenceNumber
+}
+
+type EndOfEpochTransactionKind struct {
+	ChangeEpoch              *ChangeEpoch
+	AuthenticatorStateCreate *lib.EmptyEnum
+	AuthenticatorStateExpire *struct {
+		MinEpoch                             uint64
+		AuthenticatorObjInitialSharedVersion SequenceNumber
+	}
+	RandomnessStateCreate *lib.EmptyEnum
+	DenyListStateCreate   *lib.EmptyEnum
+	BridgeStateCreate     *CheckpointDigest
+	BridgeCommitteeInit   *SequenceNumber
+}
+
+func (e EndOfEpochTransactionKind) IsBcsEnum() {
+}
+
 type ProgrammableTransaction struct {
 	Inputs   []CallArg
 	Commands []Command
@@ -189,6 +280,9 @@ type GenesisObject struct {
 	}
 }
 
+func (g GenesisObject) IsBcsEnum() {
+}
+
 type CallArg struct {
 	Pure   *[]byte
 	Object *ObjectArg
@@ -204,6 +298,7 @@ type ObjectArg struct {
 		InitialSharedVersion SequenceNumber
 		Mutable              bool
 	}
+	Receiving *ObjectRef
 }
 
 func (o ObjectArg) IsBcsEnum() {
@@ -215,6 +310,8 @@ func (o ObjectArg) id() ObjectID {
 		return o.ImmOrOwnedObject.ObjectId
 	case o.SharedObject != nil:
 		return o.SharedObject.Id
+	case o.Receiving != nil:
+		return o.Receiving.ObjectId
 	default:
 		return ObjectID{}
 	}
@@ -1,6 +1,71 @@
 package sui_types
 
+import "github.com/thorli9527/sui-wallet-sdk/lib"
+
 type (
 	CheckpointSequenceNumber = uint64
 	CheckpointTimestamp      = uint64
 )
+
+type CheckpointSummary struct {
+	Epoch                      EpochId
+	SequenceNumber             CheckpointSequenceNumber
+	NetworkTotalTransactions   uint64
+	ContentDigest              CheckpointContentsDigest
+	PreviousDigest             *CheckpointDigest `bcs:"optional"`
+	EpochRollingGasCostSummary GasCostSummary
+	TimestampMs                CheckpointTimestamp
+	CheckpointCommitments      []CheckpointCommitment
+	EndOfEpochData             *EndOfEpochData `bcs:"optional"`
+	VersionSpecificData        []uint8
+}
+
+func (c CheckpointSummary) Digest() CheckpointDigest {
+	return UseDefaultHash(BcsSignable[CheckpointSummary]{Data: c})
+}
+
+type CheckpointCommitment struct {
+	ECMHLiveObjectSetDigest *Digest
+}
+
+func (c CheckpointCommitment) IsBcsEnum() {
+}
+
+type EndOfEpochData struct {
+	NextEpochCommittee []struct {
+		// AuthorityName is the BLS public key of the authority.
+		AuthorityName []uint8
+		Stake         uint64
+	}
+	NextEpochProtocolVersion uint64
+	EpochCommitments         []CheckpointCommitment
+}
+
+// AuthorityQuorumSignInfo is the aggregated BLS signature of a quorum of the committee of Epoch,
+// SignersMap is the serialized roaring bitmap of the signing authorities.
+type AuthorityQuorumSignInfo struct {
+	Epoch      EpochId
+	Signature  []uint8
+	SignersMap []uint8
+}
+
+type CertifiedCheckpointSummary struct {
+	Data          CheckpointSummary
+	AuthSignature AuthorityQuorumSignInfo
+}
+
+type ExecutionDigests struct {
+	Transaction TransactionDigest
+	Effects     TransactionEffectsDigest
+}
+
+type CheckpointContents struct {
+	V1 *struct {
+		Transactions []ExecutionDigests
+		// UserSignatures are the serialized signatures of each transaction.
+		UserSignatures [][]lib.Base64Data
+	}
+}
+
+func (c CheckpointContents) IsBcsEnum() {
+}
@@ -31,4 +31,5 @@ func TestTransferSui(t *testing.T) {
 	txByte, err := bcs.Marshal(tx)
 	require.NoError(t, err)
 	t.Logf("%x", txByte)
+	require.Equal(t, tx.Digest(), NewTransactionDigestFromBytes(txByte))
 }
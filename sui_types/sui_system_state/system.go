@@ -4,4 +4,27 @@ import "github.com/thorli9527/sui-wallet-sdk/move_types"
 
 const (
 	SuiSystemModuleName = move_types.Identifier("sui_system")
+
+	RequestAddValidatorCandidateFunName    = move_types.Identifier("request_add_validator_candidate")
+	RequestRemoveValidatorCandidateFunName = move_types.Identifier("request_remove_validator_candidate")
+	RequestAddValidatorFunName             = move_types.Identifier("request_add_validator")
+	RequestRemoveValidatorFunName          = move_types.Identifier("request_remove_validator")
+
+	RequestSetGasPriceFunName                  = move_types.Identifier("request_set_gas_price")
+	SetCandidateValidatorGasPriceFunName       = move_types.Identifier("set_candidate_validator_gas_price")
+	RequestSetCommissionRateFunName            = move_types.Identifier("request_set_commission_rate")
+	SetCandidateValidatorCommissionRateFunName = move_types.Identifier("set_candidate_validator_commission_rate")
+
+	UpdateValidatorNameFunName        = move_types.Identifier("update_validator_name")
+	UpdateValidatorDescriptionFunName = move_types.Identifier("update_validator_description")
+	UpdateValidatorImageUrlFunName    = move_types.Identifier("update_validator_image_url")
+	UpdateValidatorProjectUrlFunName  = move_types.Identifier("update_validator_project_url")
+
+	UpdateValidatorNextEpochNetworkAddressFunName = move_types.Identifier("update_validator_next_epoch_network_address")
+	UpdateCandidateValidatorNetworkAddressFunName = move_types.Identifier("update_candidate_validator_network_address")
+	UpdateValidatorNextEpochP2pAddressFunName     = move_types.Identifier("update_validator_next_epoch_p2p_address")
+	UpdateCandidateValidatorP2pAddressFunName     = move_types.Identifier("update_candidate_validator_p2p_address")
+
+	ReportValidatorFunName     = move_types.Identifier("report_validator")
+	UndoReportValidatorFunName = move_types.Identifier("undo_report_validator")
 )
@@ -1,6 +1,7 @@
 package sui_types
 
 var (
+	SuiFrameworkAddress, _            = NewAddressFromHex("0x2")
 	SuiSystemAddress, _               = NewAddressFromHex("0x3")
 	SuiSystemPackageId                = SuiSystemAddress
 	SuiSystemStateObjectId, _         = NewObjectIdFromHex("0x5")
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrInvalidAmount = errors.New("invalid coin amount")
+
+// Rounding is how FormatAmountRounded drops the fraction digits beyond the precision.
+type Rounding int
+
+const (
+	RoundHalfUp   Rounding = iota // to the nearest, half away from zero
+	RoundHalfEven                 // to the nearest, half to the even digit
+	RoundDown                     // toward zero
+	RoundUp                       // away from zero
+)
+
+var amountLiteral = regexp.MustCompile(`^\+?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+
+// ParseAmount parses a human amount like "1.5" into base units of a coin with decimals, 1500000000
+// for SUI. The amount is decimal, not negative, without exponent nor separators, and has at most
+// decimals fraction digits, so it is never rounded.
+func ParseAmount(amount string, decimals uint8) (*big.Int, error) {
+	amount = strings.TrimSpace(amount)
+	if !amountLiteral.MatchString(amount) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	units := value.Shift(int32(decimals))
+	if !units.IsInteger() {
+		return nil, fmt.Errorf("%w: %q has more than %d decimals", ErrInvalidAmount, amount, decimals)
+	}
+	return units.BigInt(), nil
+}
+
+// FormatAmount formats base units of a coin with decimals as a human amount, exactly and without
+// trailing zeros: 1500000000 is "1.5" for SUI.
+func FormatAmount(amount *big.Int, decimals uint8) string {
+	return decimal.NewFromBigInt(amount, -int32(decimals)).String()
+}
+
+// FormatAmountRounded formats base units of a coin with decimals as a human amount rounded to
+// precision fraction digits, all of them written: 1500000000 is "1.50" for SUI with precision 2.
+func FormatAmountRounded(amount *big.Int, decimals uint8, precision int32, rounding Rounding) string {
+	value := decimal.NewFromBigInt(amount, -int32(decimals))
+	switch rounding {
+	case RoundHalfEven:
+		value = value.RoundBank(precision)
+	case RoundDown:
+		value = value.RoundDown(precision)
+	case RoundUp:
+		value = value.RoundUp(precision)
+	default:
+		value = value.Round(precision)
+	}
+	return value.StringFixed(precision)
+}
+
+// ParseAmount parses a human amount of the coin into base units, see ParseAmount.
+func (m *SuiCoinMetadata) ParseAmount(amount string) (*big.Int, error) {
+	return ParseAmount(amount, m.Decimals)
+}
+
+// FormatAmount formats base units of the coin with its symbol, like "1.5 SUI".
+func (m *SuiCoinMetadata) FormatAmount(amount *big.Int) string {
+	return withSymbol(FormatAmount(amount, m.Decimals), m.Symbol)
+}
+
+// FormatAmountRounded formats base units of the coin rounded to precision fraction digits with its
+// symbol, like "1.50 SUI".
+func (m *SuiCoinMetadata) FormatAmountRounded(amount *big.Int, precision int32, rounding Rounding) string {
+	return withSymbol(FormatAmountRounded(amount, m.Decimals, precision, rounding), m.Symbol)
+}
+
+func withSymbol(amount, symbol string) string {
+	if symbol == "" {
+		return amount
+	}
+	return amount + " " + symbol
+}
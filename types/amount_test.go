@@ -0,0 +1,65 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals uint8
+		want     string
+		wantErr  bool
+	}{
+		{amount: "1.5", decimals: 9, want: "1500000000"},
+		{amount: "0.000000001", decimals: 9, want: "1"},
+		{amount: " 18446744073.709551615 ", decimals: 9, want: "18446744073709551615"},
+		{amount: "123456789012345678901234567890", decimals: 6, want: "123456789012345678901234567890000000"},
+		{amount: ".25", decimals: 2, want: "25"},
+		{amount: "7.", decimals: 0, want: "7"},
+		{amount: "+0.1", decimals: 1, want: "1"},
+		{amount: "-0.1", decimals: 1, wantErr: true},
+		{amount: "-0", decimals: 9, wantErr: true},
+		{amount: "0.1234567", decimals: 6, wantErr: true},
+		{amount: "1e3", decimals: 9, wantErr: true},
+		{amount: "1,000", decimals: 9, wantErr: true},
+		{amount: ".", decimals: 9, wantErr: true},
+		{amount: "", decimals: 9, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.amount, tt.decimals)
+		if tt.wantErr {
+			require.ErrorIs(t, err, ErrInvalidAmount, tt.amount)
+			continue
+		}
+		require.NoError(t, err, tt.amount)
+		require.Equal(t, tt.want, got.String(), tt.amount)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	sui := SuiCoinMetadata{Decimals: 9, Symbol: "SUI"}
+	require.Equal(t, "1.5 SUI", sui.FormatAmount(big.NewInt(1_500_000_000)))
+	require.Equal(t, "0.000000001 SUI", sui.FormatAmount(big.NewInt(1)))
+	require.Equal(t, "0 SUI", sui.FormatAmount(new(big.Int)))
+	require.Equal(t, "-2", FormatAmount(big.NewInt(-2000), 3))
+
+	amount := big.NewInt(1_235_000_000)
+	require.Equal(t, "1.24 SUI", sui.FormatAmountRounded(amount, 2, RoundHalfUp))
+	require.Equal(t, "1.24", FormatAmountRounded(amount, 9, 2, RoundHalfEven))
+	require.Equal(t, "1.22", FormatAmountRounded(big.NewInt(1_225_000_000), 9, 2, RoundHalfEven))
+	require.Equal(t, "1.23", FormatAmountRounded(amount, 9, 2, RoundDown))
+	require.Equal(t, "1.24", FormatAmountRounded(big.NewInt(1_230_000_001), 9, 2, RoundUp))
+	require.Equal(t, "1.50", FormatAmountRounded(big.NewInt(1_500_000_000), 9, 2, RoundDown))
+	require.Equal(t, "2", FormatAmountRounded(big.NewInt(1_500_000_000), 9, 0, RoundHalfUp))
+
+	// parsing a formatted amount gives it back
+	for _, units := range []int64{0, 1, 999_999_999, 1_000_000_000, 123_456_789_012} {
+		parsed, err := sui.ParseAmount(FormatAmount(big.NewInt(units), sui.Decimals))
+		require.NoError(t, err)
+		require.Equal(t, units, parsed.Int64())
+	}
+}
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+// CheckpointId selects a checkpoint either by sequence number or by digest.
+type CheckpointId string
+
+func CheckpointIdFromSequence(seq CheckpointSequenceNumber) CheckpointId {
+	return CheckpointId(strconv.FormatUint(seq, 10))
+}
+
+func CheckpointIdFromDigest(digest sui_types.CheckpointDigest) CheckpointId {
+	return CheckpointId(digest.String())
+}
+
+type Checkpoint struct {
+	Epoch          SafeSuiBigInt[EpochId]                  `json:"epoch"`
+	SequenceNumber SafeSuiBigInt[CheckpointSequenceNumber] `json:"sequenceNumber"`
+	Digest         sui_types.CheckpointDigest              `json:"digest"`
+	// Total number of transactions committed since genesis, including those in this checkpoint.
+	NetworkTotalTransactions SafeSuiBigInt[uint64]       `json:"networkTotalTransactions"`
+	PreviousDigest           *sui_types.CheckpointDigest `json:"previousDigest,omitempty"`
+	// The running total gas costs of all transactions included in the current epoch so far
+	// until this checkpoint.
+	EpochRollingGasCostSummary GasCostSummary `json:"epochRollingGasCostSummary"`
+	// Timestamp of the checkpoint in milliseconds since the unix epoch.
+	TimestampMs SafeSuiBigInt[sui_types.CheckpointTimestamp] `json:"timestampMs"`
+	// Present only on the last checkpoint of the epoch.
+	EndOfEpochData        *EndOfEpochData               `json:"endOfEpochData,omitempty"`
+	Transactions          []sui_types.TransactionDigest `json:"transactions"`
+	CheckpointCommitments []CheckpointCommitment        `json:"checkpointCommitments"`
+	ValidatorSignature    lib.Base64Data                `json:"validatorSignature"`
+}
+
+func (c *Checkpoint) Timestamp() time.Time {
+	return time.UnixMilli(c.TimestampMs.Int64())
+}
+
+func (c *Checkpoint) IsEndOfEpoch() bool {
+	return c.EndOfEpochData != nil
+}
+
+type EndOfEpochData struct {
+	NextEpochCommittee       []CommitteeMember      `json:"nextEpochCommittee"`
+	NextEpochProtocolVersion SafeSuiBigInt[uint64]  `json:"nextEpochProtocolVersion"`
+	EpochCommitments         []CheckpointCommitment `json:"epochCommitments"`
+}
+
+type CheckpointCommitment struct {
+	ECMHLiveObjectSetDigest *struct {
+		Digest sui_types.Digest `json:"digest"`
+	} `json:"ECMHLiveObjectSetDigest,omitempty"`
+}
+
+// CommitteeMember is the [authorityName, stake] pair of a committee.
+type CommitteeMember struct {
+	AuthorityName lib.Base64Data
+	Stake         SafeSuiBigInt[uint64]
+}
+
+func (m CommitteeMember) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{m.AuthorityName, m.Stake})
+}
+
+func (m *CommitteeMember) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("committee member [%s] is not a pair", string(data))
+	}
+	if err := json.Unmarshal(pair[0], &m.AuthorityName); err != nil {
+		return err
+	}
+	return json.Unmarshal(pair[1], &m.Stake)
+}
+
+type CheckpointPage = Page[Checkpoint, SafeSuiBigInt[CheckpointSequenceNumber]]
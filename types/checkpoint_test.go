@@ -0,0 +1,54 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint_UnmarshalJSON(t *testing.T) {
+	data := `{
+		"epoch": "5",
+		"sequenceNumber": "1024",
+		"digest": "HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn",
+		"networkTotalTransactions": "4096",
+		"previousDigest": "HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn",
+		"epochRollingGasCostSummary": {
+			"computationCost": "1000",
+			"storageCost": "2000",
+			"storageRebate": "1500",
+			"nonRefundableStorageFee": "15"
+		},
+		"timestampMs": "1681393657483",
+		"transactions": ["HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn"],
+		"checkpointCommitments": [],
+		"validatorSignature": "AQID",
+		"endOfEpochData": {
+			"nextEpochCommittee": [["AQID", "2500"], ["BAUG", "7500"]],
+			"nextEpochProtocolVersion": "7",
+			"epochCommitments": [{"ECMHLiveObjectSetDigest": {"digest": "HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn"}}]
+		}
+	}`
+	var checkpoint Checkpoint
+	require.NoError(t, json.Unmarshal([]byte(data), &checkpoint))
+	require.Equal(t, uint64(1024), checkpoint.SequenceNumber.Uint64())
+	require.Equal(t, int64(1681393657483), checkpoint.Timestamp().UnixMilli())
+	require.Equal(t, uint64(1000), checkpoint.EpochRollingGasCostSummary.ComputationCost.Uint64())
+	require.Len(t, checkpoint.Transactions, 1)
+	require.Equal(t, []byte{1, 2, 3}, checkpoint.ValidatorSignature.Data())
+
+	require.True(t, checkpoint.IsEndOfEpoch())
+	committee := checkpoint.EndOfEpochData.NextEpochCommittee
+	require.Len(t, committee, 2)
+	require.Equal(t, []byte{4, 5, 6}, committee[1].AuthorityName.Data())
+	require.Equal(t, uint64(7500), committee[1].Stake.Uint64())
+	require.NotNil(t, checkpoint.EndOfEpochData.EpochCommitments[0].ECMHLiveObjectSetDigest)
+
+	encoded, err := json.Marshal(committee[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `["AQID", "2500"]`, string(encoded))
+
+	require.Equal(t, CheckpointId("1024"), CheckpointIdFromSequence(1024))
+	require.Equal(t, CheckpointId(checkpoint.Digest.String()), CheckpointIdFromDigest(checkpoint.Digest))
+}
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"github.com/thorli9527/sui-wallet-sdk/sui_types"
 	"math/big"
-	"sort"
 )
 
 const SUI_COIN_TYPE = "0x2::sui::SUI"
@@ -95,7 +94,7 @@ func (cs *PickedCoins) SuggestMaxGasBudget() uint64 {
 // @param targetAmount total amount of coins to be selected from inputCoins
 // @param gasBudget the transaction gas budget
 // @param limit the max number of coins selected, default is `MAX_INPUT_COUNT_MERGE`
-// @param moreCount get more count of coins as possible, maybe the caller will want to try to merge out some small coin objects, default is 10
+// @param moreCount get more count of coins as possible in the order of inputCoins, maybe the caller will want to try to merge out some small coin objects, 0 selects with DefaultCoinSelector instead, see PickupCoinsWith
 // @throw ErrNoCoinsFound If the count of input coins is 0.
 // @throw ErrInsufficientBalance If the input coins are all that is left and the total amount is less than the target amount.
 // @throw ErrNeedMergeCoin If there are many coins, but the total amount of coins limited is less than the target amount.
@@ -103,6 +102,9 @@ func PickupCoins(inputCoins *CoinPage, targetAmount big.Int, gasBudget uint64, l
 	*PickedCoins,
 	error,
 ) {
+	if moreCount <= 0 {
+		return PickupCoinsWith(DefaultCoinSelector, inputCoins, targetAmount, gasBudget, limit)
+	}
 	inputCount := len(inputCoins.Data)
 	if inputCount <= 0 {
 		return nil, ErrNoCoinsFound
@@ -110,9 +112,6 @@ func PickupCoins(inputCoins *CoinPage, targetAmount big.Int, gasBudget uint64, l
 	if limit <= 0 {
 		limit = MAX_INPUT_COUNT_MERGE
 	}
-	if moreCount == 0 {
-		moreCount = 10
-	}
 	if moreCount > limit {
 		moreCount = limit
 	}
@@ -221,34 +220,36 @@ func (cs Coins) PickSUICoinsWithGas(amount *big.Int, gasAmount uint64, pickMetho
 
 // PickCoins pick coins, which sum >= amount,
 // pickMethod, see PickSmaller|PickBigger|PickByOrder
+// if amount is 0, the first coin of the pickMethod order is picked
 // if not satisfated amount, an ErrCoinsNeedMoreObject error will return
 func (cs Coins) PickCoins(amount *big.Int, pickMethod int) (Coins, error) {
-	var sortedCoins Coins
-	if pickMethod == PickByOrder {
-		sortedCoins = cs
-	} else {
-		sortedCoins = make(Coins, len(cs))
-		copy(sortedCoins, cs)
-		sort.Slice(
-			sortedCoins, func(i, j int) bool {
-				if pickMethod == PickSmaller {
-					return sortedCoins[i].Balance.Uint64() < sortedCoins[j].Balance.Uint64()
-				} else {
-					return sortedCoins[i].Balance.Uint64() >= sortedCoins[j].Balance.Uint64()
-				}
-			},
-		)
-	}
-
-	result := make(Coins, 0)
-	total := big.NewInt(0)
-	for _, coin := range sortedCoins {
-		result = append(result, coin)
-		total = new(big.Int).Add(total, big.NewInt(0).SetUint64(coin.Balance.Uint64()))
-		if total.Cmp(amount) >= 0 {
-			return result, nil
+	if amount.Sign() <= 0 {
+		// one coin is picked for no amount, the first one of the order
+		if len(cs) == 0 {
+			return nil, ErrCoinsNeedMoreObject
 		}
+		switch pickMethod {
+		case PickSmaller:
+			return sortedCoins(cs, false)[:1], nil
+		case PickByOrder:
+			return Coins{cs[0]}, nil
+		default:
+			return sortedCoins(cs, true)[:1], nil
+		}
+	}
+	var selector CoinSelector
+	switch pickMethod {
+	case PickSmaller:
+		selector = SmallestFirst{}
+	case PickByOrder:
+		selector = InOrder{}
+	default:
+		selector = LargestFirst{}
 	}
+	return cs.SelectCoins(selector, amount)
+}
 
-	return nil, ErrCoinsNeedMoreObject
+// SelectCoins selects coins with a total balance of at least amount with selector.
+func (cs Coins) SelectCoins(selector CoinSelector, amount *big.Int) (Coins, error) {
+	return selector.SelectCoins(cs, amount, 0)
 }
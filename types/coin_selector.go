@@ -0,0 +1,262 @@
+package types
+
+import (
+	"math/big"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// CoinSelector selects coins whose total balance covers an amount.
+//
+// SelectCoins returns coins taken from coins, at most maxCoins of them when it is positive, with a
+// total balance of at least amount. It fails with ErrCoinsNeedMoreObject when all the coins do not
+// cover the amount, and with ErrNeedMergeCoin when only more than maxCoins of them do.
+type CoinSelector interface {
+	SelectCoins(coins Coins, amount *big.Int, maxCoins int) (Coins, error)
+}
+
+// LargestFirst selects the largest coins first, it minimizes the number of inputs.
+type LargestFirst struct{}
+
+// SmallestFirst selects the smallest coins first, it consumes the dust. When the smallest coins
+// need more than maxCoins, as many of them as possible are completed with the largest ones.
+type SmallestFirst struct{}
+
+// InOrder selects the coins in their order, like the order of the node.
+type InOrder struct{}
+
+// BestFit selects the coins whose total is the closest to the amount, to avoid a change coin, and
+// the fewest coins among those. It searches at most MaxTries selections, 100000 by default, and
+// falls back to the best one found, at worst the selection of LargestFirst.
+type BestFit struct {
+	MaxTries int
+}
+
+// RandomSelection selects coins in a random order, so the selections of an address are not linked
+// by a pattern. When a random order needs more than maxCoins, the selection of LargestFirst is used.
+type RandomSelection struct {
+	// Rand is the source of the order, a time seeded one when it is nil. A *rand.Rand must not be
+	// shared by goroutines.
+	Rand *rand.Rand
+}
+
+func coinBalance(coin Coin) *big.Int {
+	return new(big.Int).SetUint64(coin.Balance.Uint64())
+}
+
+// sortedCoins returns a copy of coins sorted by balance, stable so equal coins keep their order.
+func sortedCoins(coins Coins, descending bool) Coins {
+	sorted := make(Coins, len(coins))
+	copy(sorted, coins)
+	sort.SliceStable(
+		sorted, func(i, j int) bool {
+			if descending {
+				return sorted[i].Balance.Uint64() > sorted[j].Balance.Uint64()
+			}
+			return sorted[i].Balance.Uint64() < sorted[j].Balance.Uint64()
+		},
+	)
+	return sorted
+}
+
+// checkCoverable returns the error of a selection of coins which cannot cover amount.
+func checkCoverable(coins Coins, amount *big.Int, maxCoins int) error {
+	if coins.TotalBalance().Cmp(amount) < 0 {
+		return ErrCoinsNeedMoreObject
+	}
+	if maxCoins > 0 && maxCoins < len(coins) {
+		largest := sortedCoins(coins, true)[:maxCoins]
+		if largest.TotalBalance().Cmp(amount) < 0 {
+			return ErrNeedMergeCoin
+		}
+	}
+	return nil
+}
+
+// takeUntil returns the first coins of ordered covering amount, or false if they are more than
+// maxCoins.
+func takeUntil(ordered Coins, amount *big.Int, maxCoins int) (Coins, bool) {
+	total := new(big.Int)
+	for i, coin := range ordered {
+		if total.Cmp(amount) >= 0 {
+			return ordered[:i:i], true
+		}
+		if maxCoins > 0 && i >= maxCoins {
+			return nil, false
+		}
+		total.Add(total, coinBalance(coin))
+	}
+	if total.Cmp(amount) >= 0 && (maxCoins <= 0 || len(ordered) <= maxCoins) {
+		return ordered, true
+	}
+	return nil, false
+}
+
+func (LargestFirst) SelectCoins(coins Coins, amount *big.Int, maxCoins int) (Coins, error) {
+	if err := checkCoverable(coins, amount, maxCoins); err != nil {
+		return nil, err
+	}
+	selected, _ := takeUntil(sortedCoins(coins, true), amount, maxCoins)
+	return selected, nil
+}
+
+func (InOrder) SelectCoins(coins Coins, amount *big.Int, maxCoins int) (Coins, error) {
+	if err := checkCoverable(coins, amount, 0); err != nil {
+		return nil, err
+	}
+	selected, ok := takeUntil(append(Coins(nil), coins...), amount, maxCoins)
+	if !ok {
+		return nil, ErrNeedMergeCoin
+	}
+	return selected, nil
+}
+
+func (SmallestFirst) SelectCoins(coins Coins, amount *big.Int, maxCoins int) (Coins, error) {
+	if err := checkCoverable(coins, amount, maxCoins); err != nil {
+		return nil, err
+	}
+	ascending := sortedCoins(coins, false)
+	if selected, ok := takeUntil(ascending, amount, maxCoins); ok {
+		return selected, nil
+	}
+	// the j smallest coins and the maxCoins-j largest ones, with as many small coins as possible
+	n := len(ascending)
+	for j := maxCoins - 1; j >= 0; j-- {
+		selected := append(append(Coins(nil), ascending[:j]...), ascending[n-(maxCoins-j):]...)
+		if selected.TotalBalance().Cmp(amount) >= 0 {
+			return selected, nil
+		}
+	}
+	// unreachable, checkCoverable found the largest coins cover the amount
+	return nil, ErrNeedMergeCoin
+}
+
+func (s BestFit) SelectCoins(coins Coins, amount *big.Int, maxCoins int) (Coins, error) {
+	if err := checkCoverable(coins, amount, maxCoins); err != nil {
+		return nil, err
+	}
+	descending := sortedCoins(coins, true)
+	best, _ := takeUntil(descending, amount, maxCoins)
+	if amount.Sign() <= 0 {
+		return best, nil
+	}
+	search := bestFitSearch{
+		coins:    descending,
+		amount:   amount,
+		maxCoins: maxCoins,
+		tries:    s.MaxTries,
+		best:     best,
+		bestSum:  best.TotalBalance(),
+	}
+	if search.tries <= 0 {
+		search.tries = 100_000
+	}
+	search.suffix = make([]*big.Int, len(descending)+1)
+	search.suffix[len(descending)] = new(big.Int)
+	for i := len(descending) - 1; i >= 0; i-- {
+		search.suffix[i] = new(big.Int).Add(search.suffix[i+1], coinBalance(descending[i]))
+	}
+	search.run(0, new(big.Int), nil)
+	return search.best, nil
+}
+
+// bestFitSearch is a depth first branch and bound search over the coins sorted by descending
+// balance, each coin is first included then excluded.
+type bestFitSearch struct {
+	coins    Coins
+	suffix   []*big.Int
+	amount   *big.Int
+	maxCoins int
+	tries    int
+
+	best    Coins
+	bestSum *big.Int
+}
+
+func (s *bestFitSearch) run(i int, sum *big.Int, selected []int) {
+	if s.tries <= 0 || s.bestSum.Cmp(s.amount) == 0 && len(s.best) == 1 {
+		return
+	}
+	s.tries--
+	if sum.Cmp(s.amount) >= 0 {
+		if cmp := sum.Cmp(s.bestSum); cmp < 0 || cmp == 0 && len(selected) < len(s.best) {
+			s.best = make(Coins, len(selected))
+			for k, idx := range selected {
+				s.best[k] = s.coins[idx]
+			}
+			s.bestSum = new(big.Int).Set(sum)
+		}
+		return
+	}
+	if i == len(s.coins) || s.maxCoins > 0 && len(selected) >= s.maxCoins {
+		return
+	}
+	// the remaining coins cannot reach the amount, or any selection from here costs more change
+	if new(big.Int).Add(sum, s.suffix[i]).Cmp(s.amount) < 0 || sum.Cmp(s.bestSum) >= 0 {
+		return
+	}
+	s.run(i+1, new(big.Int).Add(sum, coinBalance(s.coins[i])), append(selected, i))
+	s.run(i+1, sum, selected)
+}
+
+func (s RandomSelection) SelectCoins(coins Coins, amount *big.Int, maxCoins int) (Coins, error) {
+	if err := checkCoverable(coins, amount, maxCoins); err != nil {
+		return nil, err
+	}
+	random := s.Rand
+	if random == nil {
+		random = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	shuffled := append(Coins(nil), coins...)
+	random.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	if selected, ok := takeUntil(shuffled, amount, maxCoins); ok {
+		return selected, nil
+	}
+	return LargestFirst{}.SelectCoins(coins, amount, maxCoins)
+}
+
+// DefaultCoinSelector is the selector of PickupCoins and of the client helpers given none,
+// LargestFirst: it needs the fewest inputs, so it fails the least with ErrNeedMergeCoin.
+var DefaultCoinSelector CoinSelector = LargestFirst{}
+
+// PickupCoinsWith selects the coins of a page covering targetAmount and gasBudget with selector, at
+// most limit of them, MAX_INPUT_COUNT_MERGE by default. When the coins cover targetAmount but not
+// the gas budget too, as many of them as possible are returned, all of them in order or the limit
+// largest ones, and the gas budget must be lowered to PickedCoins.SuggestMaxGasBudget.
+func PickupCoinsWith(
+	selector CoinSelector,
+	inputCoins *CoinPage,
+	targetAmount big.Int,
+	gasBudget uint64,
+	limit int,
+) (*PickedCoins, error) {
+	if len(inputCoins.Data) == 0 {
+		return nil, ErrNoCoinsFound
+	}
+	if limit <= 0 {
+		limit = MAX_INPUT_COUNT_MERGE
+	}
+	totalTarget := new(big.Int).Add(&targetAmount, new(big.Int).SetUint64(gasBudget))
+	coins, err := selector.SelectCoins(inputCoins.Data, totalTarget, limit)
+	if err == ErrCoinsNeedMoreObject {
+		if inputCoins.HasNextPage {
+			return nil, ErrNeedMergeCoin
+		}
+		if err = checkCoverable(inputCoins.Data, &targetAmount, limit); err == ErrCoinsNeedMoreObject {
+			return nil, ErrInsufficientBalance
+		}
+		coins = append(Coins(nil), inputCoins.Data...)
+		if len(coins) > limit {
+			coins = sortedCoins(coins, true)[:limit]
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &PickedCoins{
+		Coins:        coins,
+		TotalAmount:  *coins.TotalBalance(),
+		TargetAmount: targetAmount,
+	}, nil
+}
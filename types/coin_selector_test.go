@@ -0,0 +1,180 @@
+package types
+
+import (
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+)
+
+// selectionCase is a random set of coins, an amount and a max number of coins.
+type selectionCase struct {
+	Coins    Coins
+	Amount   *big.Int
+	MaxCoins int
+}
+
+func (selectionCase) Generate(random *rand.Rand, size int) reflect.Value {
+	c := selectionCase{Coins: make(Coins, random.Intn(20))}
+	total := uint64(0)
+	for i := range c.Coins {
+		balance := uint64(random.Intn(1000) + 1)
+		if random.Intn(4) == 0 {
+			// some dust
+			balance = uint64(random.Intn(5) + 1)
+		}
+		c.Coins[i] = Coin{CoinObjectId: suiObjectID{byte(i + 1)}, Balance: balanceObject(balance)}
+		total += balance
+	}
+	// amounts around the total, so some cannot be covered
+	c.Amount = big.NewInt(random.Int63n(int64(total)*5/4 + 1))
+	if random.Intn(2) == 0 {
+		c.MaxCoins = random.Intn(6) + 1
+	}
+	return reflect.ValueOf(c)
+}
+
+// coverable returns the error every selector must return for c.
+func (c selectionCase) coverable() error {
+	if c.Coins.TotalBalance().Cmp(c.Amount) < 0 {
+		return ErrCoinsNeedMoreObject
+	}
+	if c.MaxCoins > 0 && c.MaxCoins < len(c.Coins) &&
+		sortedCoins(c.Coins, true)[:c.MaxCoins].TotalBalance().Cmp(c.Amount) < 0 {
+		return ErrNeedMergeCoin
+	}
+	return nil
+}
+
+// checkSelection checks selected is a valid selection of distinct coins of c.
+func checkSelection(c selectionCase, selected Coins) bool {
+	seen := make(map[suiObjectID]bool)
+	for _, coin := range selected {
+		if seen[coin.CoinObjectId] {
+			return false
+		}
+		seen[coin.CoinObjectId] = true
+		found := false
+		for _, input := range c.Coins {
+			found = found || input.CoinObjectId == coin.CoinObjectId && input.Balance == coin.Balance
+		}
+		if !found {
+			return false
+		}
+	}
+	return selected.TotalBalance().Cmp(c.Amount) >= 0 && (c.MaxCoins <= 0 || len(selected) <= c.MaxCoins)
+}
+
+func TestCoinSelectors_Properties(t *testing.T) {
+	selectors := map[string]CoinSelector{
+		"LargestFirst":    LargestFirst{},
+		"SmallestFirst":   SmallestFirst{},
+		"BestFit":         BestFit{},
+		"RandomSelection": RandomSelection{Rand: rand.New(rand.NewSource(1))},
+	}
+	config := &quick.Config{MaxCount: 2000}
+	for name, selector := range selectors {
+		selector := selector
+		t.Run(
+			name, func(t *testing.T) {
+				property := func(c selectionCase) bool {
+					selected, err := selector.SelectCoins(c.Coins, c.Amount, c.MaxCoins)
+					if want := c.coverable(); want != nil || err != nil {
+						return err == want
+					}
+					return checkSelection(c, selected)
+				}
+				require.NoError(t, quick.Check(property, config))
+			},
+		)
+	}
+}
+
+func TestInOrder_Properties(t *testing.T) {
+	property := func(c selectionCase) bool {
+		selected, err := InOrder{}.SelectCoins(c.Coins, c.Amount, c.MaxCoins)
+		if err != nil {
+			return err == c.coverable() || err == ErrNeedMergeCoin && c.coverable() == nil
+		}
+		// a prefix of the coins
+		return checkSelection(c, selected) && (len(selected) == 0 || reflect.DeepEqual(selected, c.Coins[:len(selected)]))
+	}
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 2000}))
+}
+
+func TestLargestFirst_FewestCoins(t *testing.T) {
+	property := func(c selectionCase) bool {
+		selected, err := LargestFirst{}.SelectCoins(c.Coins, c.Amount, c.MaxCoins)
+		if err != nil {
+			return true
+		}
+		// no selection of fewer coins covers the amount
+		fewer := len(selected) - 1
+		return fewer < 0 || sortedCoins(c.Coins, true)[:fewer].TotalBalance().Cmp(c.Amount) < 0
+	}
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 2000}))
+}
+
+func TestBestFit_LeastChange(t *testing.T) {
+	property := func(c selectionCase) bool {
+		best, err := BestFit{}.SelectCoins(c.Coins, c.Amount, c.MaxCoins)
+		if err != nil {
+			return true
+		}
+		for _, selector := range []CoinSelector{LargestFirst{}, SmallestFirst{}} {
+			other, err := selector.SelectCoins(c.Coins, c.Amount, c.MaxCoins)
+			if err != nil || best.TotalBalance().Cmp(other.TotalBalance()) > 0 {
+				return false
+			}
+		}
+		return true
+	}
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 2000}))
+
+	coins := Coins{
+		{Balance: balanceObject(700)}, {Balance: balanceObject(400)}, {Balance: balanceObject(350)},
+		{Balance: balanceObject(250)}, {Balance: balanceObject(5)},
+	}
+	selected, err := BestFit{}.SelectCoins(coins, big.NewInt(600), 0)
+	require.NoError(t, err)
+	require.Equal(t, Coins{{Balance: balanceObject(350)}, {Balance: balanceObject(250)}}, selected)
+}
+
+func TestSmallestFirst_ConsumesDust(t *testing.T) {
+	coins := Coins{
+		{Balance: balanceObject(1000)}, {Balance: balanceObject(1)}, {Balance: balanceObject(900)},
+		{Balance: balanceObject(2)}, {Balance: balanceObject(3)},
+	}
+	selected, err := SmallestFirst{}.SelectCoins(coins, big.NewInt(5), 0)
+	require.NoError(t, err)
+	require.Len(t, selected, 3)
+	// the dust is completed by the largest coin within the limit
+	selected, err = SmallestFirst{}.SelectCoins(coins, big.NewInt(1500), 3)
+	require.NoError(t, err)
+	require.Equal(t, Coins{{Balance: balanceObject(1)}, {Balance: balanceObject(900)}, {Balance: balanceObject(1000)}}, selected)
+}
+
+func TestPickupCoinsWith(t *testing.T) {
+	coins := Coins{{Balance: balanceObject(1e3)}, {Balance: balanceObject(1e5)}, {Balance: balanceObject(1e2)}}
+	page := &CoinPage{Data: coins}
+
+	picked, err := PickupCoinsWith(BestFit{}, page, *big.NewInt(1e3), 100, 0)
+	require.NoError(t, err)
+	require.Equal(t, "1100", picked.TotalAmount.String())
+	// only the target is covered, the gas budget must be lowered
+	picked, err = PickupCoinsWith(LargestFirst{}, page, *big.NewInt(101050), 1e3, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(50), picked.SuggestMaxGasBudget())
+
+	_, err = PickupCoinsWith(LargestFirst{}, page, *big.NewInt(1e6), 0, 0)
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+	_, err = PickupCoinsWith(LargestFirst{}, &CoinPage{Data: coins, HasNextPage: true}, *big.NewInt(1e6), 0, 0)
+	require.ErrorIs(t, err, ErrNeedMergeCoin)
+	_, err = PickupCoinsWith(SmallestFirst{}, page, *big.NewInt(101000), 0, 1)
+	require.ErrorIs(t, err, ErrNeedMergeCoin)
+	_, err = PickupCoinsWith(SmallestFirst{}, &CoinPage{}, *big.NewInt(1), 0, 1)
+	require.ErrorIs(t, err, ErrNoCoinsFound)
+}
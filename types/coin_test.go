@@ -218,6 +218,34 @@ func TestCoins_PickCoins(t *testing.T) {
 			want:    testCoins,
 			wantErr: false,
 		},
+		{
+			name:    "zero amount smaller",
+			cs:      testCoins,
+			args:    args{amount: big.NewInt(0), pickMethod: PickSmaller},
+			want:    Coins{{Balance: balanceObject(1)}},
+			wantErr: false,
+		},
+		{
+			name:    "zero amount bigger",
+			cs:      testCoins,
+			args:    args{amount: big.NewInt(0), pickMethod: PickBigger},
+			want:    Coins{{Balance: balanceObject(5)}},
+			wantErr: false,
+		},
+		{
+			name:    "zero amount by order",
+			cs:      testCoins,
+			args:    args{amount: big.NewInt(0), pickMethod: PickByOrder},
+			want:    Coins{{Balance: balanceObject(3)}},
+			wantErr: false,
+		},
+		{
+			name:    "zero amount no coins",
+			cs:      Coins{},
+			args:    args{amount: big.NewInt(0), pickMethod: PickByOrder},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name:    "pick error",
 			cs:      testCoins,
@@ -279,6 +307,24 @@ func TestPickupCoins(t *testing.T) {
 				TargetAmount: *big.NewInt(1e3),
 			},
 		},
+		{
+			name: "moreCount = 0, default selector",
+			args: args{
+				inputCoins: &Page[Coin, suiObjectID]{
+					Data: []Coin{
+						coin(1e3), coin(1e5), coin(1e2), coin(1e4),
+					},
+				},
+				targetAmount: *big.NewInt(1e3),
+			},
+			want: &PickedCoins{
+				Coins: []Coin{
+					coin(1e5),
+				},
+				TotalAmount:  *big.NewInt(1e5),
+				TargetAmount: *big.NewInt(1e3),
+			},
+		},
 		{
 			name: "large gas",
 			args: args{
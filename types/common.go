@@ -82,8 +82,17 @@ type ObjectOwner struct {
 	*string
 }
 
-type Page[T SuiTransactionBlockResponse | SuiEvent | Coin | SuiObjectResponse | DynamicFieldInfo | string,
-	C sui_types.TransactionDigest | EventId | sui_types.ObjectID] struct {
+// PageData is the set of item types returned by the paged RPCs.
+type PageData interface {
+	SuiTransactionBlockResponse | SuiEvent | Coin | SuiObjectResponse | DynamicFieldInfo | string | Checkpoint | EpochInfo
+}
+
+// PageCursor is the set of cursor types of the paged RPCs.
+type PageCursor interface {
+	sui_types.TransactionDigest | EventId | sui_types.ObjectID | SafeSuiBigInt[uint64]
+}
+
+type Page[T PageData, C PageCursor] struct {
 	Data        []T  `json:"data"`
 	NextCursor  *C   `json:"nextCursor,omitempty"`
 	HasNextPage bool `json:"hasNextPage"`
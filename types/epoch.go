@@ -0,0 +1,115 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+type EpochInfo struct {
+	Epoch SafeSuiBigInt[EpochId] `json:"epoch"`
+	// List of validators included in epoch
+	Validators             []SuiValidatorSummary                   `json:"validators"`
+	EpochTotalTransactions SafeSuiBigInt[uint64]                   `json:"epochTotalTransactions"`
+	FirstCheckpointId      SafeSuiBigInt[CheckpointSequenceNumber] `json:"firstCheckpointId"`
+	EpochStartTimestamp    SafeSuiBigInt[uint64]                   `json:"epochStartTimestamp"`
+	// Present once the epoch has ended
+	EndOfEpochInfo    *EndOfEpochInfo `json:"endOfEpochInfo,omitempty"`
+	ReferenceGasPrice *uint64         `json:"referenceGasPrice,omitempty"`
+}
+
+type EndOfEpochInfo struct {
+	LastCheckpointId  SafeSuiBigInt[CheckpointSequenceNumber] `json:"lastCheckpointId"`
+	EpochEndTimestamp SafeSuiBigInt[uint64]                   `json:"epochEndTimestamp"`
+	// existing fields from `SystemEpochInfoEvent` (without epoch)
+	ProtocolVersion              SafeSuiBigInt[uint64] `json:"protocolVersion"`
+	ReferenceGasPrice            SafeSuiBigInt[uint64] `json:"referenceGasPrice"`
+	TotalStake                   SafeSuiBigInt[uint64] `json:"totalStake"`
+	StorageFundReinvestment      SafeSuiBigInt[uint64] `json:"storageFundReinvestment"`
+	StorageCharge                SafeSuiBigInt[uint64] `json:"storageCharge"`
+	StorageRebate                SafeSuiBigInt[uint64] `json:"storageRebate"`
+	StorageFundBalance           SafeSuiBigInt[uint64] `json:"storageFundBalance"`
+	StakeSubsidyAmount           SafeSuiBigInt[uint64] `json:"stakeSubsidyAmount"`
+	TotalGasFees                 SafeSuiBigInt[uint64] `json:"totalGasFees"`
+	TotalStakeRewardsDistributed SafeSuiBigInt[uint64] `json:"totalStakeRewardsDistributed"`
+	LeftoverStorageFundInflow    SafeSuiBigInt[uint64] `json:"leftoverStorageFundInflow"`
+}
+
+type EpochPage = Page[EpochInfo, SafeSuiBigInt[EpochId]]
+
+type CommitteeInfo struct {
+	Epoch      SafeSuiBigInt[EpochId] `json:"epoch"`
+	Validators []CommitteeMember      `json:"validators"`
+}
+
+func (c *CommitteeInfo) TotalStake() uint64 {
+	var total uint64
+	for _, v := range c.Validators {
+		total += v.Stake.Uint64()
+	}
+	return total
+}
+
+// StakeOf returns the stake of the validator with the protocol public key authorityName.
+func (c *CommitteeInfo) StakeOf(authorityName lib.Base64Data) (uint64, bool) {
+	for _, v := range c.Validators {
+		if string(v.AuthorityName) == string(authorityName) {
+			return v.Stake.Uint64(), true
+		}
+	}
+	return 0, false
+}
+
+type NetworkMetrics struct {
+	// Current TPS - Transaction Blocks per Second.
+	CurrentTps float64 `json:"currentTps"`
+	// Peak TPS in the past 30 days
+	Tps30Days         float64                                 `json:"tps30Days"`
+	CurrentCheckpoint SafeSuiBigInt[CheckpointSequenceNumber] `json:"currentCheckpoint"`
+	CurrentEpoch      SafeSuiBigInt[EpochId]                  `json:"currentEpoch"`
+	TotalAddresses    SafeSuiBigInt[uint64]                   `json:"totalAddresses"`
+	TotalObjects      SafeSuiBigInt[uint64]                   `json:"totalObjects"`
+	TotalPackages     SafeSuiBigInt[uint64]                   `json:"totalPackages"`
+}
+
+type MoveFunctionName struct {
+	Package  sui_types.ObjectID `json:"package"`
+	Module   string             `json:"module"`
+	Function string             `json:"function"`
+}
+
+func (n MoveFunctionName) String() string {
+	return fmt.Sprintf("%v::%v::%v", n.Package.ShortString(), n.Module, n.Function)
+}
+
+// MoveCallRank is the [function, count] pair of the move call metrics.
+type MoveCallRank struct {
+	Function MoveFunctionName
+	Count    SafeSuiBigInt[uint64]
+}
+
+func (r MoveCallRank) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{r.Function, r.Count})
+}
+
+func (r *MoveCallRank) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("move call rank [%s] is not a pair", string(data))
+	}
+	if err := json.Unmarshal(pair[0], &r.Function); err != nil {
+		return err
+	}
+	return json.Unmarshal(pair[1], &r.Count)
+}
+
+type MoveCallMetrics struct {
+	Rank3Days  []MoveCallRank `json:"rank3Days"`
+	Rank7Days  []MoveCallRank `json:"rank7Days"`
+	Rank30Days []MoveCallRank `json:"rank30Days"`
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEpochPage_UnmarshalJSON(t *testing.T) {
+	data := `{
+		"data": [{
+			"epoch": "10",
+			"validators": [],
+			"epochTotalTransactions": "5432",
+			"firstCheckpointId": "9000",
+			"epochStartTimestamp": "1681393657483",
+			"endOfEpochInfo": {
+				"lastCheckpointId": "9999",
+				"epochEndTimestamp": "1681480057483",
+				"protocolVersion": "8",
+				"referenceGasPrice": "1000",
+				"totalStake": "7000000",
+				"storageFundReinvestment": "0",
+				"storageCharge": "120",
+				"storageRebate": "80",
+				"storageFundBalance": "500",
+				"stakeSubsidyAmount": "1000000",
+				"totalGasFees": "300",
+				"totalStakeRewardsDistributed": "1000300",
+				"leftoverStorageFundInflow": "0"
+			},
+			"referenceGasPrice": 1000
+		}],
+		"nextCursor": "10",
+		"hasNextPage": true
+	}`
+	var page EpochPage
+	require.NoError(t, json.Unmarshal([]byte(data), &page))
+	require.Equal(t, uint64(10), page.NextCursor.Uint64())
+	epoch := page.Data[0]
+	require.Equal(t, uint64(9000), epoch.FirstCheckpointId.Uint64())
+	require.Equal(t, uint64(9999), epoch.EndOfEpochInfo.LastCheckpointId.Uint64())
+	require.Equal(t, uint64(300), epoch.EndOfEpochInfo.TotalGasFees.Uint64())
+	require.Equal(t, uint64(1000), *epoch.ReferenceGasPrice)
+}
+
+func TestCommitteeInfo_UnmarshalJSON(t *testing.T) {
+	var committee CommitteeInfo
+	data := `{"epoch": "10", "validators": [["AQID", "2500"], ["BAUG", "7500"]]}`
+	require.NoError(t, json.Unmarshal([]byte(data), &committee))
+	require.Equal(t, uint64(10000), committee.TotalStake())
+	stake, ok := committee.StakeOf(committee.Validators[1].AuthorityName)
+	require.True(t, ok)
+	require.Equal(t, uint64(7500), stake)
+}
+
+func TestMetrics_UnmarshalJSON(t *testing.T) {
+	var metrics NetworkMetrics
+	data := `{"currentTps": 12.5, "tps30Days": 480.25, "currentCheckpoint": "9999", "currentEpoch": "10",
+		"totalAddresses": "100", "totalObjects": "2000", "totalPackages": "30"}`
+	require.NoError(t, json.Unmarshal([]byte(data), &metrics))
+	require.Equal(t, 12.5, metrics.CurrentTps)
+	require.Equal(t, uint64(9999), metrics.CurrentCheckpoint.Uint64())
+
+	var calls MoveCallMetrics
+	data = `{"rank3Days": [[{"package": "0x2", "module": "pay", "function": "split"}, "42"]], "rank7Days": [], "rank30Days": []}`
+	require.NoError(t, json.Unmarshal([]byte(data), &calls))
+	require.Equal(t, "0x2::pay::split", calls.Rank3Days[0].Function.String())
+	require.Equal(t, uint64(42), calls.Rank3Days[0].Count.Uint64())
+}
@@ -11,4 +11,8 @@ var (
 
 	ErrCoinsNotMatchRequest = errors.New("coins not match request")
 	ErrCoinsNeedMoreObject  = errors.New("you should get more SUI coins and try again")
+
+	ErrObjectDataNotFound      = errors.New("object response has no data")
+	ErrNoMoveObjectBcs         = errors.New("object response has no move object bcs, query it with ShowBcs")
+	ErrMoveStructNotRegistered = errors.New("move struct type not registered")
 )
@@ -0,0 +1,180 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+// Kinds of ExecutionError, the name of the ExecutionFailureStatus variant reported by the node.
+const (
+	ExecutionErrorInsufficientGas         = "InsufficientGas"
+	ExecutionErrorInsufficientCoinBalance = "InsufficientCoinBalance"
+	ExecutionErrorMoveAbort               = "MoveAbort"
+	ExecutionErrorMovePrimitiveRuntime    = "MovePrimitiveRuntimeError"
+	ExecutionErrorCommandArgumentError    = "CommandArgumentError"
+	ExecutionErrorInvalidGasObject        = "InvalidGasObject"
+	ExecutionErrorCoinBalanceOverflow     = "CoinBalanceOverflow"
+)
+
+// Categories of the errors returned by the node, test them with errors.Is.
+var (
+	ErrObjectVersionConflict = errors.New("object version is not available for consumption")
+	ErrObjectLocked          = errors.New("object is locked by another transaction")
+	ErrInsufficientGas       = errors.New("insufficient gas")
+	ErrNodeOverloaded        = errors.New("node is overloaded")
+	ErrTransactionNotFound   = errors.New("transaction not found")
+)
+
+var knownErrorMessages = []struct {
+	err       error
+	fragments []string
+}{
+	{ErrObjectVersionConflict, []string{"ObjectVersionUnavailableForConsumption", "is not available for consumption"}},
+	{ErrObjectLocked, []string{"ObjectLockConflict", "ObjectLockedAtEpoch", "equivocated", "already locked"}},
+	{ErrInsufficientGas, []string{"InsufficientGas", "GasBalanceTooLow", "lower than the needed amount"}},
+	{ErrTransactionNotFound, []string{"Could not find the referenced transaction"}},
+	{
+		ErrNodeOverloaded, []string{
+			"TooManyTransactionsPendingExecution", "TooManyTransactionsPendingConsensus",
+			"TooManyTransactionsPendingOnObject", "ValidatorOverloadedRetryAfter", "overloaded",
+		},
+	},
+}
+
+// ClassifyError returns the category of an error message of the node, like ErrObjectLocked, or nil
+// if it is none of them.
+func ClassifyError(message string) error {
+	for _, known := range knownErrorMessages {
+		for _, fragment := range known.fragments {
+			if strings.Contains(message, fragment) {
+				return known.err
+			}
+		}
+	}
+	return nil
+}
+
+var (
+	executionErrorKind    = regexp.MustCompile(`^[A-Za-z]+`)
+	executionErrorCommand = regexp.MustCompile(` in command (\d+)$`)
+	moveAbortPattern      = regexp.MustCompile(
+		`MoveAbort\(MoveLocation \{ module: ModuleId \{ address: (?:0x)?([0-9a-fA-F]+), name: Identifier\("([^"]*)"\) \}, ` +
+			`function: (\d+), instruction: (\d+), function_name: (?:Some\("([^"]*)"\)|None) \}, (\d+)\)`,
+	)
+)
+
+// MoveAbort is a Move abort, the location and the code of the abort.
+type MoveAbort struct {
+	Package sui_types.ObjectID
+	Module  string
+	// Function is empty if the node did not report the function name.
+	Function      string
+	FunctionIndex uint16
+	Instruction   uint16
+	Code          uint64
+}
+
+// ParseMoveAbort finds the Move abort in an execution status or RPC error message, nil if there is
+// none.
+func ParseMoveAbort(message string) *MoveAbort {
+	match := moveAbortPattern.FindStringSubmatch(message)
+	if match == nil {
+		return nil
+	}
+	packageId, err := sui_types.NewObjectIdFromHex(match[1])
+	if err != nil {
+		return nil
+	}
+	functionIndex, err := strconv.ParseUint(match[3], 10, 16)
+	if err != nil {
+		return nil
+	}
+	instruction, err := strconv.ParseUint(match[4], 10, 16)
+	if err != nil {
+		return nil
+	}
+	code, err := strconv.ParseUint(match[6], 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &MoveAbort{
+		Package:       *packageId,
+		Module:        match[2],
+		Function:      match[5],
+		FunctionIndex: uint16(functionIndex),
+		Instruction:   uint16(instruction),
+		Code:          code,
+	}
+}
+
+func (a *MoveAbort) Error() string {
+	function := a.Function
+	if function == "" {
+		function = fmt.Sprintf("#%d", a.FunctionIndex)
+	}
+	return fmt.Sprintf("move abort in %v::%v::%v with code %d", a.Package.ShortString(), a.Module, function, a.Code)
+}
+
+// ExecutionError is returned for a transaction which was executed with a failure status. Its gas
+// is charged and the transaction can not be submitted again.
+type ExecutionError struct {
+	Digest sui_types.TransactionDigest
+	// Kind is the failure variant, like ExecutionErrorMoveAbort.
+	Kind string
+	// Command is the index of the failed command, if the node reported one.
+	Command *int
+	// Message is the error of the execution status.
+	Message string
+	// MoveAbort is set for an ExecutionErrorMoveAbort, errors.As finds it too.
+	MoveAbort *MoveAbort
+	// Response is nil for an error of the effects alone.
+	Response *SuiTransactionBlockResponse
+}
+
+// NewExecutionError returns the error of a failed transaction, nil if it did not fail.
+func NewExecutionError(resp *SuiTransactionBlockResponse) *ExecutionError {
+	if !resp.Failed() {
+		return nil
+	}
+	err := newExecutionError(resp.Digest, resp.ExecutionStatus().Error)
+	err.Response = resp
+	return err
+}
+
+func newExecutionError(digest sui_types.TransactionDigest, message string) *ExecutionError {
+	err := &ExecutionError{
+		Digest:  digest,
+		Kind:    executionErrorKind.FindString(message),
+		Message: message,
+	}
+	if match := executionErrorCommand.FindStringSubmatch(message); match != nil {
+		if command, parseErr := strconv.Atoi(match[1]); parseErr == nil {
+			err.Command = &command
+		}
+	}
+	if err.Kind == ExecutionErrorMoveAbort {
+		err.MoveAbort = ParseMoveAbort(message)
+	}
+	return err
+}
+
+func (e *ExecutionError) Error() string {
+	return fmt.Sprintf("transaction %v failed: %s", e.Digest, e.Message)
+}
+
+// Is matches ErrInsufficientGas for an ExecutionErrorInsufficientGas.
+func (e *ExecutionError) Is(target error) bool {
+	return target == ErrInsufficientGas && e.Kind == ExecutionErrorInsufficientGas
+}
+
+func (e *ExecutionError) Unwrap() error {
+	if e.MoveAbort == nil {
+		return nil
+	}
+	return e.MoveAbort
+}
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExecutionError(t *testing.T) {
+	var resp SuiTransactionBlockResponse
+	require.NoError(
+		t, json.Unmarshal(
+			[]byte(`{
+				"digest": "HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn",
+				"effects": {
+					"messageVersion": "v1",
+					"status": {
+						"status": "failure",
+						"error": "MoveAbort(MoveLocation { module: ModuleId { address: 0000000000000000000000000000000000000000000000000000000000000002, name: Identifier(\"coin\") }, function: 7, instruction: 9, function_name: Some(\"split\") }, 0) in command 1"
+					}
+				}
+			}`), &resp,
+		),
+	)
+	err := NewExecutionError(&resp)
+	require.NotNil(t, err)
+	require.Equal(t, ExecutionErrorMoveAbort, err.Kind)
+	require.Equal(t, 1, *err.Command)
+	require.Equal(t, "HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn", err.Digest.String())
+	require.False(t, errors.Is(err, ErrInsufficientGas))
+
+	var abort *MoveAbort
+	require.True(t, errors.As(error(err), &abort))
+	require.Equal(t, "0x2", abort.Package.ShortString())
+	require.Equal(t, "coin", abort.Module)
+	require.Equal(t, "split", abort.Function)
+	require.Equal(t, uint16(7), abort.FunctionIndex)
+	require.Equal(t, uint16(9), abort.Instruction)
+	require.Equal(t, uint64(0), abort.Code)
+	require.Equal(t, "move abort in 0x2::coin::split with code 0", abort.Error())
+
+	var effectsErr *ExecutionError
+	require.True(t, errors.As(resp.Effects.Data.Err(), &effectsErr))
+	require.Equal(t, err.Message, effectsErr.Message)
+	require.Nil(t, effectsErr.Response)
+
+	resp.Effects.Data.V1.Status = ExecutionStatus{Status: "failure", Error: "InsufficientGas"}
+	require.True(t, errors.Is(NewExecutionError(&resp), ErrInsufficientGas))
+	require.Nil(t, NewExecutionError(&resp).MoveAbort)
+
+	resp.Effects.Data.V1.Status = ExecutionStatus{Status: "success"}
+	require.Nil(t, NewExecutionError(&resp))
+	require.NoError(t, resp.Effects.Data.Err())
+}
+
+func TestParseMoveAbort(t *testing.T) {
+	abort := ParseMoveAbort(
+		`Error checking transaction input objects: MoveAbort(MoveLocation { module: ModuleId { address: 0x0f00, name: Identifier("pool") }, function: 3, instruction: 12, function_name: None }, 18446744073709551615)`,
+	)
+	require.NotNil(t, abort)
+	require.Equal(t, "0xf00", abort.Package.ShortString())
+	require.Equal(t, "", abort.Function)
+	require.Equal(t, uint64(18446744073709551615), abort.Code)
+	require.Equal(t, "move abort in 0xf00::pool::#3 with code 18446744073709551615", abort.Error())
+
+	require.Nil(t, ParseMoveAbort("InsufficientGas"))
+}
+
+func TestClassifyError(t *testing.T) {
+	cases := map[string]error{
+		"Transaction is rejected as invalid by more than 1/3 of validators by stake (non-retriable). " +
+			"Non-retriable errors: [ObjectVersionUnavailableForConsumption { provided_obj_ref: (0x1, SequenceNumber(1)) }]": ErrObjectVersionConflict,
+		"Failed to sign transaction by a quorum of validators because one or more of its objects is equivocated until the next epoch": ErrObjectLocked,
+		"Balance of gas object 10 is lower than the needed amount: 100":                                                               ErrInsufficientGas,
+		"Transaction is not processed because 10 of validators by stake are overloaded":                                               ErrNodeOverloaded,
+		"Could not find the referenced transaction [TransactionDigest(HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn)].":                ErrTransactionNotFound,
+		"Invalid user signature": nil,
+	}
+	for message, expected := range cases {
+		require.Equal(t, expected, ClassifyError(message), message)
+	}
+}
@@ -0,0 +1,256 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/move_types"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+var (
+	moveJsonAddressType = reflect.TypeOf(sui_types.SuiAddress{})
+	moveJsonUIDType     = reflect.TypeOf(sui_types.UID{})
+	moveJsonIDType      = reflect.TypeOf(sui_types.ID{})
+	moveJsonBalanceType = reflect.TypeOf(sui_types.Balance{})
+	moveJsonUrlType     = reflect.TypeOf(sui_types.Url{})
+	moveJsonUint128Type = reflect.TypeOf(bcs.Uint128{})
+	moveJsonOptionPath  = reflect.TypeOf(move_types.Option[bool]{}).PkgPath()
+)
+
+// DecodeMoveJson decodes a move value in the json form of the node, like the ParsedJson of an
+// event or the fields of an object content, into v which has the BCS layout of the move struct.
+// Struct fields are matched by their json tag, or by the snake case of the field name.
+// A u256 is decoded into a [32]uint8 in little endian like its BCS encoding.
+func DecodeMoveJson(value any, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.New("decode move json into a non pointer value")
+	}
+	if raw, ok := value.(json.RawMessage); ok {
+		decoder := json.NewDecoder(strings.NewReader(string(raw)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+	}
+	return decodeMoveJsonValue(value, rv.Elem())
+}
+
+// DecodeMoveEvent decodes the BCS bytes of event, or its ParsedJson if the node sent no BCS.
+func DecodeMoveEvent[T any](event *SuiEvent) (*T, error) {
+	var v T
+	if event.Bcs != "" {
+		return &v, lib.UnmarshalBCS(base58.Decode(event.Bcs), &v)
+	}
+	return &v, DecodeMoveJson(event.ParsedJson, &v)
+}
+
+func decodeMoveJsonValue(value any, v reflect.Value) error {
+	t := v.Type()
+	switch t {
+	case moveJsonAddressType:
+		address, err := moveJsonAddress(value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(address))
+		return nil
+	case moveJsonIDType:
+		address, err := moveJsonAddress(value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(sui_types.ID{Bytes: address}))
+		return nil
+	case moveJsonUIDType:
+		if fields, ok := value.(map[string]any); ok {
+			value = fields["id"]
+		}
+		address, err := moveJsonAddress(value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(sui_types.UID{Id: sui_types.ID{Bytes: address}}))
+		return nil
+	case moveJsonBalanceType:
+		num, err := moveJsonUint(value, 64)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(sui_types.Balance{Value: num}))
+		return nil
+	case moveJsonUrlType:
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("move json [%v] is not an url", value)
+		}
+		v.Set(reflect.ValueOf(sui_types.Url{Url: str}))
+		return nil
+	case moveJsonUint128Type:
+		num, err := bcs.NewUint128(moveJsonNumberString(value))
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(*num))
+		return nil
+	}
+	if t.PkgPath() == moveJsonOptionPath && strings.HasPrefix(t.Name(), "Option[") {
+		vec := v.Field(0)
+		if value == nil {
+			vec.Set(reflect.Zero(vec.Type()))
+			return nil
+		}
+		vec.Set(reflect.MakeSlice(vec.Type(), 1, 1))
+		return decodeMoveJsonValue(value, vec.Index(0))
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("move json [%v] is not a bool", value)
+		}
+		v.SetBool(b)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		num, err := moveJsonUint(value, t.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(num)
+	case reflect.String:
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("move json [%v] is not a string", value)
+		}
+		v.SetString(str)
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Len() == 32 {
+			if _, ok := value.([]any); !ok {
+				return decodeMoveJsonU256(value, v)
+			}
+		}
+		items, ok := value.([]any)
+		if !ok || len(items) != t.Len() {
+			return fmt.Errorf("move json [%v] is not an array of %d items", value, t.Len())
+		}
+		for i, item := range items {
+			if err := decodeMoveJsonValue(item, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("move json [%v] is not a vector", value)
+		}
+		slice := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			if err := decodeMoveJsonValue(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Pointer:
+		if value == nil {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		elem := reflect.New(t.Elem())
+		if err := decodeMoveJsonValue(value, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Struct:
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("move json [%v] is not a struct", value)
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := moveJsonFieldName(field)
+			if name == "-" {
+				continue
+			}
+			fieldValue, ok := fields[name]
+			if !ok {
+				continue
+			}
+			if err := decodeMoveJsonValue(fieldValue, v.Field(i)); err != nil {
+				return fmt.Errorf("field %s: %w", name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported move json target type %v", t)
+	}
+	return nil
+}
+
+func decodeMoveJsonU256(value any, v reflect.Value) error {
+	num, ok := new(big.Int).SetString(moveJsonNumberString(value), 10)
+	if !ok || num.Sign() < 0 || num.BitLen() > 256 {
+		return fmt.Errorf("move json [%v] is not a u256", value)
+	}
+	bigEndian := num.FillBytes(make([]byte, 32))
+	for i := 0; i < 32; i++ {
+		v.Index(i).SetUint(uint64(bigEndian[31-i]))
+	}
+	return nil
+}
+
+func moveJsonAddress(value any) (sui_types.SuiAddress, error) {
+	str, ok := value.(string)
+	if !ok {
+		return sui_types.SuiAddress{}, fmt.Errorf("move json [%v] is not an address", value)
+	}
+	address, err := sui_types.NewAddressFromHex(str)
+	if err != nil {
+		return sui_types.SuiAddress{}, err
+	}
+	return *address, nil
+}
+
+func moveJsonNumberString(value any) string {
+	switch num := value.(type) {
+	case float64:
+		return strconv.FormatFloat(num, 'f', -1, 64)
+	default:
+		return fmt.Sprint(num)
+	}
+}
+
+func moveJsonUint(value any, bits int) (uint64, error) {
+	return strconv.ParseUint(moveJsonNumberString(value), 10, bits)
+}
+
+func moveJsonFieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get("json"); tag != "" {
+		if name := strings.Split(tag, ",")[0]; name != "" {
+			return name
+		}
+	}
+	var name []rune
+	runes := []rune(field.Name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && !unicode.IsUpper(runes[i-1]) {
+				name = append(name, '_')
+			}
+			r = unicode.ToLower(r)
+		}
+		name = append(name, r)
+	}
+	return string(name)
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+func TestDecodeMoveJson(t *testing.T) {
+	var staked sui_types.StakedSui
+	err := DecodeMoveJson(
+		json.RawMessage(`{"id":{"id":"0x5"},"pool_id":"0x6","stake_activation_epoch":"12","principal":"1000000000"}`),
+		&staked,
+	)
+	require.NoError(t, err)
+	require.Equal(t, "0x5", staked.Id.ObjectID().ShortString())
+	require.Equal(t, "0x6", staked.PoolId.Bytes.ShortString())
+	require.Equal(t, uint64(12), staked.StakeActivationEpoch)
+	require.Equal(t, uint64(1000000000), staked.Principal.Value)
+
+	var metadata sui_types.CoinMetadata
+	err = DecodeMoveJson(
+		json.RawMessage(`{"id":{"id":"0x7"},"decimals":9,"name":"Sui","symbol":"SUI","description":"","icon_url":"https://sui.io/icon.png"}`),
+		&metadata,
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint8(9), metadata.Decimals)
+	iconUrl, ok := metadata.IconUrl.Get()
+	require.True(t, ok)
+	require.Equal(t, "https://sui.io/icon.png", iconUrl.Url)
+	require.NoError(t, DecodeMoveJson(json.RawMessage(`{"icon_url":null}`), &metadata))
+	require.False(t, metadata.IconUrl.IsSome())
+
+	var u256 [32]uint8
+	require.NoError(t, DecodeMoveJson("258", &u256))
+	require.Equal(t, [32]uint8{2, 1}, u256)
+
+	var small uint8
+	require.Error(t, DecodeMoveJson(256.0, &small))
+	require.Error(t, DecodeMoveJson("1", small))
+}
@@ -0,0 +1,169 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+// MoveObjectDecoder decodes the BCS contents of a Move object into a go value.
+type MoveObjectDecoder func(bcsBytes []byte) (any, error)
+
+// MoveStructRegistry maps Move struct types to decoders.
+//
+// A struct type can be registered with its type arguments, e.g. `0x2::coin::Coin<0x2::sui::SUI>`,
+// which only matches that instantiation, or without them, e.g. `0x2::coin::Coin`, which matches any
+// instantiation. The exact instantiation always wins. Addresses are compared in their short form,
+// so `0x2` and `0x000...02` are the same.
+type MoveStructRegistry struct {
+	lock     sync.RWMutex
+	decoders map[string]MoveObjectDecoder
+}
+
+func NewMoveStructRegistry() *MoveStructRegistry {
+	return &MoveStructRegistry{
+		decoders: make(map[string]MoveObjectDecoder),
+	}
+}
+
+// DefaultMoveStructRegistry has the decoders of the sui framework structs.
+var DefaultMoveStructRegistry = newDefaultMoveStructRegistry()
+
+func newDefaultMoveStructRegistry() *MoveStructRegistry {
+	r := NewMoveStructRegistry()
+	mustRegisterMoveStruct[sui_types.Coin](r, "0x2::coin::Coin")
+	mustRegisterMoveStruct[sui_types.TreasuryCap](r, "0x2::coin::TreasuryCap")
+	mustRegisterMoveStruct[sui_types.CoinMetadata](r, "0x2::coin::CoinMetadata")
+	mustRegisterMoveStruct[sui_types.StakedSui](r, "0x3::staking_pool::StakedSui")
+	return r
+}
+
+func mustRegisterMoveStruct[T any](r *MoveStructRegistry, structType string) {
+	if err := RegisterMoveStruct[T](r, structType); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterMoveStruct registers T as the BCS layout of structType, decoded values are of type *T.
+func RegisterMoveStruct[T any](r *MoveStructRegistry, structType string) error {
+	return r.Register(
+		structType, func(bcsBytes []byte) (any, error) {
+			value := new(T)
+			if err := lib.UnmarshalBCS(bcsBytes, value); err != nil {
+				return nil, err
+			}
+			return value, nil
+		},
+	)
+}
+
+func (r *MoveStructRegistry) Register(structType string, decoder MoveObjectDecoder) error {
+	key, err := NormalizeStructType(structType)
+	if err != nil {
+		return err
+	}
+	if decoder == nil {
+		return fmt.Errorf("nil decoder for move struct %s", structType)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.decoders[key] = decoder
+	return nil
+}
+
+// Lookup returns the decoder of the exact instantiation of structType, or that of its generic struct.
+func (r *MoveStructRegistry) Lookup(structType string) (MoveObjectDecoder, bool) {
+	key, err := NormalizeStructType(structType)
+	if err != nil {
+		return nil, false
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if decoder, ok := r.decoders[key]; ok {
+		return decoder, true
+	}
+	if idx := strings.Index(key, "<"); idx != -1 {
+		decoder, ok := r.decoders[key[:idx]]
+		return decoder, ok
+	}
+	return nil, false
+}
+
+func (r *MoveStructRegistry) Decode(structType string, bcsBytes []byte) (any, error) {
+	decoder, ok := r.Lookup(structType)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMoveStructNotRegistered, structType)
+	}
+	return decoder(bcsBytes)
+}
+
+// addressLiteral matches an address followed by `::` at the start of a type or type argument, not
+// the hex looking part of an identifier like `token0xabc`.
+var addressLiteral = regexp.MustCompile(`(^|[<,\s])0[xX][0-9a-fA-F]+::`)
+
+// NormalizeStructType removes whitespace and shortens every address in a Move struct type,
+// e.g. `0x0000...0002::coin::Coin< 0x2::sui::SUI >` becomes `0x2::coin::Coin<0x2::sui::SUI>`.
+func NormalizeStructType(structType string) (string, error) {
+	str := strings.Join(strings.Fields(structType), "")
+	base := str
+	if idx := strings.Index(str, "<"); idx != -1 {
+		if !strings.HasSuffix(str, ">") {
+			return "", fmt.Errorf("invalid move struct type %s", structType)
+		}
+		base = str[:idx]
+	}
+	if len(strings.Split(base, "::")) != 3 {
+		return "", fmt.Errorf("invalid move struct type %s", structType)
+	}
+	return addressLiteral.ReplaceAllStringFunc(
+		str, func(match string) string {
+			start := strings.IndexAny(match, "xX") - 1
+			short := strings.TrimLeft(match[start+2:len(match)-2], "0")
+			if short == "" {
+				short = "0"
+			}
+			return match[:start] + "0x" + strings.ToLower(short) + "::"
+		},
+	), nil
+}
+
+// MoveObjectBcs returns the raw BCS contents, the object must be queried with SuiObjectDataOptions.ShowBcs.
+func (r *SuiObjectResponse) MoveObjectBcs() (*SuiRawMoveObject, error) {
+	if r.Data == nil {
+		return nil, ErrObjectDataNotFound
+	}
+	if r.Data.Bcs == nil || r.Data.Bcs.Data.MoveObject == nil {
+		return nil, ErrNoMoveObjectBcs
+	}
+	return r.Data.Bcs.Data.MoveObject, nil
+}
+
+// DecodeMoveObject decodes the object contents with the decoder registered for its type,
+// DefaultMoveStructRegistry is used if registry is nil.
+func (r *SuiObjectResponse) DecodeMoveObject(registry *MoveStructRegistry) (any, error) {
+	raw, err := r.MoveObjectBcs()
+	if err != nil {
+		return nil, err
+	}
+	if registry == nil {
+		registry = DefaultMoveStructRegistry
+	}
+	return registry.Decode(raw.Type, raw.BcsBytes.Data())
+}
+
+// DecodeMoveObjectAs decodes the object contents into T without looking up a registry.
+func DecodeMoveObjectAs[T any](r *SuiObjectResponse) (*T, error) {
+	raw, err := r.MoveObjectBcs()
+	if err != nil {
+		return nil, err
+	}
+	value := new(T)
+	if err := lib.UnmarshalBCS(raw.BcsBytes.Data(), value); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", raw.Type, err)
+	}
+	return value, nil
+}
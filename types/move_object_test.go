@@ -0,0 +1,133 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+	"github.com/thorli9527/sui-wallet-sdk/move_types"
+	"github.com/thorli9527/sui-wallet-sdk/sui_types"
+)
+
+func objectResponseWithBcs(t *testing.T, structType string, value any) *SuiObjectResponse {
+	bcsBytes, err := bcs.Marshal(value)
+	require.NoError(t, err)
+	jsonData := fmt.Sprintf(
+		`{"data":{"objectId":"0x5","version":"3","digest":"HvbE2UZny6cP4KukaXetmj4jjpKTDTjVo23XEcu7VgSn",`+
+			`"bcs":{"dataType":"moveObject","type":"%s","hasPublicTransfer":true,"version":3,"bcsBytes":"%s"}}}`,
+		structType, lib.Base64Data(bcsBytes).String(),
+	)
+	var resp SuiObjectResponse
+	err = json.Unmarshal([]byte(jsonData), &resp)
+	require.NoError(t, err)
+	return &resp
+}
+
+func TestNormalizeStructType(t *testing.T) {
+	tests := []struct {
+		str     string
+		want    string
+		wantErr bool
+	}{
+		{str: "0x2::coin::Coin", want: "0x2::coin::Coin"},
+		{
+			str:  "0x0000000000000000000000000000000000000000000000000000000000000002::coin::Coin< 0x02::sui::SUI >",
+			want: "0x2::coin::Coin<0x2::sui::SUI>",
+		},
+		{str: "0x00::m::S<u64, vector<0xAB::m::T>>", want: "0x0::m::S<u64,vector<0xab::m::T>>"},
+		{str: "0x02::token0xabc::Token0x0F<0x0a::m::S0x00>", want: "0x2::token0xabc::Token0x0F<0xa::m::S0x00>"},
+		{str: "0x2::coin", wantErr: true},
+		{str: "0x2::coin::Coin<0x2::sui::SUI", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeStructType(tt.str)
+		if tt.wantErr {
+			require.Error(t, err, tt.str)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got)
+	}
+}
+
+func TestSuiObjectResponse_DecodeMoveObject(t *testing.T) {
+	id := AddressFromHex(t, "0x5")
+	coin := sui_types.Coin{
+		Id:      sui_types.UID{Id: sui_types.ID{Bytes: *id}},
+		Balance: sui_types.Balance{Value: 1000},
+	}
+	resp := objectResponseWithBcs(t, "0x2::coin::Coin<0x2::sui::SUI>", coin)
+	value, err := resp.DecodeMoveObject(nil)
+	require.NoError(t, err)
+	require.Equal(t, &coin, value)
+	require.Equal(t, uint64(1000), value.(*sui_types.Coin).Value())
+	require.Equal(t, *id, value.(*sui_types.Coin).Id.ObjectID())
+
+	staked := sui_types.StakedSui{
+		Id:                   sui_types.UID{Id: sui_types.ID{Bytes: *id}},
+		PoolId:               sui_types.ID{Bytes: *AddressFromHex(t, "0x77")},
+		StakeActivationEpoch: 12,
+		Principal:            sui_types.Balance{Value: 1e9},
+	}
+	resp = objectResponseWithBcs(
+		t, "0x0000000000000000000000000000000000000000000000000000000000000003::staking_pool::StakedSui", staked,
+	)
+	value, err = resp.DecodeMoveObject(nil)
+	require.NoError(t, err)
+	require.Equal(t, &staked, value)
+
+	_, err = objectResponseWithBcs(t, "0x9::m::Unknown", coin).DecodeMoveObject(nil)
+	require.ErrorIs(t, err, ErrMoveStructNotRegistered)
+
+	_, err = (&SuiObjectResponse{Data: &SuiObjectData{}}).DecodeMoveObject(nil)
+	require.ErrorIs(t, err, ErrNoMoveObjectBcs)
+}
+
+func TestMoveStructRegistry_Generic(t *testing.T) {
+	type Field struct {
+		Id    sui_types.UID
+		Name  string
+		Value sui_types.VecMap[string, move_types.Option[uint64]]
+	}
+	type SpecialField struct {
+		Id sui_types.UID
+	}
+	registry := NewMoveStructRegistry()
+	require.NoError(t, RegisterMoveStruct[Field](registry, "0x2::dynamic_field::Field"))
+	require.NoError(t, RegisterMoveStruct[SpecialField](registry, "0x2::dynamic_field::Field<u8, bool>"))
+
+	field := Field{
+		Name: "prices",
+		Value: sui_types.VecMap[string, move_types.Option[uint64]]{
+			Contents: []sui_types.Entry[string, move_types.Option[uint64]]{
+				{Key: "a", Value: move_types.Some(uint64(5))},
+				{Key: "b", Value: move_types.None[uint64]()},
+			},
+		},
+	}
+	resp := objectResponseWithBcs(t, "0x2::dynamic_field::Field<0x1::string::String, u64>", field)
+	value, err := resp.DecodeMoveObject(registry)
+	require.NoError(t, err)
+	decoded := value.(*Field)
+	require.Equal(t, "prices", decoded.Name)
+	a, ok := decoded.Value.Get(func(k string) bool { return k == "a" })
+	require.True(t, ok)
+	price, ok := a.Get()
+	require.True(t, ok)
+	require.Equal(t, uint64(5), price)
+	b, ok := decoded.Value.Get(func(k string) bool { return k == "b" })
+	require.True(t, ok)
+	require.False(t, b.IsSome())
+
+	resp = objectResponseWithBcs(t, "0x2::dynamic_field::Field<u8,bool>", SpecialField{})
+	value, err = resp.DecodeMoveObject(registry)
+	require.NoError(t, err)
+	require.IsType(t, &SpecialField{}, value)
+
+	direct, err := DecodeMoveObjectAs[sui_types.Coin](objectResponseWithBcs(t, "0x2::coin::Coin<0x2::sui::SUI>", sui_types.Coin{}))
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), direct.Value())
+}
@@ -0,0 +1,205 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/thorli9527/sui-wallet-sdk/lib"
+)
+
+type SuiMoveAbility string
+
+const (
+	SuiMoveAbilityCopy  SuiMoveAbility = "Copy"
+	SuiMoveAbilityDrop  SuiMoveAbility = "Drop"
+	SuiMoveAbilityStore SuiMoveAbility = "Store"
+	SuiMoveAbilityKey   SuiMoveAbility = "Key"
+)
+
+type SuiMoveAbilitySet struct {
+	Abilities []SuiMoveAbility `json:"abilities"`
+}
+
+func (s SuiMoveAbilitySet) Has(ability SuiMoveAbility) bool {
+	for _, a := range s.Abilities {
+		if a == ability {
+			return true
+		}
+	}
+	return false
+}
+
+type SuiMoveVisibility string
+
+const (
+	SuiMoveVisibilityPrivate SuiMoveVisibility = "Private"
+	SuiMoveVisibilityPublic  SuiMoveVisibility = "Public"
+	SuiMoveVisibilityFriend  SuiMoveVisibility = "Friend"
+)
+
+type SuiMoveModuleId struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+}
+
+type SuiMoveNormalizedModule struct {
+	FileFormatVersion uint32                               `json:"fileFormatVersion"`
+	Address           string                               `json:"address"`
+	Name              string                               `json:"name"`
+	Friends           []SuiMoveModuleId                    `json:"friends"`
+	Structs           map[string]SuiMoveNormalizedStruct   `json:"structs"`
+	ExposedFunctions  map[string]SuiMoveNormalizedFunction `json:"exposedFunctions"`
+}
+
+type SuiMoveStructTypeParameter struct {
+	Constraints SuiMoveAbilitySet `json:"constraints"`
+	IsPhantom   bool              `json:"isPhantom"`
+}
+
+type SuiMoveNormalizedField struct {
+	Name string                `json:"name"`
+	Type SuiMoveNormalizedType `json:"type"`
+}
+
+type SuiMoveNormalizedStruct struct {
+	Abilities      SuiMoveAbilitySet            `json:"abilities"`
+	TypeParameters []SuiMoveStructTypeParameter `json:"typeParameters"`
+	Fields         []SuiMoveNormalizedField     `json:"fields"`
+}
+
+type SuiMoveNormalizedFunction struct {
+	Visibility     SuiMoveVisibility       `json:"visibility"`
+	IsEntry        bool                    `json:"isEntry"`
+	TypeParameters []SuiMoveAbilitySet     `json:"typeParameters"`
+	Parameters     []SuiMoveNormalizedType `json:"parameters"`
+	Return         []SuiMoveNormalizedType `json:"return"`
+}
+
+// Primitive names of SuiMoveNormalizedType
+const (
+	SuiMoveNormalizedTypeBool    = "Bool"
+	SuiMoveNormalizedTypeU8      = "U8"
+	SuiMoveNormalizedTypeU16     = "U16"
+	SuiMoveNormalizedTypeU32     = "U32"
+	SuiMoveNormalizedTypeU64     = "U64"
+	SuiMoveNormalizedTypeU128    = "U128"
+	SuiMoveNormalizedTypeU256    = "U256"
+	SuiMoveNormalizedTypeAddress = "Address"
+	SuiMoveNormalizedTypeSigner  = "Signer"
+)
+
+type SuiMoveNormalizedStructType struct {
+	Address       string                  `json:"address"`
+	Module        string                  `json:"module"`
+	Name          string                  `json:"name"`
+	TypeArguments []SuiMoveNormalizedType `json:"typeArguments"`
+}
+
+// SuiMoveNormalizedType is either a primitive, which is json encoded as its name, or exactly
+// one of the other variants.
+type SuiMoveNormalizedType struct {
+	Primitive string
+
+	Struct           *SuiMoveNormalizedStructType
+	Vector           *SuiMoveNormalizedType
+	TypeParameter    *uint16
+	Reference        *SuiMoveNormalizedType
+	MutableReference *SuiMoveNormalizedType
+}
+
+// suiMoveNormalizedTypeVariants has the json layout of the non primitive variants
+type suiMoveNormalizedTypeVariants struct {
+	Struct           *SuiMoveNormalizedStructType `json:"Struct,omitempty"`
+	Vector           *SuiMoveNormalizedType       `json:"Vector,omitempty"`
+	TypeParameter    *uint16                      `json:"TypeParameter,omitempty"`
+	Reference        *SuiMoveNormalizedType       `json:"Reference,omitempty"`
+	MutableReference *SuiMoveNormalizedType       `json:"MutableReference,omitempty"`
+}
+
+func (t *SuiMoveNormalizedType) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*t = SuiMoveNormalizedType{}
+		return json.Unmarshal(data, &t.Primitive)
+	}
+	var variants suiMoveNormalizedTypeVariants
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return err
+	}
+	*t = SuiMoveNormalizedType{
+		Struct:           variants.Struct,
+		Vector:           variants.Vector,
+		TypeParameter:    variants.TypeParameter,
+		Reference:        variants.Reference,
+		MutableReference: variants.MutableReference,
+	}
+	if t.Struct == nil && t.Vector == nil && t.TypeParameter == nil && t.Reference == nil && t.MutableReference == nil {
+		return fmt.Errorf("unknown move normalized type [%s]", string(data))
+	}
+	return nil
+}
+
+func (t SuiMoveNormalizedType) MarshalJSON() ([]byte, error) {
+	if t.Primitive != "" {
+		return json.Marshal(t.Primitive)
+	}
+	return json.Marshal(
+		suiMoveNormalizedTypeVariants{
+			Struct:           t.Struct,
+			Vector:           t.Vector,
+			TypeParameter:    t.TypeParameter,
+			Reference:        t.Reference,
+			MutableReference: t.MutableReference,
+		},
+	)
+}
+
+// String formats the type in move syntax, type parameters are named T0, T1...
+func (t SuiMoveNormalizedType) String() string {
+	switch {
+	case t.Primitive != "":
+		return strings.ToLower(t.Primitive)
+	case t.Struct != nil:
+		name := fmt.Sprintf("%v::%v::%v", t.Struct.Address, t.Struct.Module, t.Struct.Name)
+		if len(t.Struct.TypeArguments) == 0 {
+			return name
+		}
+		args := make([]string, len(t.Struct.TypeArguments))
+		for i, arg := range t.Struct.TypeArguments {
+			args[i] = arg.String()
+		}
+		return name + "<" + strings.Join(args, ", ") + ">"
+	case t.Vector != nil:
+		return "vector<" + t.Vector.String() + ">"
+	case t.TypeParameter != nil:
+		return fmt.Sprintf("T%d", *t.TypeParameter)
+	case t.Reference != nil:
+		return "&" + t.Reference.String()
+	case t.MutableReference != nil:
+		return "&mut " + t.MutableReference.String()
+	}
+	return ""
+}
+
+type ObjectValueKind string
+
+const (
+	ObjectValueKindByImmutableReference ObjectValueKind = "ByImmutableReference"
+	ObjectValueKindByMutableReference   ObjectValueKind = "ByMutableReference"
+	ObjectValueKindByValue              ObjectValueKind = "ByValue"
+)
+
+type MoveFunctionArgType = lib.TagJson[MoveFunctionArgTypeVariants]
+
+type MoveFunctionArgTypeVariants struct {
+	Pure   *struct{}        `json:"Pure,omitempty"`
+	Object *ObjectValueKind `json:"Object,omitempty"`
+}
+
+func (m MoveFunctionArgTypeVariants) Tag() string {
+	return ""
+}
+
+func (m MoveFunctionArgTypeVariants) Content() string {
+	return ""
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuiMoveNormalizedFunction_UnmarshalJSON(t *testing.T) {
+	data := `{
+		"visibility": "Public",
+		"isEntry": false,
+		"typeParameters": [{"abilities": []}],
+		"parameters": [
+			{"MutableReference": {"Struct": {"address": "0x2", "module": "coin", "name": "Coin", "typeArguments": [{"TypeParameter": 0}]}}},
+			"U64",
+			{"Vector": "Address"},
+			{"MutableReference": {"Struct": {"address": "0x2", "module": "tx_context", "name": "TxContext", "typeArguments": []}}}
+		],
+		"return": [{"Struct": {"address": "0x2", "module": "coin", "name": "Coin", "typeArguments": [{"TypeParameter": 0}]}}]
+	}`
+	var function SuiMoveNormalizedFunction
+	require.NoError(t, json.Unmarshal([]byte(data), &function))
+	require.Equal(t, SuiMoveVisibilityPublic, function.Visibility)
+	require.Len(t, function.Parameters, 4)
+	require.Equal(t, "&mut 0x2::coin::Coin<T0>", function.Parameters[0].String())
+	require.Equal(t, SuiMoveNormalizedTypeU64, function.Parameters[1].Primitive)
+	require.Equal(t, "vector<address>", function.Parameters[2].String())
+	require.Equal(t, "0x2::coin::Coin<T0>", function.Return[0].String())
+
+	encoded, err := json.Marshal(function)
+	require.NoError(t, err)
+	require.JSONEq(t, data, string(encoded))
+
+	require.Error(t, json.Unmarshal([]byte(`{"Unknown": 1}`), &SuiMoveNormalizedType{}))
+}
+
+func TestSuiMoveNormalizedStruct_UnmarshalJSON(t *testing.T) {
+	data := `{
+		"abilities": {"abilities": ["Store", "Key"]},
+		"typeParameters": [{"constraints": {"abilities": []}, "isPhantom": true}],
+		"fields": [
+			{"name": "id", "type": {"Struct": {"address": "0x2", "module": "object", "name": "UID", "typeArguments": []}}},
+			{"name": "balance", "type": {"Struct": {"address": "0x2", "module": "balance", "name": "Balance", "typeArguments": [{"TypeParameter": 0}]}}}
+		]
+	}`
+	var normalized SuiMoveNormalizedStruct
+	require.NoError(t, json.Unmarshal([]byte(data), &normalized))
+	require.True(t, normalized.Abilities.Has(SuiMoveAbilityKey))
+	require.False(t, normalized.Abilities.Has(SuiMoveAbilityDrop))
+	require.True(t, normalized.TypeParameters[0].IsPhantom)
+	require.Equal(t, "balance", normalized.Fields[1].Name)
+	require.Equal(t, "0x2::balance::Balance<T0>", normalized.Fields[1].Type.String())
+}
+
+func TestMoveFunctionArgType_UnmarshalJSON(t *testing.T) {
+	var argTypes []MoveFunctionArgType
+	require.NoError(t, json.Unmarshal([]byte(`["Pure", {"Object": "ByMutableReference"}]`), &argTypes))
+	require.NotNil(t, argTypes[0].Data.Pure)
+	require.Nil(t, argTypes[0].Data.Object)
+	require.Nil(t, argTypes[1].Data.Pure)
+	require.Equal(t, ObjectValueKindByMutableReference, *argTypes[1].Data.Object)
+}
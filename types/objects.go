@@ -199,6 +199,11 @@ type SuiObjectResponseQuery struct {
 
 type SuiPastObjectResponse = lib.TagJson[SuiPastObject]
 
+type SuiGetPastObjectRequest struct {
+	ObjectId sui_types.ObjectID                      `json:"objectId"`
+	Version  SafeSuiBigInt[sui_types.SequenceNumber] `json:"version"`
+}
+
 // TODO need test VersionNotFound
 type SuiPastObject struct {
 	/// The object exists and is found with this version
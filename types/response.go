@@ -24,6 +24,7 @@ type ExecuteTransactionEffects struct {
 	AuthSignInfo *AuthSignInfo                           `json:"authSignInfo"`
 }
 
+// Deprecated: ExecuteTransactionBlock returns a SuiTransactionBlockResponse.
 type ExecuteTransactionResponse struct {
 	Certificate CertifiedTransaction      `json:"certificate"`
 	Effects     ExecuteTransactionEffects `json:"effects"`
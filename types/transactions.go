@@ -106,6 +106,14 @@ func (t SuiTransactionBlockEffects) IsSuccess() bool {
 	return t.V1.Status.Status == ExecutionStatusSuccess
 }
 
+// Err returns the *ExecutionError of failed effects, like those of a dry run, nil if they succeeded.
+func (t SuiTransactionBlockEffects) Err() error {
+	if t.V1 == nil || t.V1.Status.Status != ExecutionStatusFailure {
+		return nil
+	}
+	return newExecutionError(t.V1.TransactionDigest, t.V1.Status.Error)
+}
+
 const (
 	SuiTransactionBlockKindSuiChangeEpoch             = "ChangeEpoch"
 	SuiTransactionBlockKindSuiConsensusCommitPrologue = "ConsensusCommitPrologue"
@@ -266,6 +274,21 @@ type SuiTransactionBlockResponse struct {
 	Errors []string `json:"errors,omitempty"`
 }
 
+// ExecutionStatus returns the status of the effects, nil when the effects were not requested.
+func (r *SuiTransactionBlockResponse) ExecutionStatus() *ExecutionStatus {
+	if r.Effects == nil || r.Effects.Data.V1 == nil {
+		return nil
+	}
+	return &r.Effects.Data.V1.Status
+}
+
+// Failed reports whether the transaction was executed with a failure status, its gas is charged
+// but none of its other changes are applied.
+func (r *SuiTransactionBlockResponse) Failed() bool {
+	status := r.ExecutionStatus()
+	return status != nil && status.Status == ExecutionStatusFailure
+}
+
 type ReturnValueType interface{}
 type MutableReferenceOutputType interface{}
 type ExecutionResultType struct {